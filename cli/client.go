@@ -2,13 +2,14 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"parkingSlotManagement/cmd/app"
 	"parkingSlotManagement/internals/adapters/repositories/mysql"
 	"parkingSlotManagement/internals/core/domain"
-	"parkingSlotManagement/internals/core/services/auth"
-	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/core/services/currency"
 	"strconv"
 	"strings"
 	"time"
@@ -16,30 +17,39 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// menuPermissions is what each menu action needs, the same as its route.
+var menuPermissions = map[string]domain.Permission{
+	"1": domain.PermParkingOperate,
+	"2": domain.PermParkingOperate,
+	"3": domain.PermParkingOperate,
+	"4": domain.PermSlotsManage,
+	"5": domain.PermParkingOperate,
+}
+
 func main() {
 	err := godotenv.Load("../.env")
 	if err != nil {
 		log.Fatalf("Error loading .env file: %v", err)
 	}
 
-	//if we use inmemmory instance
-	// slotRepo := inmemmory.NewSlotInMemmory()
-	// ticketRepo := inmemmory.NewTicketInMemmory()
-
+	// The CLI is wired like the server, so what it does is audited and
+	// its events reach the server's outbox relay.
 	database := mysql.GetInstance()
-	slotRepo := mysql.NewSlotRepo(database)
-	ticketRepo := mysql.NewTicketRepo(database)
+	auditLog, closeAudit := app.OpenAuditLog(database)
+	defer closeAudit()
+	service := app.NewParkingService(database, auditLog)
+	authService := app.NewAuthService(database, auditLog)
+	lot := service.Lot
 
-	service := parking.NewParkingService(slotRepo, ticketRepo)
-
-	authService := auth.NewAuthService()
+	format := func(m domain.Money) string { return currency.Format(m, lot.Locale) }
 
 	// in inmemmory save few slots already
-	// slotRepo.SaveSlot(domain.Slot{SlotId: 1, SlotType: "car", IsFree: true})
-	// slotRepo.SaveSlot(domain.Slot{SlotId: 2, SlotType: "car", IsFree: true})
-	// slotRepo.SaveSlot(domain.Slot{SlotId: 3, SlotType: "bike", IsFree: true})
-	// slotRepo.SaveSlot(domain.Slot{SlotId: 4, SlotType: "bike", IsFree: true})
+	// service.SlotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true})
+	// service.SlotRepo.SaveSlot(ctx, domain.Slot{SlotId: 2, SlotType: "car", IsFree: true})
+	// service.SlotRepo.SaveSlot(ctx, domain.Slot{SlotId: 3, SlotType: "bike", IsFree: true})
+	// service.SlotRepo.SaveSlot(ctx, domain.Slot{SlotId: 4, SlotType: "bike", IsFree: true})
 
+	ctx := context.Background()
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Println("Welcome to Parking Lot Management System ")
 	time.Sleep(500 * time.Millisecond)
@@ -47,6 +57,7 @@ func main() {
 	// adminUsername := os.Getenv("ADMIN_USERNAME")
 	// adminPassword := os.Getenv("ADMIN_PASSWORD")
 
+	var user *domain.User
 	for {
 		fmt.Print("Enter username: ")
 		username, _ := reader.ReadString('\n')
@@ -56,14 +67,22 @@ func main() {
 		password, _ := reader.ReadString('\n')
 		password = strings.TrimSpace(password)
 
-		token, err := authService.Login(username, password)
+		tokens, err := authService.Login(ctx, username, password)
 		if err != nil {
 			fmt.Println("Invalid credentials. Please try again.")
 			continue
 		}
 
+		user, err = authService.ValidateToken(ctx, tokens.AccessToken)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			continue
+		}
+		// Everything done from the menu is recorded against this user.
+		ctx = domain.WithUser(ctx, user)
+
 		fmt.Println("Login successful!")
-		fmt.Printf("Your token: %s\n", token)
+		fmt.Printf("Your token: %s\n", tokens.AccessToken)
 		break
 	}
 
@@ -73,17 +92,26 @@ func main() {
 		fmt.Println("2. Unpark Vehicle")
 		fmt.Println("3. View Available Slots")
 		fmt.Println("4. Add Slot")
-		fmt.Println("5. Exit")
+		fmt.Println("5. Settle Unpaid Balance")
+		fmt.Println("6. Exit")
 		fmt.Print("Enter your choice: ")
 
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(choice)
+		if permission, ok := menuPermissions[choice]; ok && !user.Can(permission) {
+			fmt.Println("You are not allowed to do that.")
+			continue
+		}
 
 		switch choice {
 		case "1":
 			fmt.Print("Enter vehicle number: ")
 			number, _ := reader.ReadString('\n')
-			number = strings.TrimSpace(number)
+			number, err := service.NormaliseVehicleNumber(number)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
 			time.Sleep(500 * time.Millisecond)
 			fmt.Print("Enter vehicle type (car/bike): ")
 			vtype, _ := reader.ReadString('\n')
@@ -94,7 +122,7 @@ func main() {
 				continue
 			}
 
-			ticket, err := service.ParkVehicle(domain.Vehicle{
+			ticket, err := service.ParkVehicle(ctx, domain.Vehicle{
 				VehicleNumber: number,
 				VehicleType:   vtype,
 			})
@@ -107,24 +135,56 @@ func main() {
 				fmt.Printf("Vehicle Number: %s\n", ticket.VehicleNumber)
 				fmt.Printf("Entry Time: %s\n", ticket.EntryTime.Format("2006-01-02 15:04:05"))
 				fmt.Printf("Slot ID: %d\n", ticket.SlotId)
+				if ticket.OutstandingBalance.IsPositive() {
+					fmt.Printf("Outstanding Balance: %s\n", format(ticket.OutstandingBalance))
+				}
 
 			}
 
 		case "2":
 			fmt.Print("Enter vehicle number: ")
 			number, _ := reader.ReadString('\n')
-			number = strings.TrimSpace(number)
+			number, err := service.NormaliseVehicleNumber(number)
+			if err != nil {
+				fmt.Printf(" Error: %v\n", err)
+				continue
+			}
+
+			quote, err := service.QuoteExit(ctx, number)
+			if err != nil {
+				fmt.Printf(" Error: %v\n", err)
+				continue
+			}
+			fmt.Printf(" Fee due: %s\n", format(quote.Fee))
+
+			payment := domain.PaymentRequest{Method: domain.PaymentCash}
+			if quote.Fee.IsPositive() {
+				fmt.Print("Enter payment method (cash/card): ")
+				method, _ := reader.ReadString('\n')
+				payment.Method = strings.TrimSpace(strings.ToLower(method))
+				if payment.Method == domain.PaymentCard {
+					fmt.Print("Enter card token: ")
+					token, _ := reader.ReadString('\n')
+					payment.CardToken = strings.TrimSpace(token)
+				}
+			}
 
-			fee, err := service.UnparkVehicle(number)
+			ticket, err := service.UnparkVehicle(ctx, number, payment)
 			if err != nil {
 				fmt.Printf(" Error: %v\n", err)
 			} else {
 				time.Sleep(500 * time.Millisecond)
-				fmt.Printf(" Vehicle unparked. Fee: ₹%.2f\n", fee)
+				fmt.Printf(" Vehicle unparked. Fee: %s\n", format(ticket.Fee))
+				for _, line := range ticket.TaxLines {
+					fmt.Printf("   incl. %s @ %.2f%%: %s\n", line.Name, line.Rate*100, format(line.Amount))
+				}
+				if ticket.PaymentReference != "" {
+					fmt.Printf(" Payment reference: %s\n", ticket.PaymentReference)
+				}
 			}
 
 		case "3":
-			slots, err := service.GetAvailableSlots()
+			slots, err := service.GetAvailableSlots(ctx)
 			if err != nil {
 				fmt.Printf("Error fetching slots: %v\n", err)
 			} else {
@@ -154,7 +214,7 @@ func main() {
 				continue
 			}
 
-			err = service.AddSlot(domain.Slot{
+			err = service.AddSlot(ctx, domain.Slot{
 				SlotId:   slotID,
 				SlotType: slotType,
 				IsFree:   true,
@@ -166,6 +226,33 @@ func main() {
 			}
 
 		case "5":
+			fmt.Print("Enter vehicle number: ")
+			number, _ := reader.ReadString('\n')
+			balance, err := service.GetUnpaidBalance(ctx, number)
+			if err != nil {
+				fmt.Printf(" Error: %v\n", err)
+				continue
+			}
+			fmt.Printf(" Outstanding balance: %s\n", format(balance))
+			if !balance.IsPositive() {
+				continue
+			}
+
+			fmt.Print("Enter amount to settle: ")
+			amountStr, _ := reader.ReadString('\n')
+			amount, err := domain.ParseMoney(amountStr, lot.Currency)
+			if err != nil {
+				fmt.Println("Invalid amount. Please enter an amount such as 50.")
+				continue
+			}
+			remaining, err := service.SettleBalance(ctx, number, amount, domain.PaymentRequest{Method: domain.PaymentCash})
+			if err != nil {
+				fmt.Printf(" Error: %v\n", err)
+			} else {
+				fmt.Printf(" Balance settled. Remaining: %s\n", format(remaining))
+			}
+
+		case "6":
 			fmt.Println("Thank you for using the Parking Lot System!")
 			return
 
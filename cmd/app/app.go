@@ -1,13 +1,25 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"parkingSlotManagement/internals/adapters/graphqlHandlers"
+	"parkingSlotManagement/internals/adapters/grpcHandlers"
 	"parkingSlotManagement/internals/adapters/repositories/mysql"
 	"parkingSlotManagement/internals/adapters/requestHandlers"
 	"parkingSlotManagement/internals/adapters/requestHandlers/middleware"
-	"parkingSlotManagement/internals/core/services/auth"
-	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/services/audit"
+	"parkingSlotManagement/internals/core/services/availability"
+	"parkingSlotManagement/internals/core/services/events"
+	"parkingSlotManagement/internals/core/services/outbox"
+	"parkingSlotManagement/internals/core/services/webhook"
+	"parkingSlotManagement/internals/ports"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -20,29 +32,85 @@ func Start() {
 	}
 	database := mysql.GetInstance()
 
-	SlotRepo := mysql.NewSlotRepo(database)
-	TicketRepo := mysql.NewTicketRepo(database)
+	AuditRepo, closeAudit := OpenAuditLog(database)
+	defer closeAudit()
+	ParkingService := NewParkingService(database, AuditRepo)
+	AuthService := NewAuthService(database, AuditRepo)
 
 	//InMemmory
-	// SlotRepo := inmemmory.NewSlotInMemmory()
-	// TicketRepo := inmemmory.NewTicketInMemmory()
+	// WebhookRepo := inmemmory.NewWebhookInMemmory()
+	// OutboxRepo := inmemmory.NewOutboxInMemmory()
+	WebhookRepo := mysql.NewWebhookRepo(database)
+	OutboxRepo := mysql.NewOutboxRepo(database)
 
-	ParkingService := parking.NewParkingService(SlotRepo, TicketRepo)
-	AuthService := auth.NewAuthService()
+	EventBus := events.NewBus()
+	AvailabilityFeed := availability.NewFeed(ParkingService, EventBus)
+	WebhookService := webhook.NewService(WebhookRepo)
+	WebhookService.AuditLog = AuditRepo
+	WebhookService.AllowPrivateTargets = os.Getenv("WEBHOOK_ALLOW_PRIVATE_TARGETS") == "true"
+	publisher, err := outboxPublisher(os.Getenv("OUTBOX_PUBLISHERS"), EventBus, WebhookService)
+	if err != nil {
+		log.Fatalf("invalid OUTBOX_PUBLISHERS: %v", err)
+	}
+	Relay := outbox.NewRelay(OutboxRepo, publisher)
+	Relay.Transactor = mysql.NewTransactor(database)
+	ParkingService.Events = Relay
 	handler := requestHandlers.NewHandlers(ParkingService)
-
-	loginHandler := requestHandlers.LoginHandler(AuthService)
+	userHandler := requestHandlers.NewUserHandlers(AuthService)
+	auditHandler := requestHandlers.NewAuditHandlers(audit.NewService(AuditRepo))
+	availabilityHandler := requestHandlers.NewAvailabilityHandlers(ParkingService, AvailabilityFeed)
+	webhookHandler := requestHandlers.NewWebhookHandlers(WebhookService)
 
 	r := mux.NewRouter()
+	r.Use(middleware.RequestID)
+	r.NotFoundHandler = problem.NotFound
+	r.MethodNotAllowedHandler = problem.MethodNotAllowed
+	registerRoutes(r, handler, userHandler, auditHandler, availabilityHandler, webhookHandler, graphqlHandlers.NewHandler(ParkingService), AuthService)
+	registerLegacyRoutes(r, handler, userHandler, auditHandler, AuthService)
 
-	r.HandleFunc("/login", loginHandler).Methods(http.MethodPost)
-
+	grpcAddr := os.Getenv("GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = ":9090"
+	}
+	listener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatalf("failed to listen for gRPC on %s: %v", grpcAddr, err)
+	}
+	grpcServer := grpcHandlers.NewGRPCServer(grpcHandlers.NewServer(ParkingService, AvailabilityFeed), AuthService)
+	go func() {
+		log.Println("gRPC server running on", grpcAddr)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
 
-	r.HandleFunc("/ParkVehicle", middleware.AuthMiddleware(handler.ParkVehicleRequest, AuthService)).Methods(http.MethodPost)
-	r.HandleFunc("/UnparkVehicle", middleware.AuthMiddleware(handler.UnparkVehicleRequest, AuthService)).Methods(http.MethodPost)
-	r.HandleFunc("/AddSlot", middleware.AuthMiddleware(handler.AddSlot, AuthService)).Methods(http.MethodPost)
-	r.HandleFunc("/GetAvailableSlots", middleware.AuthMiddleware(handler.GetAvailableSlots, AuthService)).Methods(http.MethodPost)
+	go WebhookService.Run(context.Background())
+	go Relay.Run(context.Background())
 
 	log.Println("Server running on:8080")
 	http.ListenAndServe(":8080", r)
 }
+
+// outboxPublisher builds what the outbox relay sends events to from a
+// comma-separated list of "log", "bus" and "webhook". The default is
+// "bus,webhook".
+func outboxPublisher(names string, bus *events.Bus, webhooks *webhook.Service) (ports.OutboxPublisher, error) {
+	if names == "" {
+		names = "bus,webhook"
+	}
+	var publishers outbox.Publishers
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			publishers = append(publishers, outbox.NewLogPublisher(log.Default()))
+		case "bus":
+			// Live availability clients can't drop repeats themselves.
+			publishers = append(publishers, outbox.NewDedupe(outbox.Broker{EventPublisher: bus}, 1000))
+		case "webhook":
+			publishers = append(publishers, webhooks)
+		default:
+			return nil, fmt.Errorf("unknown publisher %q", name)
+		}
+	}
+	return publishers, nil
+}
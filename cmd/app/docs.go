@@ -0,0 +1,45 @@
+package app
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"log"
+	"net/http"
+	"regexp"
+)
+
+// spec is the OpenAPI document for every route registered in routes.go.
+// openapi_test.go fails when the two drift apart.
+//
+//go:embed openapi.json
+var spec []byte
+
+//go:embed docs.html
+var docsPage string
+
+// integrityPattern matches a subresource integrity value: the hash
+// algorithm, a dash and the base64 digest.
+var integrityPattern = regexp.MustCompile(`^sha(256|384|512)-[A-Za-z0-9+/]+={0,2}$`)
+
+func serveSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
+
+// docsHandler renders the spec with Redoc, loaded from its CDN. The page
+// pins a Redoc release so that a new one can't change it unseen. integrity,
+// when set, is the release's subresource integrity hash ("sha384-..."), so
+// that the browser also refuses a copy the CDN altered. It stops the program
+// on a malformed hash, as Start does for other settings.
+func docsHandler(integrity string) http.HandlerFunc {
+	if integrity != "" && !integrityPattern.MatchString(integrity) {
+		log.Fatalf("invalid REDOC_INTEGRITY %q", integrity)
+	}
+	var page bytes.Buffer
+	template.Must(template.New("docs").Parse(docsPage)).Execute(&page, struct{ Integrity string }{integrity})
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page.Bytes())
+	}
+}
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"parkingSlotManagement/internals/core/domain"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+)
+
+func init() {
+	// The docs page is checked for its status and content type only.
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.PlainBodyDecoder)
+}
+
+func loadSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		t.Fatalf("openapi.json does not parse: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Fatalf("openapi.json is not a valid OpenAPI document: %v", err)
+	}
+	// Match on paths alone; the test requests have no real host.
+	doc.Servers = nil
+	return doc
+}
+
+// checkedRouter is newTestRouter with every response checked against the
+// operation the spec documents for it, so a handler that changes the shape
+// of its response fails whichever test exercises it.
+func checkedRouter(t *testing.T) http.Handler {
+	doc := loadSpec(t)
+	specRouter, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("Failed to route the spec: %v", err)
+	}
+	next := newTestRouter(t)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := httptest.NewRecorder()
+		next.ServeHTTP(resp, r)
+		for k, v := range resp.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.Code)
+		w.Write(resp.Body.Bytes())
+
+		route, params, err := specRouter.FindRoute(r)
+		if err == routers.ErrPathNotFound || err == routers.ErrMethodNotAllowed {
+			// Unknown routes are covered by TestRoutesMatchSpec; their
+			// problem bodies are checked by TestErrorsAreProblemDetails.
+			return
+		}
+		if err != nil {
+			t.Errorf("%s %s: %v", r.Method, r.URL.Path, err)
+			return
+		}
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: &openapi3filter.RequestValidationInput{Request: r, PathParams: params, Route: route},
+			Status:                 resp.Code,
+			Header:                 resp.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(resp.Body.Bytes())),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+		})
+		if err != nil {
+			t.Errorf("%s %s answered %d, which the spec does not describe: %v", r.Method, r.URL.Path, resp.Code, err)
+		}
+	})
+}
+
+var pathVariable = regexp.MustCompile(`\{(\w+):[^}]+\}`)
+
+func TestRoutesMatchSpec(t *testing.T) {
+	doc := loadSpec(t)
+	documented := map[string]bool{}
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+path] = true
+		}
+	}
+
+	registered := map[string]bool{}
+	newTestRouter(t).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		for _, method := range methods {
+			registered[method+" "+pathVariable.ReplaceAllString(path, "{$1}")] = true
+		}
+		return nil
+	})
+
+	var missing, stale []string
+	for route := range registered {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			stale = append(stale, route)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	if len(missing) > 0 {
+		t.Errorf("Routes missing from openapi.json:\n%s", strings.Join(missing, "\n"))
+	}
+	if len(stale) > 0 {
+		t.Errorf("openapi.json documents routes that are not registered:\n%s", strings.Join(stale, "\n"))
+	}
+}
+
+func TestDocsPinRedoc(t *testing.T) {
+	if strings.Contains(docsPage, "/latest/") || !strings.Contains(docsPage, "/redoc/v2.1.5/") {
+		t.Errorf("Expected docs.html to load a pinned Redoc release, got:\n%s", docsPage)
+	}
+}
+
+func TestDocsIntegrity(t *testing.T) {
+	render := func(integrity string) string {
+		resp := httptest.NewRecorder()
+		docsHandler(integrity)(resp, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		return resp.Body.String()
+	}
+	if page := render(""); strings.Contains(page, "integrity=") || strings.Contains(page, "{{") {
+		t.Errorf("Expected no integrity attribute without REDOC_INTEGRITY, got:\n%s", page)
+	}
+	hash := "sha384-" + strings.Repeat("A", 64)
+	if page := render(hash); !strings.Contains(page, `integrity="`+hash+`" crossorigin="anonymous"`) {
+		t.Errorf("Expected the script to carry the integrity hash, got:\n%s", page)
+	}
+}
+
+func TestReadRoutesMatchSpec(t *testing.T) {
+	c := client{t: t, router: checkedRouter(t)}
+	resp := c.do(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "admin", "password": "admin123"})
+	var tokens domain.TokenPair
+	json.NewDecoder(resp.Body).Decode(&tokens)
+	c.token = tokens.AccessToken
+	c.expect(http.MethodPost, "/api/v1/webhooks", map[string]any{"url": "http://127.0.0.1:1/hooks", "eventtypes": []string{"ticket.opened", "slot.added"}}, http.StatusCreated)
+	c.expect(http.MethodPost, "/api/v1/slots", map[string]any{"slotid": 1, "slottype": "car", "isfree": true}, http.StatusCreated)
+	ticket := c.expect(http.MethodPost, "/api/v1/tickets", map[string]string{"vehiclenumber": "UP16AB1234", "vehicletype": "car"}, http.StatusCreated).Header().Get("Location")
+	c.expect(http.MethodPost, "/api/v1/vehicle-list-entries", map[string]string{"vehiclenumber": "DL01CD5678", "listtype": "block", "reason": "unpaid"}, http.StatusCreated)
+	c.expect(http.MethodPost, "/api/v1/tickets", map[string]string{"vehiclenumber": "DL01CD5678", "vehicletype": "car"}, http.StatusForbidden)
+	c.expect(http.MethodPost, "/api/v1/api-keys", map[string]any{"name": "gate", "scopes": []string{"parking:operate"}}, http.StatusCreated)
+
+	for _, path := range []string{
+		"/openapi.json",
+		"/docs",
+		"/.well-known/jwks.json",
+		"/api/v1/slots",
+		"/api/v1/slots?free=true",
+		"/api/v1/availability",
+		ticket,
+		ticket + "/quote",
+		"/api/v1/vehicles/UP16AB1234/balance",
+		"/api/v1/vehicle-list-entries",
+		"/api/v1/entry-rejections",
+		"/api/v1/adjustments",
+		"/api/v1/reports/revenue?from=2024-01-01&to=2024-02-01",
+		"/api/v1/users",
+		"/api/v1/lockouts",
+		"/api/v1/api-keys",
+		"/api/v1/audit",
+		"/api/v1/webhooks",
+		"/api/v1/webhook-deliveries",
+		"/api/v1/webhook-deliveries/dead",
+		"/GetAvailableSlots",
+		"/GetVehicleList",
+		"/GetEntryRejections",
+		"/GetUnpaidBalance?vehiclenumber=UP16AB1234",
+		"/GetAdjustments",
+		"/GetUsers",
+		"/GetLockouts",
+		"/GetAPIKeys",
+	} {
+		if resp = c.do(http.MethodGet, path, nil); resp.Code != http.StatusOK {
+			t.Errorf("GET %s: expected 200, got %d: %s", path, resp.Code, resp.Body.String())
+		}
+	}
+}
+
+// TestWriteRoutesMatchSpec makes each v1 change that the lifecycle tests
+// don't, expecting it to succeed, so that checkedRouter validates the
+// response the spec documents for success rather than a problem.
+func TestWriteRoutesMatchSpec(t *testing.T) {
+	c := client{t: t, router: checkedRouter(t)}
+	var tokens domain.TokenPair
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "admin", "password": "admin123"}, http.StatusOK).Body).Decode(&tokens)
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/auth/refresh", map[string]string{"refreshtoken": tokens.RefreshToken}, http.StatusOK).Body).Decode(&tokens)
+	c.token = tokens.AccessToken
+
+	c.expect(http.MethodPost, "/api/v1/slots", map[string]any{"slotid": 1, "slottype": "car", "isfree": true}, http.StatusCreated)
+	c.expect(http.MethodPost, "/api/v1/slots", map[string]any{"slotid": 2, "slottype": "car", "isfree": true}, http.StatusCreated)
+	c.expect(http.MethodPost, "/api/v1/availability/stream-token", nil, http.StatusCreated)
+	c.expect(http.MethodPost, "/api/v1/graphql", map[string]string{"query": "{ availability { total free } }"}, http.StatusOK)
+
+	var paid, unpaid domain.Ticket
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/tickets", map[string]string{"vehiclenumber": "UP16AB1234", "vehicletype": "car"}, http.StatusCreated).Body).Decode(&paid)
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/tickets", map[string]string{"vehiclenumber": "UP16AB9999", "vehicletype": "car"}, http.StatusCreated).Body).Decode(&unpaid)
+	c.expect(http.MethodPost, fmt.Sprintf("/api/v1/tickets/%d/exit", paid.TicketId), map[string]string{"method": domain.PaymentCash}, http.StatusOK)
+	c.expect(http.MethodPost, fmt.Sprintf("/api/v1/tickets/%d/force-exit", unpaid.TicketId), map[string]string{"reason": "barrier lifted"}, http.StatusOK)
+	c.expect(http.MethodPost, "/api/v1/vehicles/UP16AB9999/settlements", map[string]string{"amount": "0.01"}, http.StatusCreated)
+
+	var approved, rejected domain.FeeAdjustment
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/adjustments", map[string]any{"ticketid": paid.TicketId, "amount": "0.01", "reason": "overcharged"}, http.StatusCreated).Body).Decode(&approved)
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/adjustments", map[string]any{"ticketid": unpaid.TicketId, "amount": "0.01", "reason": "barrier fault"}, http.StatusCreated).Body).Decode(&rejected)
+	c.expect(http.MethodPost, "/api/v1/users", map[string]string{"username": "sup", "password": "password", "role": domain.RoleSupervisor}, http.StatusCreated)
+	supervisor := client{t: t, router: c.router}
+	json.NewDecoder(supervisor.expect(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "sup", "password": "password"}, http.StatusOK).Body).Decode(&tokens)
+	supervisor.token = tokens.AccessToken
+	supervisor.expect(http.MethodPost, fmt.Sprintf("/api/v1/adjustments/%d/approve", approved.AdjustmentId), nil, http.StatusOK)
+	supervisor.expect(http.MethodPost, fmt.Sprintf("/api/v1/adjustments/%d/apply", approved.AdjustmentId), nil, http.StatusOK)
+	supervisor.expect(http.MethodPost, fmt.Sprintf("/api/v1/adjustments/%d/reject", rejected.AdjustmentId), nil, http.StatusOK)
+	supervisor.expect(http.MethodPut, "/api/v1/me/password", map[string]string{"currentpassword": "password", "newpassword": "password2"}, http.StatusNoContent)
+	json.NewDecoder(supervisor.expect(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "sup", "password": "password2"}, http.StatusOK).Body).Decode(&tokens)
+	supervisor.token = tokens.AccessToken
+	supervisor.expect(http.MethodPost, "/api/v1/auth/logout", map[string]string{"refreshtoken": tokens.RefreshToken}, http.StatusOK)
+
+	c.expect(http.MethodPost, "/api/v1/vehicle-list-entries", map[string]string{"vehiclenumber": "DL01CD5678", "listtype": "block", "reason": "unpaid"}, http.StatusCreated)
+	c.expect(http.MethodDelete, "/api/v1/vehicle-list-entries/DL01CD5678", nil, http.StatusNoContent)
+
+	var key struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/api-keys", map[string]any{"name": "gate", "scopes": []string{"parking:operate"}}, http.StatusCreated).Body).Decode(&key)
+	c.expect(http.MethodPost, "/api/v1/api-keys/"+key.ID+"/rotate", nil, http.StatusOK)
+	c.expect(http.MethodDelete, "/api/v1/api-keys/"+key.ID, nil, http.StatusOK)
+
+	var hook struct {
+		ID string `json:"id"`
+	}
+	json.NewDecoder(c.expect(http.MethodPost, "/api/v1/webhooks", map[string]any{"url": "http://127.0.0.1:1/hooks", "eventtypes": []string{"slot.added"}}, http.StatusCreated).Body).Decode(&hook)
+	c.expect(http.MethodDelete, "/api/v1/webhooks/"+hook.ID, nil, http.StatusNoContent)
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"net/http"
+	"os"
+	"parkingSlotManagement/internals/adapters/graphqlHandlers"
+	"parkingSlotManagement/internals/adapters/requestHandlers"
+	"parkingSlotManagement/internals/adapters/requestHandlers/middleware"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/auth"
+
+	"github.com/gorilla/mux"
+)
+
+// registerRoutes adds the /api/v1 resources to r. They are registered on
+// the root router rather than a /api/v1 subrouter, since mux answers 404
+// instead of 405 for a wrong method on a subrouter.
+func registerRoutes(r *mux.Router, handler *requestHandlers.Handlers, userHandler *requestHandlers.UserHandlers, auditHandler *requestHandlers.AuditHandlers, availabilityHandler *requestHandlers.AvailabilityHandlers, webhookHandler *requestHandlers.WebhookHandlers, graphqlHandler *graphqlHandlers.Handler, AuthService auth.AuthService) {
+	protect := func(h http.HandlerFunc, permission domain.Permission) http.HandlerFunc {
+		return middleware.AuthMiddleware(h, AuthService, permission)
+	}
+
+	r.HandleFunc("/openapi.json", serveSpec).Methods(http.MethodGet)
+	r.HandleFunc("/docs", docsHandler(os.Getenv("REDOC_INTEGRITY"))).Methods(http.MethodGet)
+	r.HandleFunc("/.well-known/jwks.json", requestHandlers.JWKSHandler(AuthService)).Methods(http.MethodGet)
+
+	r.HandleFunc("/api/v1/auth/login", requestHandlers.LoginHandler(AuthService)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/auth/refresh", requestHandlers.RefreshHandler(AuthService)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/auth/logout", protect(requestHandlers.LogoutHandler(AuthService), domain.PermOwnAccount)).Methods(http.MethodPost)
+
+	r.HandleFunc("/api/v1/slots", protect(handler.ListSlots, domain.PermParkingOperate)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/slots", protect(handler.CreateSlot, domain.PermSlotsManage)).Methods(http.MethodPost)
+
+	r.HandleFunc("/api/v1/availability", protect(availabilityHandler.GetAvailability, domain.PermAvailabilityRead)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/availability/stream-token", protect(requestHandlers.StreamTokenHandler(AuthService), domain.PermAvailabilityRead)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/availability/stream", middleware.StreamAuthMiddleware(availabilityHandler.StreamAvailability, AuthService)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/availability/ws", middleware.StreamAuthMiddleware(availabilityHandler.WatchAvailability, AuthService)).Methods(http.MethodGet)
+
+	r.HandleFunc("/api/v1/tickets", protect(handler.CreateTicket, domain.PermParkingOperate)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/tickets/{id:[0-9]+}", protect(handler.GetTicket, domain.PermParkingOperate)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/tickets/{id:[0-9]+}/quote", protect(handler.GetTicketQuote, domain.PermParkingOperate)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/tickets/{id:[0-9]+}/exit", protect(handler.ExitTicket, domain.PermParkingOperate)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/tickets/{id:[0-9]+}/force-exit", protect(handler.ForceExitTicket, domain.PermLedgerManage)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/tickets/{id:[0-9]+}/receipt", protect(handler.GetTicketReceipt, domain.PermReceiptsRead)).Methods(http.MethodGet)
+
+	r.HandleFunc("/api/v1/vehicles/{vehiclenumber}/balance", protect(handler.GetVehicleBalance, domain.PermParkingOperate)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/vehicles/{vehiclenumber}/settlements", protect(handler.CreateSettlement, domain.PermParkingOperate)).Methods(http.MethodPost)
+
+	r.HandleFunc("/api/v1/vehicle-list-entries", protect(handler.GetVehicleList, domain.PermVehicleListsRead)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/vehicle-list-entries", protect(handler.AddVehicleListEntry, domain.PermVehicleListsManage)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/vehicle-list-entries/{vehiclenumber}", protect(handler.DeleteVehicleListEntry, domain.PermVehicleListsManage)).Methods(http.MethodDelete)
+	r.HandleFunc("/api/v1/entry-rejections", protect(handler.GetEntryRejections, domain.PermVehicleListsRead)).Methods(http.MethodGet)
+
+	r.HandleFunc("/api/v1/adjustments", protect(handler.GetAdjustments, domain.PermReportsRead)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/adjustments", protect(handler.RequestAdjustment, domain.PermAdjustmentsRequest)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/adjustments/{id:[0-9]+}/approve", protect(handler.ApproveAdjustment, domain.PermAdjustmentsReview)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/adjustments/{id:[0-9]+}/reject", protect(handler.RejectAdjustment, domain.PermAdjustmentsReview)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/adjustments/{id:[0-9]+}/apply", protect(handler.ApplyAdjustment, domain.PermAdjustmentsReview)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/graphql", protect(graphqlHandler.ServeHTTP, domain.PermParkingOperate)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/reports/revenue", protect(handler.GetRevenueReport, domain.PermReportsRead)).Methods(http.MethodGet)
+
+	r.HandleFunc("/api/v1/users", protect(userHandler.GetUsers, domain.PermUsersManage)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/users", protect(userHandler.AddUser, domain.PermUsersManage)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/users/{id}", protect(userHandler.GetUser, domain.PermUsersManage)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/users/{id}", protect(userHandler.PatchUser, domain.PermUsersManage)).Methods(http.MethodPatch)
+	r.HandleFunc("/api/v1/users/{id}", protect(userHandler.RemoveUser, domain.PermUsersManage)).Methods(http.MethodDelete)
+	r.HandleFunc("/api/v1/users/{id}/password", protect(userHandler.SetUserPassword, domain.PermUsersManage)).Methods(http.MethodPut)
+	r.HandleFunc("/api/v1/me/password", protect(userHandler.SetOwnPassword, domain.PermOwnAccount)).Methods(http.MethodPut)
+	r.HandleFunc("/api/v1/lockouts", protect(userHandler.GetLockouts, domain.PermUsersManage)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/lockouts/{key}", protect(userHandler.DeleteLockout, domain.PermUsersManage)).Methods(http.MethodDelete)
+
+	r.HandleFunc("/api/v1/api-keys", protect(userHandler.GetAPIKeys, domain.PermAPIKeysManage)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/api-keys", protect(userHandler.AddAPIKey, domain.PermAPIKeysManage)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/api-keys/{id}/rotate", protect(userHandler.RotateAPIKey, domain.PermAPIKeysManage)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/api-keys/{id}", protect(userHandler.RevokeAPIKey, domain.PermAPIKeysManage)).Methods(http.MethodDelete)
+
+	r.HandleFunc("/api/v1/webhooks", protect(webhookHandler.GetWebhooks, domain.PermWebhooksManage)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/webhooks", protect(webhookHandler.AddWebhook, domain.PermWebhooksManage)).Methods(http.MethodPost)
+	r.HandleFunc("/api/v1/webhooks/{id}", protect(webhookHandler.DeleteWebhook, domain.PermWebhooksManage)).Methods(http.MethodDelete)
+	r.HandleFunc("/api/v1/webhook-deliveries", protect(webhookHandler.GetDeliveries, domain.PermWebhooksManage)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/webhook-deliveries/dead", protect(webhookHandler.GetDeadLetters, domain.PermWebhooksManage)).Methods(http.MethodGet)
+	r.HandleFunc("/api/v1/webhook-deliveries/{id}/retry", protect(webhookHandler.RetryDelivery, domain.PermWebhooksManage)).Methods(http.MethodPost)
+
+	r.HandleFunc("/api/v1/audit", protect(auditHandler.GetAudit, domain.PermAuditRead)).Methods(http.MethodGet)
+}
+
+// registerLegacyRoutes keeps the original verb-style routes working for
+// existing clients. Each answers with a Deprecation header and a Link to
+// its /api/v1 successor.
+func registerLegacyRoutes(r *mux.Router, handler *requestHandlers.Handlers, userHandler *requestHandlers.UserHandlers, auditHandler *requestHandlers.AuditHandlers, AuthService auth.AuthService) {
+	legacy := func(successor string, h http.HandlerFunc, permission domain.Permission) http.HandlerFunc {
+		return middleware.Deprecated(successor, middleware.AuthMiddleware(h, AuthService, permission))
+	}
+
+	r.HandleFunc("/login", middleware.Deprecated("/api/v1/auth/login", requestHandlers.LoginHandler(AuthService))).Methods(http.MethodPost)
+	r.HandleFunc("/refresh", middleware.Deprecated("/api/v1/auth/refresh", requestHandlers.RefreshHandler(AuthService))).Methods(http.MethodPost)
+	r.HandleFunc("/logout", legacy("/api/v1/auth/logout", requestHandlers.LogoutHandler(AuthService), domain.PermOwnAccount)).Methods(http.MethodPost)
+
+	r.HandleFunc("/ParkVehicle", legacy("/api/v1/tickets", handler.ParkVehicleRequest, domain.PermParkingOperate)).Methods(http.MethodPost)
+	r.HandleFunc("/QuoteExit", legacy("/api/v1/tickets", handler.QuoteExitRequest, domain.PermParkingOperate)).Methods(http.MethodPost)
+	r.HandleFunc("/UnparkVehicle", legacy("/api/v1/tickets", handler.UnparkVehicleRequest, domain.PermParkingOperate)).Methods(http.MethodPost)
+	r.HandleFunc("/AddSlot", legacy("/api/v1/slots", handler.AddSlot, domain.PermSlotsManage)).Methods(http.MethodPost)
+	r.HandleFunc("/GetAvailableSlots", legacy("/api/v1/slots?free=true", handler.GetAvailableSlots, domain.PermParkingOperate)).Methods(http.MethodGet, http.MethodPost)
+
+	r.HandleFunc("/AddVehicleListEntry", legacy("/api/v1/vehicle-list-entries", handler.AddVehicleListEntry, domain.PermVehicleListsManage)).Methods(http.MethodPost)
+	r.HandleFunc("/RemoveVehicleListEntry", legacy("/api/v1/vehicle-list-entries", handler.RemoveVehicleListEntry, domain.PermVehicleListsManage)).Methods(http.MethodPost)
+	r.HandleFunc("/GetVehicleList", legacy("/api/v1/vehicle-list-entries", handler.GetVehicleList, domain.PermVehicleListsRead)).Methods(http.MethodGet)
+	r.HandleFunc("/GetEntryRejections", legacy("/api/v1/entry-rejections", handler.GetEntryRejections, domain.PermVehicleListsRead)).Methods(http.MethodGet)
+
+	r.HandleFunc("/ForceUnparkVehicle", legacy("/api/v1/tickets", handler.ForceUnparkVehicleRequest, domain.PermLedgerManage)).Methods(http.MethodPost)
+	r.HandleFunc("/GetUnpaidBalance", legacy("/api/v1/vehicles", handler.GetUnpaidBalance, domain.PermParkingOperate)).Methods(http.MethodGet)
+	r.HandleFunc("/SettleBalance", legacy("/api/v1/vehicles", handler.SettleBalanceRequest, domain.PermParkingOperate)).Methods(http.MethodPost)
+
+	r.HandleFunc("/RequestAdjustment", legacy("/api/v1/adjustments", handler.RequestAdjustment, domain.PermAdjustmentsRequest)).Methods(http.MethodPost)
+	r.HandleFunc("/ApproveAdjustment", legacy("/api/v1/adjustments", handler.ApproveAdjustment, domain.PermAdjustmentsReview)).Methods(http.MethodPost)
+	r.HandleFunc("/RejectAdjustment", legacy("/api/v1/adjustments", handler.RejectAdjustment, domain.PermAdjustmentsReview)).Methods(http.MethodPost)
+	r.HandleFunc("/ApplyAdjustment", legacy("/api/v1/adjustments", handler.ApplyAdjustment, domain.PermAdjustmentsReview)).Methods(http.MethodPost)
+	r.HandleFunc("/GetAdjustments", legacy("/api/v1/adjustments", handler.GetAdjustments, domain.PermReportsRead)).Methods(http.MethodGet)
+	r.HandleFunc("/GetRevenueReport", legacy("/api/v1/reports/revenue", handler.GetRevenueReport, domain.PermReportsRead)).Methods(http.MethodGet)
+	r.HandleFunc("/GetReceipt", legacy("/api/v1/tickets", handler.GetReceipt, domain.PermReceiptsRead)).Methods(http.MethodGet)
+
+	r.HandleFunc("/AddUser", legacy("/api/v1/users", userHandler.AddUser, domain.PermUsersManage)).Methods(http.MethodPost)
+	r.HandleFunc("/UpdateUser", legacy("/api/v1/users", userHandler.UpdateUser, domain.PermUsersManage)).Methods(http.MethodPost)
+	r.HandleFunc("/DeleteUser", legacy("/api/v1/users", userHandler.DeleteUser, domain.PermUsersManage)).Methods(http.MethodPost)
+	r.HandleFunc("/GetUsers", legacy("/api/v1/users", userHandler.GetUsers, domain.PermUsersManage)).Methods(http.MethodGet)
+	r.HandleFunc("/ResetPassword", legacy("/api/v1/users", userHandler.ResetPassword, domain.PermUsersManage)).Methods(http.MethodPost)
+	r.HandleFunc("/GetLockouts", legacy("/api/v1/lockouts", userHandler.GetLockouts, domain.PermUsersManage)).Methods(http.MethodGet)
+	r.HandleFunc("/UnlockAccount", legacy("/api/v1/lockouts", userHandler.UnlockAccount, domain.PermUsersManage)).Methods(http.MethodPost)
+	r.HandleFunc("/ChangePassword", legacy("/api/v1/me/password", userHandler.ChangePassword, domain.PermOwnAccount)).Methods(http.MethodPost)
+
+	r.HandleFunc("/AddAPIKey", legacy("/api/v1/api-keys", userHandler.AddAPIKey, domain.PermAPIKeysManage)).Methods(http.MethodPost)
+	r.HandleFunc("/GetAPIKeys", legacy("/api/v1/api-keys", userHandler.GetAPIKeys, domain.PermAPIKeysManage)).Methods(http.MethodGet)
+	r.HandleFunc("/RotateAPIKey", legacy("/api/v1/api-keys", userHandler.RotateAPIKey, domain.PermAPIKeysManage)).Methods(http.MethodPost)
+	r.HandleFunc("/RevokeAPIKey", legacy("/api/v1/api-keys", userHandler.RevokeAPIKey, domain.PermAPIKeysManage)).Methods(http.MethodPost)
+
+	r.HandleFunc("/audit", legacy("/api/v1/audit", auditHandler.GetAudit, domain.PermAuditRead)).Methods(http.MethodGet)
+}
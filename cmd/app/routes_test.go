@@ -0,0 +1,299 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"parkingSlotManagement/internals/adapters/graphqlHandlers"
+	"parkingSlotManagement/internals/adapters/payments"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/adapters/requestHandlers"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/audit"
+	"parkingSlotManagement/internals/core/services/auth"
+	"parkingSlotManagement/internals/core/services/availability"
+	"parkingSlotManagement/internals/core/services/events"
+	"parkingSlotManagement/internals/core/services/outbox"
+	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/core/services/webhook"
+	"parkingSlotManagement/internals/ports"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestRouter(t *testing.T) *mux.Router {
+	os.Setenv("JWT_SECRET", "testsecret-testsecret-testsecret")
+	service := parking.NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	service.LedgerRepo = inmemmory.NewLedgerInMemmory()
+	service.VehicleListRepo = inmemmory.NewVehicleListInMemmory()
+	service.AdjustmentRepo = inmemmory.NewAdjustmentInMemmory()
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCash: payments.NewCashGateway()}
+	// A stay of a few milliseconds still costs a paisa, so tests can
+	// settle and adjust it.
+	service.FeeRounding = domain.RoundUp
+	bus := events.NewBus()
+	webhooks := webhook.NewService(inmemmory.NewWebhookInMemmory())
+	webhooks.AllowPrivateTargets = true
+	publisher, err := outboxPublisher("", bus, webhooks)
+	if err != nil {
+		t.Fatalf("outboxPublisher failed: %v", err)
+	}
+	outboxRepo := inmemmory.NewOutboxInMemmory()
+	relay := outbox.NewRelay(outboxRepo, publisher)
+	service.Outbox = outboxRepo
+	service.Events = relay
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go relay.Run(ctx)
+	authService := auth.NewAuthService(inmemmory.NewUserInMemmory(), inmemmory.NewRevocationInMemmory())
+	authService.HashCost = bcrypt.MinCost
+	authService.APIKeys = inmemmory.NewAPIKeyInMemmory()
+	if _, err := authService.Bootstrap(context.Background(), "admin", "admin123"); err != nil {
+		t.Fatalf("Bootstrap failed: %v", err)
+	}
+
+	handler := requestHandlers.NewHandlers(service)
+	userHandler := requestHandlers.NewUserHandlers(authService)
+	auditHandler := requestHandlers.NewAuditHandlers(audit.NewService(inmemmory.NewAuditInMemmory()))
+	availabilityHandler := requestHandlers.NewAvailabilityHandlers(service, availability.NewFeed(service, bus))
+	webhookHandler := requestHandlers.NewWebhookHandlers(webhooks)
+	r := mux.NewRouter()
+	r.NotFoundHandler = problem.NotFound
+	r.MethodNotAllowedHandler = problem.MethodNotAllowed
+	registerRoutes(r, handler, userHandler, auditHandler, availabilityHandler, webhookHandler, graphqlHandlers.NewHandler(service), authService)
+	registerLegacyRoutes(r, handler, userHandler, auditHandler, authService)
+	return r
+}
+
+type client struct {
+	t      *testing.T
+	router http.Handler
+	token  string
+}
+
+func (c client) do(method, path string, body any) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp := httptest.NewRecorder()
+	c.router.ServeHTTP(resp, req)
+	return resp
+}
+
+// expect is do, failing the test unless the response has status.
+func (c client) expect(method, path string, body any, status int) *httptest.ResponseRecorder {
+	c.t.Helper()
+	resp := c.do(method, path, body)
+	if resp.Code != status {
+		c.t.Errorf("%s %s: expected %d, got %d: %s", method, path, status, resp.Code, resp.Body.String())
+	}
+	return resp
+}
+
+func TestV1TicketLifecycle(t *testing.T) {
+	c := client{t: t, router: checkedRouter(t)}
+	resp := c.do(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "admin", "password": "admin123"})
+	var tokens domain.TokenPair
+	json.NewDecoder(resp.Body).Decode(&tokens)
+	if resp.Code != http.StatusOK || tokens.AccessToken == "" {
+		t.Fatalf("Expected to log in, got %d", resp.Code)
+	}
+	c.token = tokens.AccessToken
+
+	if resp = c.do(http.MethodPost, "/api/v1/slots", domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}); resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 Created for a slot, got %d", resp.Code)
+	}
+	resp = c.do(http.MethodPost, "/api/v1/tickets", domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	var ticket domain.Ticket
+	json.NewDecoder(resp.Body).Decode(&ticket)
+	location := resp.Header().Get("Location")
+	if resp.Code != http.StatusCreated || location == "" {
+		t.Fatalf("Expected status 201 Created with a Location, got %d %q", resp.Code, location)
+	}
+	if resp = c.do(http.MethodPost, "/api/v1/tickets", domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"}); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 Conflict parking twice, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodGet, location, nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected the ticket at its Location, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodGet, "/api/v1/slots?free=true", nil); resp.Code != http.StatusOK || resp.Body.String() != "[]\n" {
+		t.Errorf("Expected no free slots, got %d %s", resp.Code, resp.Body.String())
+	}
+	if resp = c.do(http.MethodGet, location+"/quote", nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected a quote, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodGet, location+"/receipt", nil); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 Conflict for a receipt before exit, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodPost, location+"/exit", map[string]string{"method": domain.PaymentCash}); resp.Code != http.StatusOK {
+		t.Fatalf("Expected the exit to succeed, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp = c.do(http.MethodPost, location+"/exit", map[string]string{"method": domain.PaymentCash}); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 Conflict exiting twice, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodGet, location+"/receipt", nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected the receipt after exit, got %d", resp.Code)
+	}
+	resp = c.do(http.MethodPost, "/api/v1/tickets", domain.Vehicle{VehicleNumber: "UP16AB9999", VehicleType: "car"})
+	json.NewDecoder(resp.Body).Decode(&ticket)
+	if resp = c.do(http.MethodPost, resp.Header().Get("Location")+"/force-exit", map[string]string{"reason": "barrier lifted"}); resp.Code != http.StatusOK {
+		t.Errorf("Expected the forced exit to succeed, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp = c.do(http.MethodGet, "/api/v1/vehicles/UP16AB9999/balance", nil); resp.Code != http.StatusOK {
+		t.Errorf("Expected the unpaid balance, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodPost, "/api/v1/vehicles/UP16AB9999/settlements", map[string]string{"amount": "0"}); resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity for a zero settlement, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodGet, "/api/v1/tickets/42", nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 Not Found for an unknown ticket, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodDelete, "/api/v1/slots", nil); resp.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 Method Not Allowed, got %d", resp.Code)
+	}
+}
+
+func TestV1Users(t *testing.T) {
+	c := client{t: t, router: checkedRouter(t)}
+	resp := c.do(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "admin", "password": "admin123"})
+	var tokens domain.TokenPair
+	json.NewDecoder(resp.Body).Decode(&tokens)
+	c.token = tokens.AccessToken
+
+	resp = c.do(http.MethodPost, "/api/v1/users", map[string]string{"username": "ravi", "password": "password", "role": domain.RoleAttendant})
+	var user domain.User
+	json.NewDecoder(resp.Body).Decode(&user)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 Created for a user, got %d", resp.Code)
+	}
+	resp = c.do(http.MethodPatch, "/api/v1/users/"+user.ID, map[string]bool{"disabled": true})
+	json.NewDecoder(resp.Body).Decode(&user)
+	if resp.Code != http.StatusOK || !user.Disabled || user.Role != domain.RoleAttendant {
+		t.Errorf("Expected only the disabled flag to change, got %d %+v", resp.Code, user)
+	}
+	if resp = c.do(http.MethodPut, "/api/v1/users/"+user.ID+"/password", map[string]string{"newpassword": "password2"}); resp.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 No Content resetting a password, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodDelete, "/api/v1/users/"+user.ID, nil); resp.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 No Content deleting a user, got %d", resp.Code)
+	}
+	if resp = c.do(http.MethodGet, "/api/v1/users/"+user.ID, nil); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 Not Found for a deleted user, got %d", resp.Code)
+	}
+}
+
+func TestLegacyRoutesAreDeprecated(t *testing.T) {
+	c := client{t: t, router: checkedRouter(t)}
+	resp := c.do(http.MethodPost, "/login", map[string]string{"username": "admin", "password": "admin123"})
+	var tokens domain.TokenPair
+	json.NewDecoder(resp.Body).Decode(&tokens)
+	if resp.Code != http.StatusOK || resp.Header().Get("Deprecation") != "true" {
+		t.Fatalf("Expected the legacy login to work and be marked deprecated, got %d %v", resp.Code, resp.Header())
+	}
+	c.token = tokens.AccessToken
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		resp = c.do(method, "/GetAvailableSlots", nil)
+		if resp.Code != http.StatusOK || resp.Header().Get("Link") != `</api/v1/slots?free=true>; rel="successor-version"` {
+			t.Errorf("Expected %s /GetAvailableSlots to point to its successor, got %d %v", method, resp.Code, resp.Header())
+		}
+	}
+}
+
+func TestErrorsAreProblemDetails(t *testing.T) {
+	c := client{t: t, router: checkedRouter(t)}
+	for _, test := range []struct {
+		method, path string
+		status       int
+		code         string
+	}{
+		{http.MethodGet, "/api/v1/nowhere", http.StatusNotFound, "not_found"},
+		{http.MethodDelete, "/api/v1/slots", http.StatusMethodNotAllowed, "method_not_allowed"},
+		{http.MethodGet, "/api/v1/slots", http.StatusUnauthorized, "unauthorized"},
+	} {
+		resp := c.do(test.method, test.path, nil)
+		var p problem.Problem
+		json.NewDecoder(resp.Body).Decode(&p)
+		if resp.Code != test.status || p.Code != test.code || resp.Header().Get("Content-Type") != problem.ContentType {
+			t.Errorf("%s %s: expected %d %s, got %d %+v", test.method, test.path, test.status, test.code, resp.Code, p)
+		}
+	}
+}
+
+func TestGraphQL(t *testing.T) {
+	c := client{t: t, router: checkedRouter(t)}
+	query := map[string]string{"query": "{ availability { total free } }"}
+	if resp := c.do(http.MethodPost, "/api/v1/graphql", query); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", resp.Code)
+	}
+
+	resp := c.do(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "admin", "password": "admin123"})
+	var tokens domain.TokenPair
+	json.NewDecoder(resp.Body).Decode(&tokens)
+	c.token = tokens.AccessToken
+	c.do(http.MethodPost, "/api/v1/graphql", map[string]string{"query": `mutation { addSlot(slotId: 1, slotType: "car") { slotId } }`})
+	if resp = c.do(http.MethodPost, "/api/v1/graphql", query); resp.Code != http.StatusOK || resp.Body.String() != `{"data":{"availability":{"total":1,"free":1}}}`+"\n" {
+		t.Errorf("Expected the availability, got %d %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestAvailabilityStreamToken(t *testing.T) {
+	router := newTestRouter(t)
+	c := client{t: t, router: router}
+	resp := c.do(http.MethodPost, "/api/v1/auth/login", map[string]string{"username": "admin", "password": "admin123"})
+	var tokens domain.TokenPair
+	json.NewDecoder(resp.Body).Decode(&tokens)
+	c.token = tokens.AccessToken
+
+	resp = c.do(http.MethodPost, "/api/v1/availability/stream-token", nil)
+	var stream domain.StreamToken
+	json.NewDecoder(resp.Body).Decode(&stream)
+	if resp.Code != http.StatusCreated || stream.Token == "" {
+		t.Fatalf("Expected status 201 Created with a stream token, got %d %s", resp.Code, resp.Body.String())
+	}
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+	open := func(token string) (*http.Response, error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/availability/stream?token="+token, nil)
+		return http.DefaultClient.Do(req)
+	}
+
+	sse, err := open(stream.Token)
+	if err != nil {
+		t.Fatalf("Expected to open the stream, got %v", err)
+	}
+	defer sse.Body.Close()
+	if sse.StatusCode != http.StatusOK || sse.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Expected the stream to open with a stream token, got %d %q", sse.StatusCode, sse.Header.Get("Content-Type"))
+	}
+
+	for name, token := range map[string]string{"an access token": tokens.AccessToken, "garbage": "not-a-token"} {
+		resp, err := open(token)
+		if err != nil {
+			t.Fatalf("Expected a response for %s, got %v", name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for %s in the query, got %d", name, resp.StatusCode)
+		}
+	}
+
+	c.token = stream.Token
+	if resp = c.do(http.MethodGet, "/api/v1/availability", nil); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 using a stream token as a bearer token, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"parkingSlotManagement/internals/adapters/payments"
+	"parkingSlotManagement/internals/adapters/repositories/jsonl"
+	"parkingSlotManagement/internals/adapters/repositories/mysql"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/auth"
+	"parkingSlotManagement/internals/core/services/currency"
+	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/core/services/tax"
+	"parkingSlotManagement/internals/ports"
+	"time"
+)
+
+// The constructors below are shared by the server and the CLI, so that a
+// change made from either is audited, stored with its outbox events and
+// checked against the same configuration. They stop the program on a bad
+// setting, as Start does.
+
+// OpenAuditLog returns the audit_log table, or the JSON-lines file named by
+// AUDIT_LOG_FILE. close releases the file.
+func OpenAuditLog(database *sql.DB) (auditLog ports.AuditLog, close func()) {
+	path := os.Getenv("AUDIT_LOG_FILE")
+	if path == "" {
+		return mysql.NewAuditRepo(database), func() {}
+	}
+	auditFile, err := jsonl.NewAuditFile(path)
+	if err != nil {
+		log.Fatalf("cannot open audit log: %v", err)
+	}
+	return auditFile, func() { auditFile.Close() }
+}
+
+// NewParkingService wires the parking service to the database and the lot
+// configuration. Changes are written to the outbox in their transaction;
+// the server's relay sends them on, and a caller running one sets Events to
+// nudge it.
+func NewParkingService(database *sql.DB, auditLog ports.AuditLog) *parking.ParkingService {
+	//InMemmory
+	// service := parking.NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	// service.VehicleListRepo = inmemmory.NewVehicleListInMemmory()
+	// service.LedgerRepo = inmemmory.NewLedgerInMemmory()
+	// service.AdjustmentRepo = inmemmory.NewAdjustmentInMemmory()
+	// service.Outbox = inmemmory.NewOutboxInMemmory()
+	// (no Transactor: the in-memory repositories have no transactions)
+	service := parking.NewParkingService(mysql.NewSlotRepo(database), mysql.NewTicketRepo(database))
+	service.VehicleListRepo = mysql.NewVehicleListRepo(database)
+	service.LedgerRepo = mysql.NewLedgerRepo(database)
+	service.AdjustmentRepo = mysql.NewAdjustmentRepo(database)
+	service.AuditLog = auditLog
+	service.Outbox = mysql.NewOutboxRepo(database)
+	service.Transactor = mysql.NewTransactor(database)
+	service.PaymentGateways = map[string]ports.PaymentGateway{
+		domain.PaymentCash: payments.NewCashGateway(),
+		// domain.PaymentCard: payments.NewFakeCardGateway(),
+	}
+	lot, err := currency.LotFromEnv()
+	if err != nil {
+		log.Fatalf("invalid lot configuration: %v", err)
+	}
+	service.Lot = lot
+	domain.DefaultCurrency = lot.Currency
+	if service.ExchangeRates, err = currency.RatesFromEnv(lot.Currency); err != nil {
+		log.Fatalf("invalid exchange rates: %v", err)
+	}
+	if value := os.Getenv("MAX_UNPAID_BALANCE"); value != "" {
+		limit, err := domain.ParseMoney(value, lot.Currency)
+		if err != nil || limit.IsNegative() {
+			log.Fatalf("invalid MAX_UNPAID_BALANCE %q", value)
+		}
+		service.MaxUnpaidBalance = limit
+	}
+	if rounding := domain.Rounding(os.Getenv("FEE_ROUNDING")); rounding != "" {
+		if !rounding.Valid() {
+			log.Fatalf("invalid FEE_ROUNDING %q", rounding)
+		}
+		service.FeeRounding = rounding
+	}
+	taxConfig, err := tax.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("invalid GST configuration: %v", err)
+	}
+	if service.TaxCalculator, err = tax.NewCalculator(taxConfig); err != nil {
+		log.Fatalf("invalid GST configuration: %v", err)
+	}
+	return service
+}
+
+// NewAuthService wires the auth service to the database, the JWT keys and
+// the token lifetimes.
+func NewAuthService(database *sql.DB, auditLog ports.AuditLog) *auth.AuthServiceImpl {
+	//InMemmory
+	// service := auth.NewAuthService(inmemmory.NewUserInMemmory(), inmemmory.NewRevocationInMemmory())
+	// service.APIKeys = inmemmory.NewAPIKeyInMemmory()
+	// service.LoginAttempts = inmemmory.NewLoginAttemptInMemmory()
+	service := auth.NewAuthService(mysql.NewUserRepo(database), mysql.NewRevocationRepo(database))
+	var err error
+	if service.Keys, err = auth.KeySetFromEnv(); err != nil {
+		log.Fatalf("invalid JWT keys: %v", err)
+	}
+	service.APIKeys = mysql.NewAPIKeyRepo(database)
+	service.LoginAttempts = mysql.NewLoginAttemptRepo(database)
+	service.Audit = auditLog
+	for _, setting := range []struct {
+		name string
+		ttl  *time.Duration
+	}{
+		{"ACCESS_TOKEN_TTL", &service.AccessTokenTTL},
+		{"REFRESH_TOKEN_TTL", &service.RefreshTokenTTL},
+		{"STREAM_TOKEN_TTL", &service.StreamTokenTTL},
+	} {
+		if value := os.Getenv(setting.name); value != "" {
+			if *setting.ttl, err = time.ParseDuration(value); err != nil {
+				log.Fatalf("invalid %s %q", setting.name, value)
+			}
+		}
+	}
+	return service
+}
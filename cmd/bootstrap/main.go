@@ -0,0 +1,42 @@
+// Command bootstrap creates the first admin of a fresh install. It refuses
+// to run once any user exists.
+//
+//	go run ./cmd/bootstrap -username admin
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"parkingSlotManagement/cmd/app"
+	"parkingSlotManagement/internals/adapters/repositories/mysql"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	username := flag.String("username", "admin", "username of the first admin")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("error Loading .env file")
+	}
+	database := mysql.GetInstance()
+	auditLog, closeAudit := app.OpenAuditLog(database)
+	defer closeAudit()
+	authService := app.NewAuthService(database, auditLog)
+
+	fmt.Printf("Password for %s: ", *username)
+	password, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	password = strings.TrimRight(password, "\r\n")
+
+	user, err := authService.Bootstrap(context.Background(), *username, password)
+	if err != nil {
+		log.Fatalf("bootstrap failed: %v", err)
+	}
+	fmt.Printf("Created admin %s (%s)\n", user.Username, user.ID)
+}
@@ -0,0 +1,83 @@
+// Package graphqlHandlers serves slots, tickets and availability as a
+// GraphQL API, described in schema.graphql.
+package graphqlHandlers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/services/parking"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// MaxDepth bounds how deeply a query may nest, since slots and tickets
+// refer to each other.
+const MaxDepth = 8
+
+type Handler struct {
+	schema *graphql.Schema
+}
+
+func NewHandler(service *parking.ParkingService) *Handler {
+	resolver := &Resolver{service: service, validator: dto.NewValidator(service)}
+	return &Handler{
+		schema: graphql.MustParseSchema(schema, resolver, graphql.UseStringDescriptions(), graphql.MaxDepth(MaxDepth)),
+	}
+}
+
+// ServeHTTP answers a POST of {"query", "operationName", "variables"}.
+// Errors in the query or its resolvers are in the response's errors, each
+// with the HTTP API's error code in its extensions.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Query == "" {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	resp := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// codedError is a resolver error that tells the client its stable error
+// code, and any field errors, in the GraphQL error's extensions.
+type codedError struct {
+	message string
+	code    string
+	fields  dto.Errors
+}
+
+func (e *codedError) Error() string { return e.message }
+
+func (e *codedError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code}
+	if len(e.fields) > 0 {
+		extensions["errors"] = e.fields
+	}
+	return extensions
+}
+
+// resolverError hides errors with no known mapping behind a bare message,
+// logging them instead, like problem.Write.
+func resolverError(err error) error {
+	_, code := problem.Classify(err)
+	if code == "internal_error" {
+		log.Printf("graphql: %v", err)
+		return &codedError{message: http.StatusText(http.StatusInternalServerError), code: code}
+	}
+	coded := &codedError{message: err.Error(), code: code}
+	errors.As(err, &coded.fields)
+	return coded
+}
@@ -0,0 +1,159 @@
+package graphqlHandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"parkingSlotManagement/internals/adapters/payments"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/ports"
+	"strings"
+	"testing"
+)
+
+type gqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+type testLot struct {
+	t       *testing.T
+	handler *Handler
+}
+
+func newTestLot(t *testing.T) testLot {
+	service := parking.NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCash: payments.NewCashGateway()}
+	return testLot{t: t, handler: NewHandler(service)}
+}
+
+// run posts query as a user with role and decodes the response.
+func (l testLot) run(role, query string, variables map[string]any) gqlResponse {
+	l.t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewReader(body))
+	req = req.WithContext(domain.WithUser(context.Background(), &domain.User{Username: role, Role: role}))
+	resp := httptest.NewRecorder()
+	l.handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		l.t.Fatalf("Expected status 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var out gqlResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		l.t.Fatalf("Failed to decode response: %v", err)
+	}
+	return out
+}
+
+func (l testLot) mustRun(role, query string, variables map[string]any) map[string]json.RawMessage {
+	l.t.Helper()
+	out := l.run(role, query, variables)
+	if len(out.Errors) > 0 {
+		l.t.Fatalf("Expected no errors, got %+v", out.Errors)
+	}
+	return out.Data
+}
+
+func TestQueriesAndMutations(t *testing.T) {
+	lot := newTestLot(t)
+	lot.mustRun(domain.RoleAdmin, `mutation { a: addSlot(slotId: 1, slotType: "car") { slotId } b: addSlot(slotId: 2, slotType: "car") { slotId } }`, nil)
+	data := lot.mustRun(domain.RoleAttendant, `mutation($n: String!) { park(vehicleNumber: $n, vehicleType: "car") { id open fee { amount } slot { slotId isFree } } }`,
+		map[string]any{"n": "UP16AB1234"})
+	var parked struct {
+		ID   string
+		Open bool
+		Fee  *struct{ Amount string }
+		Slot struct {
+			SlotId int
+			IsFree bool
+		}
+	}
+	json.Unmarshal(data["park"], &parked)
+	if parked.ID == "" || !parked.Open || parked.Fee != nil || parked.Slot.IsFree {
+		t.Errorf("Expected an open ticket without a fee in an occupied slot, got %+v", parked)
+	}
+
+	data = lot.mustRun(domain.RoleAttendant, `{
+		slots { slotId isFree currentTicket { id vehicleNumber } }
+		availability { total free occupied occupancy byType { slotType total free } }
+		openTickets { vehicleNumber }
+	}`, nil)
+	var slots []struct {
+		SlotId        int
+		IsFree        bool
+		CurrentTicket *struct{ ID, VehicleNumber string }
+	}
+	json.Unmarshal(data["slots"], &slots)
+	if len(slots) != 2 {
+		t.Fatalf("Expected 2 slots, got %s", data["slots"])
+	}
+	for _, slot := range slots {
+		if slot.IsFree != (slot.CurrentTicket == nil) {
+			t.Errorf("Expected only the occupied slot to have a ticket, got %+v", slot)
+		}
+		if slot.CurrentTicket != nil && (slot.CurrentTicket.ID != parked.ID || slot.SlotId != parked.Slot.SlotId) {
+			t.Errorf("Expected the parked ticket in slot %d, got %+v", parked.Slot.SlotId, slot)
+		}
+	}
+	want := `{"total":2,"free":1,"occupied":1,"occupancy":0.5,"byType":[{"slotType":"car","total":2,"free":1}]}`
+	if string(data["availability"]) != want {
+		t.Errorf("Expected availability %s, got %s", want, data["availability"])
+	}
+	if string(data["openTickets"]) != `[{"vehicleNumber":"UP16AB1234"}]` {
+		t.Errorf("Expected one open ticket, got %s", data["openTickets"])
+	}
+
+	data = lot.mustRun(domain.RoleAttendant, `mutation { unpark(vehicleNumber: "UP16AB1234") { open exitTime fee { currency } } }`, nil)
+	if !strings.Contains(string(data["unpark"]), `"open":false`) || !strings.Contains(string(data["unpark"]), `"currency":"INR"`) {
+		t.Errorf("Expected a closed ticket with a fee, got %s", data["unpark"])
+	}
+	data = lot.mustRun(domain.RoleAttendant, `query($id: ID!) { ticket(id: $id) { open } missing: ticket(id: "1") { open } }`, map[string]any{"id": parked.ID})
+	if string(data["ticket"]) != `{"open":false}` || string(data["missing"]) != "null" {
+		t.Errorf("Expected the closed ticket and null for an unknown one, got %s and %s", data["ticket"], data["missing"])
+	}
+	data = lot.mustRun(domain.RoleAttendant, `{ slots(free: false) { slotId } }`, nil)
+	if string(data["slots"]) != "[]" {
+		t.Errorf("Expected no occupied slots, got %s", data["slots"])
+	}
+}
+
+func TestErrors(t *testing.T) {
+	lot := newTestLot(t)
+
+	out := lot.run(domain.RoleAttendant, `mutation { addSlot(slotId: 1, slotType: "car") { slotId } }`, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "forbidden" {
+		t.Errorf("Expected a forbidden error for an attendant adding a slot, got %+v", out.Errors)
+	}
+
+	out = lot.run(domain.RoleAdmin, `mutation { addSlot(slotId: 0, slotType: "boat") { slotId } }`, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "validation_failed" {
+		t.Fatalf("Expected a validation error, got %+v", out.Errors)
+	}
+	if fields, _ := out.Errors[0].Extensions["errors"].([]any); len(fields) != 2 {
+		t.Errorf("Expected errors for slotid and slottype, got %v", out.Errors[0].Extensions["errors"])
+	}
+
+	out = lot.run(domain.RoleAttendant, `mutation { unpark(vehicleNumber: "UP16AB1234") { open } }`, nil)
+	if len(out.Errors) != 1 || out.Errors[0].Extensions["code"] != "ticket_not_found" {
+		t.Errorf("Expected ticket_not_found unparking a vehicle that isn't parked, got %+v", out.Errors)
+	}
+
+	out = lot.run(domain.RoleAttendant, `{ slots { currentTicket { slot { currentTicket { slot { currentTicket { slot { currentTicket { id } } } } } } } } }`, nil)
+	if len(out.Errors) == 0 {
+		t.Errorf("Expected a query nested deeper than %d to be refused", MaxDepth)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader("{"))
+	resp := httptest.NewRecorder()
+	lot.handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a bad body, got %d", resp.Code)
+	}
+}
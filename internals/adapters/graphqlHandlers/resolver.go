@@ -0,0 +1,309 @@
+package graphqlHandlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/parking"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Resolver answers both the queries and the mutations of schema.graphql.
+type Resolver struct {
+	service   *parking.ParkingService
+	validator *dto.Validator
+}
+
+func (r *Resolver) Slots(ctx context.Context, args struct {
+	Free     *bool
+	SlotType *string
+}) ([]*slotResolver, error) {
+	var slots []domain.Slot
+	var err error
+	if args.Free != nil && *args.Free {
+		slots, err = r.service.GetAvailableSlots(ctx)
+	} else {
+		slots, err = r.service.GetSlots(ctx)
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	open := r.openTickets()
+	resolvers := []*slotResolver{}
+	for _, slot := range slots {
+		if args.Free != nil && !*args.Free && slot.IsFree {
+			continue
+		}
+		if args.SlotType != nil && slot.SlotType != *args.SlotType {
+			continue
+		}
+		resolvers = append(resolvers, &slotResolver{slot: slot, open: open})
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Ticket(ctx context.Context, args struct{ ID graphql.ID }) (*ticketResolver, error) {
+	id, err := strconv.ParseInt(string(args.ID), 10, 64)
+	if err != nil {
+		return nil, resolverError(problem.Invalid("id"))
+	}
+	ticket, err := r.service.GetTicket(ctx, id)
+	if errors.Is(err, parking.ErrTicketNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &ticketResolver{ticket: *ticket, r: r}, nil
+}
+
+func (r *Resolver) OpenTickets(ctx context.Context) ([]*ticketResolver, error) {
+	tickets, err := r.service.GetOpenTickets(ctx)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	resolvers := []*ticketResolver{}
+	for _, ticket := range tickets {
+		resolvers = append(resolvers, &ticketResolver{ticket: ticket, r: r})
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Availability(ctx context.Context) (*availabilityResolver, error) {
+	slots, err := r.service.GetSlots(ctx)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	byType := map[string]*typeAvailability{}
+	overall := &availabilityResolver{}
+	for _, slot := range slots {
+		counts, ok := byType[slot.SlotType]
+		if !ok {
+			counts = &typeAvailability{slotType: slot.SlotType}
+			byType[slot.SlotType] = counts
+			overall.byType = append(overall.byType, counts)
+		}
+		counts.total++
+		overall.total++
+		if slot.IsFree {
+			counts.free++
+			overall.free++
+		}
+	}
+	sort.Slice(overall.byType, func(i, j int) bool { return overall.byType[i].slotType < overall.byType[j].slotType })
+	return overall, nil
+}
+
+func (r *Resolver) Park(ctx context.Context, args struct {
+	VehicleNumber string
+	VehicleType   string
+}) (*ticketResolver, error) {
+	req := dto.ParkVehicle{VehicleNumber: args.VehicleNumber, VehicleType: args.VehicleType}
+	if err := r.validator.Validate(req); err != nil {
+		return nil, resolverError(err)
+	}
+	ticket, err := r.service.ParkVehicle(ctx, req.Vehicle())
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &ticketResolver{ticket: *ticket, r: r}, nil
+}
+
+func (r *Resolver) Unpark(ctx context.Context, args struct {
+	VehicleNumber string
+	PaymentMethod *string
+	CardToken     *string
+}) (*ticketResolver, error) {
+	req := dto.UnparkVehicle{VehicleNumber: args.VehicleNumber}
+	if args.PaymentMethod != nil {
+		req.Method = *args.PaymentMethod
+	}
+	if args.CardToken != nil {
+		req.CardToken = *args.CardToken
+	}
+	if err := r.validator.Validate(req); err != nil {
+		return nil, resolverError(err)
+	}
+	number, err := r.service.NormaliseVehicleNumber(req.VehicleNumber)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	ticket, err := r.service.UnparkVehicle(ctx, number, req.PaymentRequest())
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &ticketResolver{ticket: *ticket, r: r}, nil
+}
+
+// AddSlot asks for more than the route's parking:operate, as POST
+// /api/v1/slots does.
+func (r *Resolver) AddSlot(ctx context.Context, args struct {
+	SlotId   int32
+	SlotType string
+}) (*slotResolver, error) {
+	if user, ok := domain.UserFromContext(ctx); !ok || !user.Can(domain.PermSlotsManage) {
+		return nil, resolverError(fmt.Errorf("%w: requires %s", problem.ErrForbidden, domain.PermSlotsManage))
+	}
+	req := dto.CreateSlot{SlotId: int(args.SlotId), SlotType: args.SlotType, IsFree: true}
+	if err := r.validator.Validate(req); err != nil {
+		return nil, resolverError(err)
+	}
+	slot := req.Slot()
+	if err := r.service.AddSlot(ctx, slot); err != nil {
+		return nil, resolverError(err)
+	}
+	return &slotResolver{slot: slot, open: r.openTickets()}, nil
+}
+
+// openTickets loads the open tickets at most once per query, however many
+// slots ask for their current ticket.
+type openTickets struct {
+	r      *Resolver
+	once   sync.Once
+	bySlot map[int]domain.Ticket
+	err    error
+}
+
+func (r *Resolver) openTickets() *openTickets {
+	return &openTickets{r: r}
+}
+
+func (o *openTickets) forSlot(ctx context.Context, slotId int) (*domain.Ticket, error) {
+	o.once.Do(func() {
+		tickets, err := o.r.service.GetOpenTickets(ctx)
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.bySlot = make(map[int]domain.Ticket, len(tickets))
+		for _, ticket := range tickets {
+			o.bySlot[ticket.SlotId] = ticket
+		}
+	})
+	if o.err != nil {
+		return nil, o.err
+	}
+	ticket, ok := o.bySlot[slotId]
+	if !ok {
+		return nil, nil
+	}
+	return &ticket, nil
+}
+
+type slotResolver struct {
+	slot domain.Slot
+	open *openTickets
+}
+
+func (s *slotResolver) SlotId() int32    { return int32(s.slot.SlotId) }
+func (s *slotResolver) SlotType() string { return s.slot.SlotType }
+func (s *slotResolver) IsFree() bool     { return s.slot.IsFree }
+
+func (s *slotResolver) CurrentTicket(ctx context.Context) (*ticketResolver, error) {
+	if s.slot.IsFree {
+		return nil, nil
+	}
+	ticket, err := s.open.forSlot(ctx, s.slot.SlotId)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if ticket == nil {
+		return nil, nil
+	}
+	return &ticketResolver{ticket: *ticket, r: s.open.r}, nil
+}
+
+type ticketResolver struct {
+	ticket domain.Ticket
+	r      *Resolver
+}
+
+func (t *ticketResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(t.ticket.TicketId, 10))
+}
+func (t *ticketResolver) VehicleNumber() string     { return t.ticket.VehicleNumber }
+func (t *ticketResolver) EntryTime() graphql.Time   { return graphql.Time{Time: t.ticket.EntryTime} }
+func (t *ticketResolver) Open() bool                { return t.ticket.ExitTime == nil }
+func (t *ticketResolver) PaymentMethod() *string    { return optional(t.ticket.PaymentMethod) }
+func (t *ticketResolver) PaymentReference() *string { return optional(t.ticket.PaymentReference) }
+
+func (t *ticketResolver) Slot(ctx context.Context) (*slotResolver, error) {
+	slot, err := t.r.service.GetSlot(ctx, t.ticket.SlotId)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &slotResolver{slot: *slot, open: t.r.openTickets()}, nil
+}
+
+func (t *ticketResolver) ExitTime() *graphql.Time {
+	if t.ticket.ExitTime == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t.ticket.ExitTime}
+}
+
+func (t *ticketResolver) Fee() *moneyResolver    { return t.closedAmount(t.ticket.Fee) }
+func (t *ticketResolver) NetFee() *moneyResolver { return t.closedAmount(t.ticket.NetFee) }
+func (t *ticketResolver) Tax() *moneyResolver    { return t.closedAmount(t.ticket.Tax) }
+
+func (t *ticketResolver) closedAmount(m domain.Money) *moneyResolver {
+	if t.ticket.ExitTime == nil {
+		return nil
+	}
+	return &moneyResolver{money: m}
+}
+
+type moneyResolver struct {
+	money domain.Money
+}
+
+func (m *moneyResolver) Amount() string { return m.money.Decimal() }
+
+func (m *moneyResolver) Currency() string {
+	if m.money.Currency == "" {
+		return string(domain.DefaultCurrency)
+	}
+	return string(m.money.Currency)
+}
+
+type availabilityResolver struct {
+	total, free int32
+	byType      []*typeAvailability
+}
+
+func (a *availabilityResolver) Total() int32    { return a.total }
+func (a *availabilityResolver) Free() int32     { return a.free }
+func (a *availabilityResolver) Occupied() int32 { return a.total - a.free }
+
+func (a *availabilityResolver) Occupancy() float64 {
+	if a.total == 0 {
+		return 0
+	}
+	return float64(a.total-a.free) / float64(a.total)
+}
+
+func (a *availabilityResolver) ByType() []*typeAvailability { return a.byType }
+
+type typeAvailability struct {
+	slotType    string
+	total, free int32
+}
+
+func (a *typeAvailability) SlotType() string { return a.slotType }
+func (a *typeAvailability) Total() int32     { return a.total }
+func (a *typeAvailability) Free() int32      { return a.free }
+func (a *typeAvailability) Occupied() int32  { return a.total - a.free }
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
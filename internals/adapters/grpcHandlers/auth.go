@@ -0,0 +1,109 @@
+package grpcHandlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"parkingSlotManagement/internals/adapters/grpcHandlers/parkingpb"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/auth"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// APIKeyMetadata carries an API key, the alternative to a bearer token in
+// the "authorization" metadata.
+const APIKeyMetadata = "x-api-key"
+
+// permissions is what each method asks of the caller, matching the HTTP
+// routes. Methods not listed are refused.
+var permissions = map[string]domain.Permission{
+	parkingpb.ParkingService_Park_FullMethodName:               domain.PermParkingOperate,
+	parkingpb.ParkingService_Unpark_FullMethodName:             domain.PermParkingOperate,
+	parkingpb.ParkingService_AddSlot_FullMethodName:            domain.PermSlotsManage,
+	parkingpb.ParkingService_ListAvailableSlots_FullMethodName: domain.PermParkingOperate,
+	parkingpb.ParkingService_WatchAvailability_FullMethodName:  domain.PermAvailabilityRead,
+}
+
+// UnaryAuthInterceptor lets a call through only if it carries a valid token
+// or API key for a principal granted the method's permission, like the
+// HTTP AuthMiddleware.
+func UnaryAuthInterceptor(authService auth.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authService, info.FullMethod)
+		if err != nil {
+			return nil, statusError(ctx, err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor is UnaryAuthInterceptor for streaming calls.
+func StreamAuthInterceptor(authService auth.AuthService) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authService, info.FullMethod)
+		if err != nil {
+			return statusError(ctx, err)
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate returns ctx carrying the caller and their address.
+func authenticate(ctx context.Context, authService auth.AuthService, method string) (context.Context, error) {
+	permission, ok := permissions[method]
+	if !ok {
+		return ctx, fmt.Errorf("%w: %s is not open to callers", problem.ErrForbidden, method)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var user *domain.User
+	var err error
+	if keys := md.Get(APIKeyMetadata); len(keys) > 0 && keys[0] != "" {
+		if user, err = authService.ValidateAPIKey(ctx, keys[0]); err != nil {
+			return ctx, err
+		}
+	} else {
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return ctx, fmt.Errorf("%w: missing token", problem.ErrUnauthorized)
+		}
+		parts := strings.Split(values[0], " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return ctx, fmt.Errorf("%w: authorization metadata must be \"Bearer <token>\"", problem.ErrUnauthorized)
+		}
+		if user, err = authService.ValidateToken(ctx, strings.TrimSpace(parts[1])); err != nil {
+			return ctx, err
+		}
+	}
+	if !user.Can(permission) {
+		return ctx, fmt.Errorf("%w: requires %s", problem.ErrForbidden, permission)
+	}
+	return domain.WithClientAddress(domain.WithUser(ctx, user), clientAddress(ctx)), nil
+}
+
+// clientAddress returns the IP address the call came from.
+func clientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,65 @@
+package grpcHandlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain names this service in the ErrorInfo attached to each error.
+const ErrorDomain = "parkingSlotManagement"
+
+// grpcCodes translates the HTTP status the problem package gives an error
+// into the nearest gRPC code. Anything not listed is Internal.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnprocessableEntity: codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusPaymentRequired:     codes.FailedPrecondition,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusMethodNotAllowed:    codes.Unimplemented,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+}
+
+// statusError turns err into a gRPC status carrying the same stable error
+// code as the HTTP API, in an ErrorInfo reason, and any field errors as a
+// BadRequest. Errors with no known mapping are logged and sent bare.
+func statusError(ctx context.Context, err error) error {
+	httpStatus, code := problem.Classify(err)
+	grpcCode, ok := grpcCodes[httpStatus]
+	if !ok {
+		method, _ := grpc.Method(ctx)
+		log.Printf("%s: %v", method, err)
+		return status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+	}
+
+	st := status.New(grpcCode, err.Error())
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: code, Domain: ErrorDomain}}
+	var fields dto.Errors
+	if errors.As(err, &fields) {
+		badRequest := &errdetails.BadRequest{}
+		for _, f := range fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
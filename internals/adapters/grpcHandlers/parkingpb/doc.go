@@ -0,0 +1,6 @@
+// Package parkingpb holds the protobuf messages and gRPC stubs generated
+// from parking.proto. Regenerate them after editing the .proto with
+// go generate.
+package parkingpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative parking.proto
@@ -0,0 +1,744 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: parking.proto
+
+package parkingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Money is an amount in a currency. The amount is a decimal string, e.g.
+// "120.50", so it never loses precision.
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        string                 `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_parking_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Money) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Slot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SlotId        int32                  `protobuf:"varint,1,opt,name=slot_id,json=slotId,proto3" json:"slot_id,omitempty"`
+	SlotType      string                 `protobuf:"bytes,2,opt,name=slot_type,json=slotType,proto3" json:"slot_type,omitempty"`
+	IsFree        bool                   `protobuf:"varint,3,opt,name=is_free,json=isFree,proto3" json:"is_free,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Slot) Reset() {
+	*x = Slot{}
+	mi := &file_parking_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Slot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Slot) ProtoMessage() {}
+
+func (x *Slot) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Slot.ProtoReflect.Descriptor instead.
+func (*Slot) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Slot) GetSlotId() int32 {
+	if x != nil {
+		return x.SlotId
+	}
+	return 0
+}
+
+func (x *Slot) GetSlotType() string {
+	if x != nil {
+		return x.SlotType
+	}
+	return ""
+}
+
+func (x *Slot) GetIsFree() bool {
+	if x != nil {
+		return x.IsFree
+	}
+	return false
+}
+
+type Ticket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      int64                  `protobuf:"varint,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	VehicleNumber string                 `protobuf:"bytes,2,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	SlotId        int32                  `protobuf:"varint,3,opt,name=slot_id,json=slotId,proto3" json:"slot_id,omitempty"`
+	EntryTime     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=entry_time,json=entryTime,proto3" json:"entry_time,omitempty"`
+	// Set once the ticket is closed, as are the fields below.
+	ExitTime         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=exit_time,json=exitTime,proto3" json:"exit_time,omitempty"`
+	Fee              *Money                 `protobuf:"bytes,6,opt,name=fee,proto3" json:"fee,omitempty"`
+	NetFee           *Money                 `protobuf:"bytes,7,opt,name=net_fee,json=netFee,proto3" json:"net_fee,omitempty"`
+	Tax              *Money                 `protobuf:"bytes,8,opt,name=tax,proto3" json:"tax,omitempty"`
+	PaymentMethod    string                 `protobuf:"bytes,9,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	PaymentReference string                 `protobuf:"bytes,10,opt,name=payment_reference,json=paymentReference,proto3" json:"payment_reference,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Ticket) Reset() {
+	*x = Ticket{}
+	mi := &file_parking_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticket) ProtoMessage() {}
+
+func (x *Ticket) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticket.ProtoReflect.Descriptor instead.
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Ticket) GetTicketId() int64 {
+	if x != nil {
+		return x.TicketId
+	}
+	return 0
+}
+
+func (x *Ticket) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *Ticket) GetSlotId() int32 {
+	if x != nil {
+		return x.SlotId
+	}
+	return 0
+}
+
+func (x *Ticket) GetEntryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EntryTime
+	}
+	return nil
+}
+
+func (x *Ticket) GetExitTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExitTime
+	}
+	return nil
+}
+
+func (x *Ticket) GetFee() *Money {
+	if x != nil {
+		return x.Fee
+	}
+	return nil
+}
+
+func (x *Ticket) GetNetFee() *Money {
+	if x != nil {
+		return x.NetFee
+	}
+	return nil
+}
+
+func (x *Ticket) GetTax() *Money {
+	if x != nil {
+		return x.Tax
+	}
+	return nil
+}
+
+func (x *Ticket) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *Ticket) GetPaymentReference() string {
+	if x != nil {
+		return x.PaymentReference
+	}
+	return ""
+}
+
+type ParkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VehicleNumber string                 `protobuf:"bytes,1,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	VehicleType   string                 `protobuf:"bytes,2,opt,name=vehicle_type,json=vehicleType,proto3" json:"vehicle_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParkRequest) Reset() {
+	*x = ParkRequest{}
+	mi := &file_parking_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParkRequest) ProtoMessage() {}
+
+func (x *ParkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParkRequest.ProtoReflect.Descriptor instead.
+func (*ParkRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ParkRequest) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *ParkRequest) GetVehicleType() string {
+	if x != nil {
+		return x.VehicleType
+	}
+	return ""
+}
+
+type UnparkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VehicleNumber string                 `protobuf:"bytes,1,opt,name=vehicle_number,json=vehicleNumber,proto3" json:"vehicle_number,omitempty"`
+	// A configured payment method; cash if left empty.
+	PaymentMethod string `protobuf:"bytes,2,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	CardToken     string `protobuf:"bytes,3,opt,name=card_token,json=cardToken,proto3" json:"card_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnparkRequest) Reset() {
+	*x = UnparkRequest{}
+	mi := &file_parking_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnparkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnparkRequest) ProtoMessage() {}
+
+func (x *UnparkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnparkRequest.ProtoReflect.Descriptor instead.
+func (*UnparkRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UnparkRequest) GetVehicleNumber() string {
+	if x != nil {
+		return x.VehicleNumber
+	}
+	return ""
+}
+
+func (x *UnparkRequest) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *UnparkRequest) GetCardToken() string {
+	if x != nil {
+		return x.CardToken
+	}
+	return ""
+}
+
+type AddSlotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SlotId        int32                  `protobuf:"varint,1,opt,name=slot_id,json=slotId,proto3" json:"slot_id,omitempty"`
+	SlotType      string                 `protobuf:"bytes,2,opt,name=slot_type,json=slotType,proto3" json:"slot_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSlotRequest) Reset() {
+	*x = AddSlotRequest{}
+	mi := &file_parking_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSlotRequest) ProtoMessage() {}
+
+func (x *AddSlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSlotRequest.ProtoReflect.Descriptor instead.
+func (*AddSlotRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddSlotRequest) GetSlotId() int32 {
+	if x != nil {
+		return x.SlotId
+	}
+	return 0
+}
+
+func (x *AddSlotRequest) GetSlotType() string {
+	if x != nil {
+		return x.SlotType
+	}
+	return ""
+}
+
+type ListAvailableSlotsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only slots of this type; all types if empty.
+	SlotType      string `protobuf:"bytes,1,opt,name=slot_type,json=slotType,proto3" json:"slot_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAvailableSlotsRequest) Reset() {
+	*x = ListAvailableSlotsRequest{}
+	mi := &file_parking_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAvailableSlotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAvailableSlotsRequest) ProtoMessage() {}
+
+func (x *ListAvailableSlotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAvailableSlotsRequest.ProtoReflect.Descriptor instead.
+func (*ListAvailableSlotsRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListAvailableSlotsRequest) GetSlotType() string {
+	if x != nil {
+		return x.SlotType
+	}
+	return ""
+}
+
+type ListAvailableSlotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slots         []*Slot                `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAvailableSlotsResponse) Reset() {
+	*x = ListAvailableSlotsResponse{}
+	mi := &file_parking_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAvailableSlotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAvailableSlotsResponse) ProtoMessage() {}
+
+func (x *ListAvailableSlotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAvailableSlotsResponse.ProtoReflect.Descriptor instead.
+func (*ListAvailableSlotsResponse) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListAvailableSlotsResponse) GetSlots() []*Slot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+type WatchAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAvailabilityRequest) Reset() {
+	*x = WatchAvailabilityRequest{}
+	mi := &file_parking_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAvailabilityRequest) ProtoMessage() {}
+
+func (x *WatchAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*WatchAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{8}
+}
+
+type Availability struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Free slots by slot type. Types with no free slot are listed with 0.
+	Free          map[string]int32       `protobuf:"bytes,1,rep,name=free,proto3" json:"free,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Total         map[string]int32       `protobuf:"bytes,2,rep,name=total,proto3" json:"total,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Availability) Reset() {
+	*x = Availability{}
+	mi := &file_parking_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Availability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Availability) ProtoMessage() {}
+
+func (x *Availability) ProtoReflect() protoreflect.Message {
+	mi := &file_parking_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Availability.ProtoReflect.Descriptor instead.
+func (*Availability) Descriptor() ([]byte, []int) {
+	return file_parking_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Availability) GetFree() map[string]int32 {
+	if x != nil {
+		return x.Free
+	}
+	return nil
+}
+
+func (x *Availability) GetTotal() map[string]int32 {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Availability) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+var File_parking_proto protoreflect.FileDescriptor
+
+const file_parking_proto_rawDesc = "" +
+	"\n" +
+	"\rparking.proto\x12\n" +
+	"parking.v1\x1a\x1fgoogle/protobuf/timestamp.proto\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\tR\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"U\n" +
+	"\x04Slot\x12\x17\n" +
+	"\aslot_id\x18\x01 \x01(\x05R\x06slotId\x12\x1b\n" +
+	"\tslot_type\x18\x02 \x01(\tR\bslotType\x12\x17\n" +
+	"\ais_free\x18\x03 \x01(\bR\x06isFree\"\xa3\x03\n" +
+	"\x06Ticket\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\x03R\bticketId\x12%\n" +
+	"\x0evehicle_number\x18\x02 \x01(\tR\rvehicleNumber\x12\x17\n" +
+	"\aslot_id\x18\x03 \x01(\x05R\x06slotId\x129\n" +
+	"\n" +
+	"entry_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tentryTime\x127\n" +
+	"\texit_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bexitTime\x12#\n" +
+	"\x03fee\x18\x06 \x01(\v2\x11.parking.v1.MoneyR\x03fee\x12*\n" +
+	"\anet_fee\x18\a \x01(\v2\x11.parking.v1.MoneyR\x06netFee\x12#\n" +
+	"\x03tax\x18\b \x01(\v2\x11.parking.v1.MoneyR\x03tax\x12%\n" +
+	"\x0epayment_method\x18\t \x01(\tR\rpaymentMethod\x12+\n" +
+	"\x11payment_reference\x18\n" +
+	" \x01(\tR\x10paymentReference\"W\n" +
+	"\vParkRequest\x12%\n" +
+	"\x0evehicle_number\x18\x01 \x01(\tR\rvehicleNumber\x12!\n" +
+	"\fvehicle_type\x18\x02 \x01(\tR\vvehicleType\"|\n" +
+	"\rUnparkRequest\x12%\n" +
+	"\x0evehicle_number\x18\x01 \x01(\tR\rvehicleNumber\x12%\n" +
+	"\x0epayment_method\x18\x02 \x01(\tR\rpaymentMethod\x12\x1d\n" +
+	"\n" +
+	"card_token\x18\x03 \x01(\tR\tcardToken\"F\n" +
+	"\x0eAddSlotRequest\x12\x17\n" +
+	"\aslot_id\x18\x01 \x01(\x05R\x06slotId\x12\x1b\n" +
+	"\tslot_type\x18\x02 \x01(\tR\bslotType\"8\n" +
+	"\x19ListAvailableSlotsRequest\x12\x1b\n" +
+	"\tslot_type\x18\x01 \x01(\tR\bslotType\"D\n" +
+	"\x1aListAvailableSlotsResponse\x12&\n" +
+	"\x05slots\x18\x01 \x03(\v2\x10.parking.v1.SlotR\x05slots\"\x1a\n" +
+	"\x18WatchAvailabilityRequest\"\xa0\x02\n" +
+	"\fAvailability\x126\n" +
+	"\x04free\x18\x01 \x03(\v2\".parking.v1.Availability.FreeEntryR\x04free\x129\n" +
+	"\x05total\x18\x02 \x03(\v2#.parking.v1.Availability.TotalEntryR\x05total\x12*\n" +
+	"\x02at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x1a7\n" +
+	"\tFreeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"TotalEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x012\xf3\x02\n" +
+	"\x0eParkingService\x123\n" +
+	"\x04Park\x12\x17.parking.v1.ParkRequest\x1a\x12.parking.v1.Ticket\x127\n" +
+	"\x06Unpark\x12\x19.parking.v1.UnparkRequest\x1a\x12.parking.v1.Ticket\x127\n" +
+	"\aAddSlot\x12\x1a.parking.v1.AddSlotRequest\x1a\x10.parking.v1.Slot\x12c\n" +
+	"\x12ListAvailableSlots\x12%.parking.v1.ListAvailableSlotsRequest\x1a&.parking.v1.ListAvailableSlotsResponse\x12U\n" +
+	"\x11WatchAvailability\x12$.parking.v1.WatchAvailabilityRequest\x1a\x18.parking.v1.Availability0\x01BAZ?parkingSlotManagement/internals/adapters/grpcHandlers/parkingpbb\x06proto3"
+
+var (
+	file_parking_proto_rawDescOnce sync.Once
+	file_parking_proto_rawDescData []byte
+)
+
+func file_parking_proto_rawDescGZIP() []byte {
+	file_parking_proto_rawDescOnce.Do(func() {
+		file_parking_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_parking_proto_rawDesc), len(file_parking_proto_rawDesc)))
+	})
+	return file_parking_proto_rawDescData
+}
+
+var file_parking_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_parking_proto_goTypes = []any{
+	(*Money)(nil),                      // 0: parking.v1.Money
+	(*Slot)(nil),                       // 1: parking.v1.Slot
+	(*Ticket)(nil),                     // 2: parking.v1.Ticket
+	(*ParkRequest)(nil),                // 3: parking.v1.ParkRequest
+	(*UnparkRequest)(nil),              // 4: parking.v1.UnparkRequest
+	(*AddSlotRequest)(nil),             // 5: parking.v1.AddSlotRequest
+	(*ListAvailableSlotsRequest)(nil),  // 6: parking.v1.ListAvailableSlotsRequest
+	(*ListAvailableSlotsResponse)(nil), // 7: parking.v1.ListAvailableSlotsResponse
+	(*WatchAvailabilityRequest)(nil),   // 8: parking.v1.WatchAvailabilityRequest
+	(*Availability)(nil),               // 9: parking.v1.Availability
+	nil,                                // 10: parking.v1.Availability.FreeEntry
+	nil,                                // 11: parking.v1.Availability.TotalEntry
+	(*timestamppb.Timestamp)(nil),      // 12: google.protobuf.Timestamp
+}
+var file_parking_proto_depIdxs = []int32{
+	12, // 0: parking.v1.Ticket.entry_time:type_name -> google.protobuf.Timestamp
+	12, // 1: parking.v1.Ticket.exit_time:type_name -> google.protobuf.Timestamp
+	0,  // 2: parking.v1.Ticket.fee:type_name -> parking.v1.Money
+	0,  // 3: parking.v1.Ticket.net_fee:type_name -> parking.v1.Money
+	0,  // 4: parking.v1.Ticket.tax:type_name -> parking.v1.Money
+	1,  // 5: parking.v1.ListAvailableSlotsResponse.slots:type_name -> parking.v1.Slot
+	10, // 6: parking.v1.Availability.free:type_name -> parking.v1.Availability.FreeEntry
+	11, // 7: parking.v1.Availability.total:type_name -> parking.v1.Availability.TotalEntry
+	12, // 8: parking.v1.Availability.at:type_name -> google.protobuf.Timestamp
+	3,  // 9: parking.v1.ParkingService.Park:input_type -> parking.v1.ParkRequest
+	4,  // 10: parking.v1.ParkingService.Unpark:input_type -> parking.v1.UnparkRequest
+	5,  // 11: parking.v1.ParkingService.AddSlot:input_type -> parking.v1.AddSlotRequest
+	6,  // 12: parking.v1.ParkingService.ListAvailableSlots:input_type -> parking.v1.ListAvailableSlotsRequest
+	8,  // 13: parking.v1.ParkingService.WatchAvailability:input_type -> parking.v1.WatchAvailabilityRequest
+	2,  // 14: parking.v1.ParkingService.Park:output_type -> parking.v1.Ticket
+	2,  // 15: parking.v1.ParkingService.Unpark:output_type -> parking.v1.Ticket
+	1,  // 16: parking.v1.ParkingService.AddSlot:output_type -> parking.v1.Slot
+	7,  // 17: parking.v1.ParkingService.ListAvailableSlots:output_type -> parking.v1.ListAvailableSlotsResponse
+	9,  // 18: parking.v1.ParkingService.WatchAvailability:output_type -> parking.v1.Availability
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_parking_proto_init() }
+func file_parking_proto_init() {
+	if File_parking_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_parking_proto_rawDesc), len(file_parking_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_parking_proto_goTypes,
+		DependencyIndexes: file_parking_proto_depIdxs,
+		MessageInfos:      file_parking_proto_msgTypes,
+	}.Build()
+	File_parking_proto = out.File
+	file_parking_proto_goTypes = nil
+	file_parking_proto_depIdxs = nil
+}
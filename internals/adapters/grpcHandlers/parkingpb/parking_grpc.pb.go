@@ -0,0 +1,299 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: parking.proto
+
+package parkingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ParkingService_Park_FullMethodName               = "/parking.v1.ParkingService/Park"
+	ParkingService_Unpark_FullMethodName             = "/parking.v1.ParkingService/Unpark"
+	ParkingService_AddSlot_FullMethodName            = "/parking.v1.ParkingService/AddSlot"
+	ParkingService_ListAvailableSlots_FullMethodName = "/parking.v1.ParkingService/ListAvailableSlots"
+	ParkingService_WatchAvailability_FullMethodName  = "/parking.v1.ParkingService/WatchAvailability"
+)
+
+// ParkingServiceClient is the client API for ParkingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ParkingService is the gRPC face of the parking lot, for gate controllers
+// and internal services. Calls carry the same credentials as the HTTP API:
+// an "authorization: Bearer <token>" or an "x-api-key" metadata entry.
+type ParkingServiceClient interface {
+	// Park issues a ticket for a vehicle and occupies a free slot of its type.
+	Park(ctx context.Context, in *ParkRequest, opts ...grpc.CallOption) (*Ticket, error)
+	// Unpark collects the fee, closes the vehicle's open ticket and frees its
+	// slot.
+	Unpark(ctx context.Context, in *UnparkRequest, opts ...grpc.CallOption) (*Ticket, error)
+	// AddSlot adds a free slot.
+	AddSlot(ctx context.Context, in *AddSlotRequest, opts ...grpc.CallOption) (*Slot, error)
+	// ListAvailableSlots lists the free slots, optionally of one type.
+	ListAvailableSlots(ctx context.Context, in *ListAvailableSlotsRequest, opts ...grpc.CallOption) (*ListAvailableSlotsResponse, error)
+	// WatchAvailability sends the free slot counts straight away and again
+	// each time they change, until the client cancels.
+	WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Availability], error)
+}
+
+type parkingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewParkingServiceClient(cc grpc.ClientConnInterface) ParkingServiceClient {
+	return &parkingServiceClient{cc}
+}
+
+func (c *parkingServiceClient) Park(ctx context.Context, in *ParkRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ParkingService_Park_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) Unpark(ctx context.Context, in *UnparkRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, ParkingService_Unpark_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) AddSlot(ctx context.Context, in *AddSlotRequest, opts ...grpc.CallOption) (*Slot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Slot)
+	err := c.cc.Invoke(ctx, ParkingService_AddSlot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) ListAvailableSlots(ctx context.Context, in *ListAvailableSlotsRequest, opts ...grpc.CallOption) (*ListAvailableSlotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAvailableSlotsResponse)
+	err := c.cc.Invoke(ctx, ParkingService_ListAvailableSlots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *parkingServiceClient) WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Availability], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ParkingService_ServiceDesc.Streams[0], ParkingService_WatchAvailability_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAvailabilityRequest, Availability]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParkingService_WatchAvailabilityClient = grpc.ServerStreamingClient[Availability]
+
+// ParkingServiceServer is the server API for ParkingService service.
+// All implementations must embed UnimplementedParkingServiceServer
+// for forward compatibility.
+//
+// ParkingService is the gRPC face of the parking lot, for gate controllers
+// and internal services. Calls carry the same credentials as the HTTP API:
+// an "authorization: Bearer <token>" or an "x-api-key" metadata entry.
+type ParkingServiceServer interface {
+	// Park issues a ticket for a vehicle and occupies a free slot of its type.
+	Park(context.Context, *ParkRequest) (*Ticket, error)
+	// Unpark collects the fee, closes the vehicle's open ticket and frees its
+	// slot.
+	Unpark(context.Context, *UnparkRequest) (*Ticket, error)
+	// AddSlot adds a free slot.
+	AddSlot(context.Context, *AddSlotRequest) (*Slot, error)
+	// ListAvailableSlots lists the free slots, optionally of one type.
+	ListAvailableSlots(context.Context, *ListAvailableSlotsRequest) (*ListAvailableSlotsResponse, error)
+	// WatchAvailability sends the free slot counts straight away and again
+	// each time they change, until the client cancels.
+	WatchAvailability(*WatchAvailabilityRequest, grpc.ServerStreamingServer[Availability]) error
+	mustEmbedUnimplementedParkingServiceServer()
+}
+
+// UnimplementedParkingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedParkingServiceServer struct{}
+
+func (UnimplementedParkingServiceServer) Park(context.Context, *ParkRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Park not implemented")
+}
+func (UnimplementedParkingServiceServer) Unpark(context.Context, *UnparkRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unpark not implemented")
+}
+func (UnimplementedParkingServiceServer) AddSlot(context.Context, *AddSlotRequest) (*Slot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSlot not implemented")
+}
+func (UnimplementedParkingServiceServer) ListAvailableSlots(context.Context, *ListAvailableSlotsRequest) (*ListAvailableSlotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAvailableSlots not implemented")
+}
+func (UnimplementedParkingServiceServer) WatchAvailability(*WatchAvailabilityRequest, grpc.ServerStreamingServer[Availability]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAvailability not implemented")
+}
+func (UnimplementedParkingServiceServer) mustEmbedUnimplementedParkingServiceServer() {}
+func (UnimplementedParkingServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeParkingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ParkingServiceServer will
+// result in compilation errors.
+type UnsafeParkingServiceServer interface {
+	mustEmbedUnimplementedParkingServiceServer()
+}
+
+func RegisterParkingServiceServer(s grpc.ServiceRegistrar, srv ParkingServiceServer) {
+	// If the following call pancis, it indicates UnimplementedParkingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ParkingService_ServiceDesc, srv)
+}
+
+func _ParkingService_Park_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).Park(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_Park_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).Park(ctx, req.(*ParkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_Unpark_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnparkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).Unpark(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_Unpark_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).Unpark(ctx, req.(*UnparkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_AddSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).AddSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_AddSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).AddSlot(ctx, req.(*AddSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_ListAvailableSlots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAvailableSlotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParkingServiceServer).ListAvailableSlots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParkingService_ListAvailableSlots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParkingServiceServer).ListAvailableSlots(ctx, req.(*ListAvailableSlotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParkingService_WatchAvailability_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAvailabilityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ParkingServiceServer).WatchAvailability(m, &grpc.GenericServerStream[WatchAvailabilityRequest, Availability]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParkingService_WatchAvailabilityServer = grpc.ServerStreamingServer[Availability]
+
+// ParkingService_ServiceDesc is the grpc.ServiceDesc for ParkingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ParkingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "parking.v1.ParkingService",
+	HandlerType: (*ParkingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Park",
+			Handler:    _ParkingService_Park_Handler,
+		},
+		{
+			MethodName: "Unpark",
+			Handler:    _ParkingService_Unpark_Handler,
+		},
+		{
+			MethodName: "AddSlot",
+			Handler:    _ParkingService_AddSlot_Handler,
+		},
+		{
+			MethodName: "ListAvailableSlots",
+			Handler:    _ParkingService_ListAvailableSlots_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAvailability",
+			Handler:       _ParkingService_WatchAvailability_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "parking.proto",
+}
@@ -0,0 +1,173 @@
+// Package grpcHandlers serves the parking service over gRPC, as defined in
+// parkingpb/parking.proto.
+package grpcHandlers
+
+import (
+	"context"
+	"parkingSlotManagement/internals/adapters/grpcHandlers/parkingpb"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/auth"
+	"parkingSlotManagement/internals/core/services/availability"
+	"parkingSlotManagement/internals/core/services/parking"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Server struct {
+	parkingpb.UnimplementedParkingServiceServer
+	service   *parking.ParkingService
+	feed      *availability.Feed
+	validator *dto.Validator
+}
+
+// NewServer serves service, with availability pushed from feed, the same
+// feed the HTTP availability streams use.
+func NewServer(service *parking.ParkingService, feed *availability.Feed) *Server {
+	return &Server{
+		service:   service,
+		feed:      feed,
+		validator: dto.NewValidator(service),
+	}
+}
+
+// NewGRPCServer returns a gRPC server answering for server, with every call
+// authenticated against authService.
+func NewGRPCServer(server *Server, authService auth.AuthService, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(UnaryAuthInterceptor(authService)),
+		grpc.ChainStreamInterceptor(StreamAuthInterceptor(authService)),
+	)
+	gs := grpc.NewServer(opts...)
+	parkingpb.RegisterParkingServiceServer(gs, server)
+	return gs
+}
+
+func (s *Server) Park(ctx context.Context, req *parkingpb.ParkRequest) (*parkingpb.Ticket, error) {
+	vehicle := dto.ParkVehicle{VehicleNumber: req.GetVehicleNumber(), VehicleType: req.GetVehicleType()}
+	if err := s.validator.Validate(vehicle); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	ticket, err := s.service.ParkVehicle(ctx, vehicle.Vehicle())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toTicket(ticket), nil
+}
+
+func (s *Server) Unpark(ctx context.Context, req *parkingpb.UnparkRequest) (*parkingpb.Ticket, error) {
+	unpark := dto.UnparkVehicle{
+		VehicleNumber: req.GetVehicleNumber(),
+		Payment:       dto.Payment{Method: req.GetPaymentMethod(), CardToken: req.GetCardToken()},
+	}
+	if err := s.validator.Validate(unpark); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	number, err := s.service.NormaliseVehicleNumber(unpark.VehicleNumber)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	ticket, err := s.service.UnparkVehicle(ctx, number, unpark.PaymentRequest())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toTicket(ticket), nil
+}
+
+func (s *Server) AddSlot(ctx context.Context, req *parkingpb.AddSlotRequest) (*parkingpb.Slot, error) {
+	create := dto.CreateSlot{SlotId: int(req.GetSlotId()), SlotType: req.GetSlotType(), IsFree: true}
+	if err := s.validator.Validate(create); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	slot := create.Slot()
+	if err := s.service.AddSlot(ctx, slot); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return toSlot(slot), nil
+}
+
+func (s *Server) ListAvailableSlots(ctx context.Context, req *parkingpb.ListAvailableSlotsRequest) (*parkingpb.ListAvailableSlotsResponse, error) {
+	slots, err := s.service.GetAvailableSlots(ctx)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	resp := &parkingpb.ListAvailableSlotsResponse{}
+	for _, slot := range slots {
+		if req.GetSlotType() == "" || slot.SlotType == req.GetSlotType() {
+			resp.Slots = append(resp.Slots, toSlot(slot))
+		}
+	}
+	return resp, nil
+}
+
+// WatchAvailability sends the current counts, then the counts after every
+// slot and ticket event.
+func (s *Server) WatchAvailability(req *parkingpb.WatchAvailabilityRequest, stream grpc.ServerStreamingServer[parkingpb.Availability]) error {
+	ctx := stream.Context()
+	sub, first, err := s.feed.Subscribe(ctx, 0)
+	if err != nil {
+		return statusError(ctx, err)
+	}
+	defer sub.Close()
+
+	if err := stream.Send(toAvailability(first)); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toAvailability(update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toAvailability(update availability.Update) *parkingpb.Availability {
+	result := &parkingpb.Availability{
+		Free:  map[string]int32{},
+		Total: map[string]int32{},
+		At:    timestamppb.New(update.At),
+	}
+	for slotType, count := range update.Slots {
+		result.Free[slotType] = int32(count.Free)
+		result.Total[slotType] = int32(count.Total)
+	}
+	return result
+}
+
+func toSlot(slot domain.Slot) *parkingpb.Slot {
+	return &parkingpb.Slot{SlotId: int32(slot.SlotId), SlotType: slot.SlotType, IsFree: slot.IsFree}
+}
+
+func toMoney(m domain.Money) *parkingpb.Money {
+	currency := m.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	return &parkingpb.Money{Amount: m.Decimal(), Currency: string(currency)}
+}
+
+func toTicket(ticket *domain.Ticket) *parkingpb.Ticket {
+	pb := &parkingpb.Ticket{
+		TicketId:         ticket.TicketId,
+		VehicleNumber:    ticket.VehicleNumber,
+		SlotId:           int32(ticket.SlotId),
+		EntryTime:        timestamppb.New(ticket.EntryTime),
+		PaymentMethod:    ticket.PaymentMethod,
+		PaymentReference: ticket.PaymentReference,
+	}
+	if ticket.ExitTime != nil {
+		pb.ExitTime = timestamppb.New(*ticket.ExitTime)
+		pb.Fee = toMoney(ticket.Fee)
+		pb.NetFee = toMoney(ticket.NetFee)
+		pb.Tax = toMoney(ticket.Tax)
+	}
+	return pb
+}
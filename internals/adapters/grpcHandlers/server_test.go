@@ -0,0 +1,244 @@
+package grpcHandlers
+
+import (
+	"context"
+	"net"
+	"os"
+	"parkingSlotManagement/internals/adapters/grpcHandlers/parkingpb"
+	"parkingSlotManagement/internals/adapters/payments"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/auth"
+	"parkingSlotManagement/internals/core/services/availability"
+	"parkingSlotManagement/internals/core/services/events"
+	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/ports"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testEnv struct {
+	client      parkingpb.ParkingServiceClient
+	authService *auth.AuthServiceImpl
+	admin       context.Context
+}
+
+// newTestEnv serves a fresh in-memory lot over an in-process listener and
+// returns a client for it, with a context logged in as admin.
+func newTestEnv(t *testing.T) testEnv {
+	os.Setenv("JWT_SECRET", "testsecret-testsecret-testsecret")
+	service := parking.NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCash: payments.NewCashGateway()}
+	authService := auth.NewAuthService(inmemmory.NewUserInMemmory(), inmemmory.NewRevocationInMemmory())
+	authService.HashCost = bcrypt.MinCost
+	authService.APIKeys = inmemmory.NewAPIKeyInMemmory()
+	if _, err := authService.Bootstrap(context.Background(), "admin", "admin123"); err != nil {
+		t.Fatalf("Bootstrap failed: %v", err)
+	}
+
+	bus := events.NewBus()
+	service.Events = bus
+	server := NewServer(service, availability.NewFeed(service, bus))
+	listener := bufconn.Listen(1 << 20)
+	gs := NewGRPCServer(server, authService)
+	go gs.Serve(listener)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return testEnv{
+		client:      parkingpb.NewParkingServiceClient(conn),
+		authService: authService,
+		admin:       loggedIn(t, authService, "admin", "admin123"),
+	}
+}
+
+func loggedIn(t *testing.T, authService *auth.AuthServiceImpl, username, password string) context.Context {
+	tokens, err := authService.Login(context.Background(), username, password)
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+tokens.AccessToken)
+}
+
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestParkAndUnpark(t *testing.T) {
+	env := newTestEnv(t)
+	if _, err := env.client.AddSlot(env.admin, &parkingpb.AddSlotRequest{SlotId: 1, SlotType: "car"}); err != nil {
+		t.Fatalf("AddSlot failed: %v", err)
+	}
+
+	ticket, err := env.client.Park(env.admin, &parkingpb.ParkRequest{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	if err != nil {
+		t.Fatalf("Park failed: %v", err)
+	}
+	if ticket.GetSlotId() != 1 || ticket.GetExitTime() != nil {
+		t.Errorf("Expected an open ticket for slot 1, got %v", ticket)
+	}
+	_, err = env.client.Park(env.admin, &parkingpb.ParkRequest{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition parking twice, got %v", err)
+	}
+
+	slots, err := env.client.ListAvailableSlots(env.admin, &parkingpb.ListAvailableSlotsRequest{})
+	if err != nil || len(slots.GetSlots()) != 0 {
+		t.Errorf("Expected no free slots, got %v, %v", slots, err)
+	}
+
+	closed, err := env.client.Unpark(env.admin, &parkingpb.UnparkRequest{VehicleNumber: "UP16AB1234"})
+	if err != nil {
+		t.Fatalf("Unpark failed: %v", err)
+	}
+	if closed.GetExitTime() == nil || closed.GetFee().GetCurrency() == "" {
+		t.Errorf("Expected a closed ticket with its fee, got %v", closed)
+	}
+	_, err = env.client.Unpark(env.admin, &parkingpb.UnparkRequest{VehicleNumber: "UP16AB1234"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound unparking twice, got %v", err)
+	}
+
+	slots, err = env.client.ListAvailableSlots(env.admin, &parkingpb.ListAvailableSlotsRequest{SlotType: "car"})
+	if err != nil || len(slots.GetSlots()) != 1 {
+		t.Errorf("Expected the slot to be free again, got %v, %v", slots, err)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := env.client.AddSlot(env.admin, &parkingpb.AddSlotRequest{SlotId: 0, SlotType: "boat"})
+	if status.Code(err) != codes.InvalidArgument || errorReason(err) != "validation_failed" {
+		t.Fatalf("Expected InvalidArgument validation_failed, got %v", err)
+	}
+	fields := map[string]bool{}
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields[violation.GetField()] = true
+			}
+		}
+	}
+	if !fields["slotid"] || !fields["slottype"] {
+		t.Errorf("Expected field violations for slotid and slottype, got %v", fields)
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.client.ListAvailableSlots(ctx, &parkingpb.ListAvailableSlotsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without credentials, got %v", err)
+	}
+	bad := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer not-a-token")
+	if _, err := env.client.ListAvailableSlots(bad, &parkingpb.ListAvailableSlotsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with a bad token, got %v", err)
+	}
+
+	if _, err := env.authService.CreateUser(ctx, domain.User{Username: "gate", Role: domain.RoleAttendant}, "gate1234"); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	attendant := loggedIn(t, env.authService, "gate", "gate1234")
+	if _, err := env.client.ListAvailableSlots(attendant, &parkingpb.ListAvailableSlotsRequest{}); err != nil {
+		t.Errorf("Expected an attendant to list slots, got %v", err)
+	}
+	if _, err := env.client.AddSlot(attendant, &parkingpb.AddSlotRequest{SlotId: 1, SlotType: "car"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for an attendant adding a slot, got %v", err)
+	}
+
+	_, key, err := env.authService.CreateAPIKey(ctx, "barrier", []string{string(domain.PermParkingOperate)})
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	withKey := metadata.AppendToOutgoingContext(ctx, APIKeyMetadata, key)
+	if _, err := env.client.ListAvailableSlots(withKey, &parkingpb.ListAvailableSlotsRequest{}); err != nil {
+		t.Errorf("Expected the API key to list slots, got %v", err)
+	}
+	if _, err := env.client.AddSlot(withKey, &parkingpb.AddSlotRequest{SlotId: 1, SlotType: "car"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a key without slots:manage, got %v", err)
+	}
+
+	stream, err := env.client.WatchAvailability(ctx, &parkingpb.WatchAvailabilityRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated watching without credentials, got %v", err)
+	}
+}
+
+func TestWatchAvailability(t *testing.T) {
+	env := newTestEnv(t)
+	env.client.AddSlot(env.admin, &parkingpb.AddSlotRequest{SlotId: 1, SlotType: "car"})
+	env.client.AddSlot(env.admin, &parkingpb.AddSlotRequest{SlotId: 2, SlotType: "car"})
+
+	ctx, cancel := context.WithTimeout(env.admin, 5*time.Second)
+	defer cancel()
+	stream, err := env.client.WatchAvailability(ctx, &parkingpb.WatchAvailabilityRequest{})
+	if err != nil {
+		t.Fatalf("WatchAvailability failed: %v", err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected the current counts, got %v", err)
+	}
+	if first.GetFree()["car"] != 2 || first.GetTotal()["car"] != 2 {
+		t.Errorf("Expected 2 of 2 car slots free, got %v", first)
+	}
+
+	if _, err := env.client.Park(env.admin, &parkingpb.ParkRequest{VehicleNumber: "UP16AB1234", VehicleType: "car"}); err != nil {
+		t.Fatalf("Park failed: %v", err)
+	}
+	next, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected the counts after parking, got %v", err)
+	}
+	if next.GetFree()["car"] != 1 || next.GetTotal()["car"] != 2 {
+		t.Errorf("Expected 1 of 2 car slots free, got %v", next)
+	}
+}
+
+func TestWatchAvailabilityWithDisplayKey(t *testing.T) {
+	env := newTestEnv(t)
+	env.client.AddSlot(env.admin, &parkingpb.AddSlotRequest{SlotId: 1, SlotType: "car"})
+	_, key, err := env.authService.CreateAPIKey(context.Background(), "lobby board", []string{"display"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	board := metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadata, key)
+
+	ctx, cancel := context.WithTimeout(board, 5*time.Second)
+	defer cancel()
+	stream, err := env.client.WatchAvailability(ctx, &parkingpb.WatchAvailabilityRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if err != nil {
+		t.Errorf("Expected a display key to watch availability, got %v", err)
+	}
+	if _, err := env.client.ListAvailableSlots(board, &parkingpb.ListAvailableSlotsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a display key listing slots, got %v", err)
+	}
+}
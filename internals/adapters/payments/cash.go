@@ -0,0 +1,35 @@
+package payments
+
+import "parkingSlotManagement/internals/core/domain"
+
+// CashGateway records cash taken by the attendant. Cash is never declined,
+// so authorisation only checks the amount.
+type CashGateway struct {
+	store *store
+}
+
+func NewCashGateway() *CashGateway {
+	return &CashGateway{store: newStore("cash")}
+}
+
+func (g *CashGateway) Authorise(req domain.PaymentRequest) (*domain.Payment, error) {
+	if !req.Amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+	req.Method = domain.PaymentCash
+	return g.store.add(req, domain.PaymentAuthorised), nil
+}
+
+func (g *CashGateway) Capture(reference string) (*domain.Payment, error) {
+	return g.store.update(reference, capture)
+}
+
+func (g *CashGateway) Refund(reference string, amount domain.Money) (*domain.Payment, error) {
+	return g.store.update(reference, func(p *domain.Payment) error {
+		return refund(p, amount)
+	})
+}
+
+func (g *CashGateway) Status(reference string) (*domain.Payment, error) {
+	return g.store.get(reference)
+}
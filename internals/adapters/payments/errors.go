@@ -0,0 +1,11 @@
+package payments
+
+import "errors"
+
+var (
+	ErrPaymentNotFound   = errors.New("payment not found")
+	ErrPaymentDeclined   = errors.New("payment declined")
+	ErrInvalidAmount     = errors.New("invalid payment amount")
+	ErrInvalidTransition = errors.New("payment is not in a state that allows this operation")
+	ErrCaptureFailed     = errors.New("payment capture failed")
+)
@@ -0,0 +1,61 @@
+package payments
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"sync"
+)
+
+// Card tokens with a fixed outcome on the fake gateway. Any other non-empty
+// token is approved.
+const (
+	FakeCardDeclineToken     = "tok_decline"
+	FakeCardCaptureFailToken = "tok_capture_fail"
+)
+
+// FakeCardGateway is a deterministic stand-in for a card processor, used in
+// tests and local development.
+type FakeCardGateway struct {
+	store  *store
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func NewFakeCardGateway() *FakeCardGateway {
+	return &FakeCardGateway{store: newStore("card"), tokens: make(map[string]string)}
+}
+
+func (g *FakeCardGateway) Authorise(req domain.PaymentRequest) (*domain.Payment, error) {
+	if !req.Amount.IsPositive() {
+		return nil, ErrInvalidAmount
+	}
+	req.Method = domain.PaymentCard
+	if req.CardToken == "" || req.CardToken == FakeCardDeclineToken {
+		g.store.add(req, domain.PaymentDeclined)
+		return nil, ErrPaymentDeclined
+	}
+	payment := g.store.add(req, domain.PaymentAuthorised)
+	g.mu.Lock()
+	g.tokens[payment.Reference] = req.CardToken
+	g.mu.Unlock()
+	return payment, nil
+}
+
+func (g *FakeCardGateway) Capture(reference string) (*domain.Payment, error) {
+	g.mu.Lock()
+	token := g.tokens[reference]
+	g.mu.Unlock()
+	if token == FakeCardCaptureFailToken {
+		return nil, ErrCaptureFailed
+	}
+	return g.store.update(reference, capture)
+}
+
+func (g *FakeCardGateway) Refund(reference string, amount domain.Money) (*domain.Payment, error) {
+	return g.store.update(reference, func(p *domain.Payment) error {
+		return refund(p, amount)
+	})
+}
+
+func (g *FakeCardGateway) Status(reference string) (*domain.Payment, error) {
+	return g.store.get(reference)
+}
@@ -0,0 +1,84 @@
+package payments
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func inr(rupees int64) domain.Money {
+	return domain.NewMoney(rupees*100, domain.CurrencyINR)
+}
+
+func TestCashGateway(t *testing.T) {
+	g := NewCashGateway()
+
+	_, err := g.Authorise(domain.PaymentRequest{TicketId: 1, Amount: inr(0)})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	authorised, err := g.Authorise(domain.PaymentRequest{TicketId: 1, Amount: inr(120)})
+	assert.NoError(t, err)
+	assert.Equal(t, "cash_000001", authorised.Reference)
+	assert.Equal(t, domain.PaymentAuthorised, authorised.Status)
+
+	_, err = g.Refund(authorised.Reference, inr(10))
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	captured, err := g.Capture(authorised.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentCaptured, captured.Status)
+
+	_, err = g.Capture(authorised.Reference)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	partial, err := g.Refund(authorised.Reference, inr(20))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentCaptured, partial.Status)
+	assert.Equal(t, inr(20), partial.RefundedAmount)
+
+	_, err = g.Refund(authorised.Reference, inr(101))
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	_, err = g.Refund(authorised.Reference, domain.NewMoney(1000, "USD"))
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	full, err := g.Refund(authorised.Reference, inr(100))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentRefunded, full.Status)
+
+	status, err := g.Status(authorised.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentRefunded, status.Status)
+
+	_, err = g.Status("cash_999999")
+	assert.ErrorIs(t, err, ErrPaymentNotFound)
+}
+
+func TestFakeCardGateway(t *testing.T) {
+	g := NewFakeCardGateway()
+
+	_, err := g.Authorise(domain.PaymentRequest{TicketId: 1, Amount: inr(50), CardToken: FakeCardDeclineToken})
+	assert.ErrorIs(t, err, ErrPaymentDeclined)
+
+	declined, err := g.Status("card_000001")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentDeclined, declined.Status)
+
+	_, err = g.Authorise(domain.PaymentRequest{TicketId: 1, Amount: inr(50)})
+	assert.ErrorIs(t, err, ErrPaymentDeclined)
+
+	authorised, err := g.Authorise(domain.PaymentRequest{TicketId: 1, Amount: inr(50), CardToken: FakeCardCaptureFailToken})
+	assert.NoError(t, err)
+	_, err = g.Capture(authorised.Reference)
+	assert.ErrorIs(t, err, ErrCaptureFailed)
+
+	authorised, err = g.Authorise(domain.PaymentRequest{TicketId: 2, Amount: inr(50), CardToken: "tok_visa"})
+	assert.NoError(t, err)
+	assert.Equal(t, "card_000004", authorised.Reference)
+	assert.Equal(t, domain.PaymentCard, authorised.Method)
+
+	captured, err := g.Capture(authorised.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentCaptured, captured.Status)
+}
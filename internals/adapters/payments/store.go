@@ -0,0 +1,83 @@
+package payments
+
+import (
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"sync"
+	"time"
+)
+
+// store keeps the payments a gateway has seen and hands out sequential
+// references, which keeps both gateways deterministic.
+type store struct {
+	mu       sync.Mutex
+	prefix   string
+	seq      int
+	payments map[string]*domain.Payment
+}
+
+func newStore(prefix string) *store {
+	return &store{prefix: prefix, payments: make(map[string]*domain.Payment)}
+}
+
+func (s *store) add(req domain.PaymentRequest, status string) *domain.Payment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	payment := &domain.Payment{
+		Reference: fmt.Sprintf("%s_%06d", s.prefix, s.seq),
+		TicketId:  req.TicketId,
+		Method:    req.Method,
+		Amount:    req.Amount,
+		Status:    status,
+		UpdatedAt: time.Now(),
+	}
+	s.payments[payment.Reference] = payment
+	snapshot := *payment
+	return &snapshot
+}
+
+// update applies fn to the stored payment under the lock and returns a copy.
+func (s *store) update(reference string, fn func(p *domain.Payment) error) (*domain.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payment, ok := s.payments[reference]
+	if !ok {
+		return nil, ErrPaymentNotFound
+	}
+	if err := fn(payment); err != nil {
+		return nil, err
+	}
+	payment.UpdatedAt = time.Now()
+	snapshot := *payment
+	return &snapshot, nil
+}
+
+func (s *store) get(reference string) (*domain.Payment, error) {
+	return s.update(reference, func(p *domain.Payment) error { return nil })
+}
+
+func capture(p *domain.Payment) error {
+	if p.Status != domain.PaymentAuthorised {
+		return ErrInvalidTransition
+	}
+	p.Status = domain.PaymentCaptured
+	return nil
+}
+
+func refund(p *domain.Payment, amount domain.Money) error {
+	if p.Status != domain.PaymentCaptured && p.Status != domain.PaymentRefunded {
+		return ErrInvalidTransition
+	}
+	if !amount.IsPositive() || amount.Currency != p.Amount.Currency {
+		return ErrInvalidAmount
+	}
+	if p.RefundedAmount.Add(amount).Cmp(p.Amount) > 0 {
+		return ErrInvalidAmount
+	}
+	p.RefundedAmount = p.RefundedAmount.Add(amount)
+	if p.RefundedAmount.Cmp(p.Amount) == 0 {
+		p.Status = domain.PaymentRefunded
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package inmemmory
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"sort"
+	"sync"
+)
+
+type AdjustmentInMemmory struct {
+	mu          sync.Mutex
+	adjustments map[int64]*domain.FeeAdjustment
+}
+
+func NewAdjustmentInMemmory() *AdjustmentInMemmory {
+	return &AdjustmentInMemmory{adjustments: make(map[int64]*domain.FeeAdjustment)}
+}
+
+func (a *AdjustmentInMemmory) SaveAdjustment(ctx context.Context, adjustment domain.FeeAdjustment) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.adjustments[adjustment.AdjustmentId] = &adjustment
+	return nil
+}
+
+func (a *AdjustmentInMemmory) UpdateAdjustmentFrom(ctx context.Context, adjustment domain.FeeAdjustment, status string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stored, ok := a.adjustments[adjustment.AdjustmentId]
+	if !ok || stored.Status != status {
+		return false, nil
+	}
+	a.adjustments[adjustment.AdjustmentId] = &adjustment
+	return true, nil
+}
+
+func (a *AdjustmentInMemmory) FindAdjustmentByID(ctx context.Context, adjustmentid int64) (*domain.FeeAdjustment, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	adjustment, ok := a.adjustments[adjustmentid]
+	if !ok {
+		return nil, fmt.Errorf("adjustment %d not exists", adjustmentid)
+	}
+	found := *adjustment
+	return &found, nil
+}
+
+func (a *AdjustmentInMemmory) ListAdjustments(ctx context.Context, status string) ([]domain.FeeAdjustment, error) {
+	return a.filter(func(adj *domain.FeeAdjustment) bool {
+		return status == "" || adj.Status == status
+	}), nil
+}
+
+func (a *AdjustmentInMemmory) ListAdjustmentsByTicket(ctx context.Context, ticketid int64) ([]domain.FeeAdjustment, error) {
+	return a.filter(func(adj *domain.FeeAdjustment) bool {
+		return adj.TicketId == ticketid
+	}), nil
+}
+
+func (a *AdjustmentInMemmory) filter(keep func(adj *domain.FeeAdjustment) bool) []domain.FeeAdjustment {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var adjustments []domain.FeeAdjustment
+	for _, adj := range a.adjustments {
+		if keep(adj) {
+			adjustments = append(adjustments, *adj)
+		}
+	}
+	sort.Slice(adjustments, func(i, j int) bool {
+		return adjustments[i].AdjustmentId < adjustments[j].AdjustmentId
+	})
+	return adjustments
+}
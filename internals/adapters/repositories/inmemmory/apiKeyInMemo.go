@@ -0,0 +1,61 @@
+package inmemmory
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"sort"
+	"sync"
+)
+
+type APIKeyInMemmory struct {
+	mu   sync.Mutex
+	keys map[string]domain.APIKey
+}
+
+func NewAPIKeyInMemmory() *APIKeyInMemmory {
+	return &APIKeyInMemmory{keys: make(map[string]domain.APIKey)}
+}
+
+func (a *APIKeyInMemmory) SaveAPIKey(ctx context.Context, key domain.APIKey) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.keys[key.ID]; ok {
+		return fmt.Errorf("api key %s already exists", key.ID)
+	}
+	a.keys[key.ID] = key
+	return nil
+}
+
+func (a *APIKeyInMemmory) UpdateAPIKey(ctx context.Context, key domain.APIKey) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.keys[key.ID]; !ok {
+		return fmt.Errorf("api key %s not exists", key.ID)
+	}
+	a.keys[key.ID] = key
+	return nil
+}
+
+func (a *APIKeyInMemmory) FindAPIKeyByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key, ok := a.keys[id]
+	if !ok {
+		return nil, nil
+	}
+	return &key, nil
+}
+
+func (a *APIKeyInMemmory) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var keys []domain.APIKey
+	for _, key := range a.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
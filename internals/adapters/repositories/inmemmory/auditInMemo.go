@@ -0,0 +1,46 @@
+package inmemmory
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"sync"
+)
+
+type AuditInMemmory struct {
+	mu      sync.Mutex
+	entries []domain.AuditEntry
+}
+
+func NewAuditInMemmory() *AuditInMemmory {
+	return &AuditInMemmory{}
+}
+
+func (a *AuditInMemmory) Record(ctx context.Context, entry domain.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry.EntryId = int64(len(a.entries) + 1)
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func (a *AuditInMemmory) ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var entries []domain.AuditEntry
+	for i := len(a.entries) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+		if filter.Matches(a.entries[i]) {
+			entries = append(entries, a.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+// Entries returns everything recorded so far, oldest first.
+func (a *AuditInMemmory) Entries() []domain.AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]domain.AuditEntry(nil), a.entries...)
+}
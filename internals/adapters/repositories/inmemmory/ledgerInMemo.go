@@ -0,0 +1,43 @@
+package inmemmory
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type LedgerInMemmory struct {
+	entries []domain.LedgerEntry
+}
+
+func NewLedgerInMemmory() *LedgerInMemmory {
+	return &LedgerInMemmory{}
+}
+
+func (l *LedgerInMemmory) SaveLedgerEntry(ctx context.Context, entry domain.LedgerEntry) error {
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (l *LedgerInMemmory) ListLedgerEntries(ctx context.Context, vehiclenumber string) ([]domain.LedgerEntry, error) {
+	var entries []domain.LedgerEntry
+	for _, entry := range l.entries {
+		if entry.VehicleNumber == vehiclenumber {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (l *LedgerInMemmory) GetBalance(ctx context.Context, vehiclenumber string) (domain.Money, error) {
+	var balance domain.Money
+	for _, entry := range l.entries {
+		if entry.VehicleNumber == vehiclenumber {
+			balance = balance.Add(entry.Amount)
+		}
+	}
+	return balance, nil
+}
+
+func (l *LedgerInMemmory) LockBalance(ctx context.Context, vehiclenumber string) (domain.Money, error) {
+	return l.GetBalance(ctx, vehiclenumber)
+}
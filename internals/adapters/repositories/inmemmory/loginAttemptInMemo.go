@@ -0,0 +1,54 @@
+package inmemmory
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"sort"
+	"sync"
+)
+
+type LoginAttemptInMemmory struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempts
+}
+
+func NewLoginAttemptInMemmory() *LoginAttemptInMemmory {
+	return &LoginAttemptInMemmory{attempts: make(map[string]domain.LoginAttempts)}
+}
+
+func (l *LoginAttemptInMemmory) FindLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	attempts, ok := l.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempts, nil
+}
+
+func (l *LoginAttemptInMemmory) SaveLoginAttempts(ctx context.Context, attempts domain.LoginAttempts) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.attempts[attempts.Key] = attempts
+	return nil
+}
+
+func (l *LoginAttemptInMemmory) DeleteLoginAttempts(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.attempts, key)
+	return nil
+}
+
+func (l *LoginAttemptInMemmory) ListLoginAttempts(ctx context.Context) ([]domain.LoginAttempts, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var list []domain.LoginAttempts
+	for _, attempts := range l.attempts {
+		list = append(list, attempts)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+	return list, nil
+}
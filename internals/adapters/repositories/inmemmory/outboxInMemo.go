@@ -0,0 +1,93 @@
+package inmemmory
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"sync"
+	"time"
+)
+
+// OutboxInMemmory keeps the outbox in memory. There are no transactions
+// here, so events are stored as soon as they are added.
+type OutboxInMemmory struct {
+	mu       sync.Mutex
+	nextID   int64
+	messages []domain.OutboxMessage
+	claims   map[int64]time.Time
+}
+
+func NewOutboxInMemmory() *OutboxInMemmory {
+	return &OutboxInMemmory{claims: make(map[int64]time.Time)}
+}
+
+func (o *OutboxInMemmory) AddEvents(ctx context.Context, events ...domain.Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	for _, event := range events {
+		o.nextID++
+		o.messages = append(o.messages, domain.OutboxMessage{ID: o.nextID, Event: event, CreatedAt: now})
+	}
+	return nil
+}
+
+func (o *OutboxInMemmory) ListUnpublished(ctx context.Context, limit int, now time.Time) ([]domain.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var messages []domain.OutboxMessage
+	for _, message := range o.messages {
+		if len(messages) == limit {
+			break
+		}
+		if until, claimed := o.claims[message.ID]; claimed && !until.Before(now) {
+			continue
+		}
+		if message.PublishedAt == nil {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+func (o *OutboxInMemmory) Claim(ctx context.Context, ids []int64, until time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, id := range ids {
+		if until.IsZero() {
+			delete(o.claims, id)
+		} else {
+			o.claims[id] = until
+		}
+	}
+	return nil
+}
+
+func (o *OutboxInMemmory) MarkPublished(ctx context.Context, ids []int64, at time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	marked := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		marked[id] = true
+	}
+	for i := range o.messages {
+		if marked[o.messages[i].ID] {
+			publishedAt := at
+			o.messages[i].PublishedAt = &publishedAt
+		}
+	}
+	return nil
+}
+
+func (o *OutboxInMemmory) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	kept := o.messages[:0]
+	for _, message := range o.messages {
+		if message.PublishedAt == nil || !message.PublishedAt.Before(before) {
+			kept = append(kept, message)
+		}
+	}
+	deleted := int64(len(o.messages) - len(kept))
+	o.messages = kept
+	return deleted, nil
+}
@@ -0,0 +1,39 @@
+package inmemmory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type RevocationInMemmory struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+func NewRevocationInMemmory() *RevocationInMemmory {
+	return &RevocationInMemmory{revoked: make(map[string]time.Time)}
+}
+
+func (r *RevocationInMemmory) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for id, expiry := range r.revoked {
+		if expiry.Before(now) {
+			delete(r.revoked, id)
+		}
+	}
+	if _, ok := r.revoked[tokenID]; ok {
+		return false, nil
+	}
+	r.revoked[tokenID] = expiresAt
+	return true, nil
+}
+
+func (r *RevocationInMemmory) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.revoked[tokenID]
+	return ok, nil
+}
@@ -1,8 +1,11 @@
 package inmemmory
 
 import (
+	"context"
 	"fmt"
 	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"sort"
 )
 
 type SlotInMemmory struct {
@@ -14,20 +17,32 @@ func NewSlotInMemmory() *SlotInMemmory {
 		slots: make(map[int]*domain.Slot)}
 }
 
-func (s *SlotInMemmory) SaveSlot(slot domain.Slot) error {
+func (s *SlotInMemmory) SaveSlot(ctx context.Context, slot domain.Slot) error {
+	if _, ok := s.slots[slot.SlotId]; ok {
+		return ports.ErrSlotExists
+	}
 	s.slots[slot.SlotId] = &slot
 	return nil
 }
-func (s *SlotInMemmory) UpdateSlot(slot *domain.Slot) error {
+func (s *SlotInMemmory) UpdateSlot(ctx context.Context, slot *domain.Slot) error {
 	existSlot, ok := s.slots[slot.SlotId]
 	if !ok {
 		return fmt.Errorf("slot of this id %d  not exists", slot.SlotId)
 	}
 	existSlot.IsFree = slot.IsFree
 	existSlot.SlotType = slot.SlotType
+	existSlot.UpdatedBy = slot.UpdatedBy
 	return nil
 }
-func (s *SlotInMemmory) ListAvailableSlots() ([]domain.Slot, error) {
+func (s *SlotInMemmory) ListSlots(ctx context.Context) ([]domain.Slot, error) {
+	var slots []domain.Slot
+	for _, slot := range s.slots {
+		slots = append(slots, *slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].SlotId < slots[j].SlotId })
+	return slots, nil
+}
+func (s *SlotInMemmory) ListAvailableSlots(ctx context.Context) ([]domain.Slot, error) {
 	var availableSlots []domain.Slot
 	for _, slot := range s.slots {
 		if slot.IsFree {
@@ -37,7 +52,7 @@ func (s *SlotInMemmory) ListAvailableSlots() ([]domain.Slot, error) {
 	}
 	return availableSlots, nil
 }
-func (s *SlotInMemmory) FindSlotByType(SlotType string) ([]domain.Slot, error) {
+func (s *SlotInMemmory) FindSlotByType(ctx context.Context, SlotType string) ([]domain.Slot, error) {
 	var availableSlots []domain.Slot
 	for _, slot := range s.slots {
 		if slot.SlotType == SlotType {
@@ -46,7 +61,7 @@ func (s *SlotInMemmory) FindSlotByType(SlotType string) ([]domain.Slot, error) {
 	}
 	return availableSlots, nil
 }
-func (s *SlotInMemmory) FindSlotTypebyID(SlotID int) (string, error) {
+func (s *SlotInMemmory) FindSlotTypebyID(ctx context.Context, SlotID int) (string, error) {
 
 	existSlot, ok := s.slots[SlotID]
 	if !ok {
@@ -54,7 +69,7 @@ func (s *SlotInMemmory) FindSlotTypebyID(SlotID int) (string, error) {
 	}
 	return existSlot.SlotType, nil
 }
-func (s *SlotInMemmory) FindSlotByID(SlotId int) (*domain.Slot, error) {
+func (s *SlotInMemmory) FindSlotByID(ctx context.Context, SlotId int) (*domain.Slot, error) {
 	existsSlot, ok := s.slots[SlotId]
 	if !ok {
 		return nil, fmt.Errorf("slot of %d id not exists", SlotId)
@@ -1,9 +1,12 @@
 package inmemmory
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"parkingSlotManagement/internals/core/domain"
+	"sort"
+	"time"
 )
 
 type TicketInMemmory struct {
@@ -13,11 +16,18 @@ type TicketInMemmory struct {
 func NewTicketInMemmory() *TicketInMemmory {
 	return &TicketInMemmory{Tickets: make(map[int64]*domain.Ticket)}
 }
-func (t *TicketInMemmory) SaveTicket(ticket domain.Ticket) error {
+func (t *TicketInMemmory) SaveTicket(ctx context.Context, ticket domain.Ticket) error {
 	t.Tickets[ticket.TicketId] = &ticket
 	return nil
 }
-func (t *TicketInMemmory) DeleteTicket(ticketid int64) error {
+func (t *TicketInMemmory) CloseTicket(ctx context.Context, ticket domain.Ticket) error {
+	if _, ok := t.Tickets[ticket.TicketId]; !ok {
+		return fmt.Errorf("ticket for this %d id not exists", ticket.TicketId)
+	}
+	t.Tickets[ticket.TicketId] = &ticket
+	return nil
+}
+func (t *TicketInMemmory) DeleteTicket(ctx context.Context, ticketid int64) error {
 
 	_, ok := t.Tickets[ticketid]
 	if !ok {
@@ -27,17 +37,47 @@ func (t *TicketInMemmory) DeleteTicket(ticketid int64) error {
 	return nil
 
 }
-func (t *TicketInMemmory) FindTicketByVehicleNumber(vehiclenumber string) (*domain.Ticket, error) {
-
-	for id := range t.Tickets {
-		fmt.Println(id)
-	}
+func (t *TicketInMemmory) FindTicketByVehicleNumber(ctx context.Context, vehiclenumber string) (*domain.Ticket, error) {
 
 	for _, ticket := range t.Tickets {
-		if ticket.VehicleNumber == vehiclenumber {
+		if ticket.VehicleNumber == vehiclenumber && ticket.ExitTime == nil {
 			return ticket, nil
 		}
 	}
 	return nil, sql.ErrNoRows
 
 }
+
+func (t *TicketInMemmory) FindTicketByID(ctx context.Context, ticketid int64) (*domain.Ticket, error) {
+	ticket, ok := t.Tickets[ticketid]
+	if !ok {
+		return nil, fmt.Errorf("ticket for this %d id not exists", ticketid)
+	}
+	return ticket, nil
+}
+
+func (t *TicketInMemmory) ListClosedTickets(ctx context.Context, from, to time.Time) ([]domain.Ticket, error) {
+	var tickets []domain.Ticket
+	for _, ticket := range t.Tickets {
+		if ticket.ExitTime != nil && !ticket.ExitTime.Before(from) && ticket.ExitTime.Before(to) {
+			tickets = append(tickets, *ticket)
+		}
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].ExitTime.Before(*tickets[j].ExitTime)
+	})
+	return tickets, nil
+}
+
+func (t *TicketInMemmory) ListOpenTickets(ctx context.Context) ([]domain.Ticket, error) {
+	var tickets []domain.Ticket
+	for _, ticket := range t.Tickets {
+		if ticket.ExitTime == nil {
+			tickets = append(tickets, *ticket)
+		}
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].EntryTime.Before(tickets[j].EntryTime)
+	})
+	return tickets, nil
+}
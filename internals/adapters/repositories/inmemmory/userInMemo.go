@@ -0,0 +1,70 @@
+package inmemmory
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"sort"
+)
+
+type UserInMemmory struct {
+	users map[string]*domain.User
+}
+
+func NewUserInMemmory() *UserInMemmory {
+	return &UserInMemmory{users: make(map[string]*domain.User)}
+}
+
+func (u *UserInMemmory) SaveUser(ctx context.Context, user domain.User) error {
+	if existing, _ := u.FindUserByUsername(ctx, user.Username); existing != nil {
+		return fmt.Errorf("username %s already exists", user.Username)
+	}
+	u.users[user.ID] = &user
+	return nil
+}
+
+func (u *UserInMemmory) UpdateUser(ctx context.Context, user domain.User) error {
+	if _, ok := u.users[user.ID]; !ok {
+		return fmt.Errorf("user %s not exists", user.ID)
+	}
+	u.users[user.ID] = &user
+	return nil
+}
+
+func (u *UserInMemmory) FindUserByID(ctx context.Context, id string) (*domain.User, error) {
+	user, ok := u.users[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *user
+	return &found, nil
+}
+
+func (u *UserInMemmory) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	for _, user := range u.users {
+		if user.Username == username {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *UserInMemmory) ListUsers(ctx context.Context) ([]domain.User, error) {
+	var users []domain.User
+	for _, user := range u.users {
+		users = append(users, *user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+func (u *UserInMemmory) DeleteUser(ctx context.Context, id string) error {
+	if _, ok := u.users[id]; !ok {
+		return fmt.Errorf("user %s not exists", id)
+	}
+	delete(u.users, id)
+	return nil
+}
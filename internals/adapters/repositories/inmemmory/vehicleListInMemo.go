@@ -0,0 +1,75 @@
+package inmemmory
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"sort"
+	"sync"
+)
+
+type VehicleListInMemmory struct {
+	mu         sync.Mutex
+	entries    map[string]*domain.VehicleListEntry
+	rejections []domain.EntryRejection
+}
+
+func NewVehicleListInMemmory() *VehicleListInMemmory {
+	return &VehicleListInMemmory{entries: make(map[string]*domain.VehicleListEntry)}
+}
+
+func (v *VehicleListInMemmory) SaveEntry(ctx context.Context, entry domain.VehicleListEntry) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.entries[entry.VehicleNumber] = &entry
+	return nil
+}
+
+func (v *VehicleListInMemmory) FindEntry(ctx context.Context, vehiclenumber string) (*domain.VehicleListEntry, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.entries[vehiclenumber]
+	if !ok {
+		return nil, nil
+	}
+	found := *entry
+	return &found, nil
+}
+
+func (v *VehicleListInMemmory) ListEntries(ctx context.Context, listtype string) ([]domain.VehicleListEntry, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var entries []domain.VehicleListEntry
+	for _, entry := range v.entries {
+		if listtype == "" || entry.ListType == listtype {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].VehicleNumber < entries[j].VehicleNumber
+	})
+	return entries, nil
+}
+
+func (v *VehicleListInMemmory) DeleteEntry(ctx context.Context, vehiclenumber string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.entries[vehiclenumber]; !ok {
+		return ports.ErrVehicleListEntryNotFound
+	}
+	delete(v.entries, vehiclenumber)
+	return nil
+}
+
+func (v *VehicleListInMemmory) SaveRejection(ctx context.Context, rejection domain.EntryRejection) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rejections = append(v.rejections, rejection)
+	return nil
+}
+
+func (v *VehicleListInMemmory) ListRejections(ctx context.Context) ([]domain.EntryRejection, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]domain.EntryRejection(nil), v.rejections...), nil
+}
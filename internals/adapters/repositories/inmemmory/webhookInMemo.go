@@ -0,0 +1,124 @@
+package inmemmory
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type WebhookInMemmory struct {
+	mu            sync.Mutex
+	subscriptions map[string]domain.WebhookSubscription
+	// deliveries are kept in the order they were queued.
+	deliveries []domain.WebhookDelivery
+}
+
+func NewWebhookInMemmory() *WebhookInMemmory {
+	return &WebhookInMemmory{subscriptions: make(map[string]domain.WebhookSubscription)}
+}
+
+func (w *WebhookInMemmory) SaveSubscription(ctx context.Context, sub domain.WebhookSubscription) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.subscriptions[sub.ID]; ok {
+		return fmt.Errorf("webhook subscription %s already exists", sub.ID)
+	}
+	w.subscriptions[sub.ID] = sub
+	return nil
+}
+
+func (w *WebhookInMemmory) DeleteSubscription(ctx context.Context, id string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.subscriptions[id]; !ok {
+		return fmt.Errorf("webhook subscription %s not exists", id)
+	}
+	delete(w.subscriptions, id)
+	return nil
+}
+
+func (w *WebhookInMemmory) FindSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	sub, ok := w.subscriptions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &sub, nil
+}
+
+func (w *WebhookInMemmory) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var subs []domain.WebhookSubscription
+	for _, sub := range w.subscriptions {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+	})
+	return subs, nil
+}
+
+func (w *WebhookInMemmory) SaveDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.deliveries = append(w.deliveries, delivery)
+	return nil
+}
+
+func (w *WebhookInMemmory) UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i := range w.deliveries {
+		if w.deliveries[i].ID == delivery.ID {
+			w.deliveries[i] = delivery
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook delivery %s not exists", delivery.ID)
+}
+
+func (w *WebhookInMemmory) FindDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, delivery := range w.deliveries {
+		if delivery.ID == id {
+			return &delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+func (w *WebhookInMemmory) ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var deliveries []domain.WebhookDelivery
+	for i := len(w.deliveries) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(deliveries) == filter.Limit {
+			break
+		}
+		if filter.Matches(w.deliveries[i]) {
+			deliveries = append(deliveries, w.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (w *WebhookInMemmory) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var due []domain.WebhookDelivery
+	for _, delivery := range w.deliveries {
+		if limit > 0 && len(due) == limit {
+			break
+		}
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
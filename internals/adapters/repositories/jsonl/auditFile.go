@@ -0,0 +1,104 @@
+package jsonl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"parkingSlotManagement/internals/core/domain"
+	"sync"
+)
+
+// AuditFile is an audit log kept as a JSON-lines file: one entry per line,
+// only ever appended to. It suits a single instance shipping its log to a
+// collector.
+type AuditFile struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	lastId int64
+}
+
+// NewAuditFile opens path for appending, creating it if needed. Entry IDs
+// carry on from the last entry already in the file.
+func NewAuditFile(path string) (*AuditFile, error) {
+	a := &AuditFile{path: path}
+	if err := a.scan(func(entry domain.AuditEntry) { a.lastId = entry.EntryId }); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit file: %w", err)
+	}
+	a.file = file
+	return a, nil
+}
+
+func (a *AuditFile) Close() error {
+	return a.file.Close()
+}
+
+func (a *AuditFile) Record(ctx context.Context, entry domain.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry.EntryId = a.lastId + 1
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding audit entry: %w", err)
+	}
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing audit entry: %w", err)
+	}
+	a.lastId = entry.EntryId
+	return nil
+}
+
+// ListAuditEntries reads the whole file and returns matching entries newest
+// first.
+func (a *AuditFile) ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var matched []domain.AuditEntry
+	err := a.scan(func(entry domain.AuditEntry) {
+		if filter.Matches(entry) {
+			matched = append(matched, entry)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	var entries []domain.AuditEntry
+	for i := len(matched) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+		entries = append(entries, matched[i])
+	}
+	return entries, nil
+}
+
+func (a *AuditFile) scan(visit func(domain.AuditEntry)) error {
+	file, err := os.Open(a.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry domain.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("error decoding audit file line %d: %w", line, err)
+		}
+		visit(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading audit file: %w", err)
+	}
+	return nil
+}
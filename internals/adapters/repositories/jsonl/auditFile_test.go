@@ -0,0 +1,51 @@
+package jsonl
+
+import (
+	"context"
+	"os"
+	"parkingSlotManagement/internals/core/domain"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func TestAuditFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := NewAuditFile(path)
+	assert.NoError(t, err)
+
+	at := time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, log.Record(ctx, domain.AuditEntry{At: at, Actor: "ravi", Action: domain.AuditTicketPark, Target: "ticket:7",
+		After: []byte(`{"ticketid":7}`)}))
+	assert.NoError(t, log.Record(ctx, domain.AuditEntry{At: at.Add(time.Minute), Actor: "admin", Action: domain.AuditSlotAdd, Target: "slot:1"}))
+	assert.NoError(t, log.Record(ctx, domain.AuditEntry{At: at.Add(2 * time.Minute), Actor: "ravi", Action: domain.AuditTicketUnpark, Target: "ticket:7"}))
+	assert.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), "\n"))
+
+	// Reopening carries on the IDs and keeps what was written.
+	log, err = NewAuditFile(path)
+	assert.NoError(t, err)
+	defer log.Close()
+	assert.NoError(t, log.Record(ctx, domain.AuditEntry{At: at.Add(3 * time.Minute), Actor: "ravi", Action: domain.AuditLedgerSettle, Target: "vehicle:UP16AB1234"}))
+
+	entries, err := log.ListAuditEntries(ctx, domain.AuditFilter{Actor: "ravi", Action: "ticket"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, int64(3), entries[0].EntryId)
+	assert.Equal(t, domain.AuditTicketUnpark, entries[0].Action)
+	assert.JSONEq(t, `{"ticketid":7}`, string(entries[1].After))
+
+	entries, err = log.ListAuditEntries(ctx, domain.AuditFilter{From: at.Add(time.Minute), Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, int64(4), entries[0].EntryId)
+	assert.Equal(t, int64(3), entries[1].EntryId)
+}
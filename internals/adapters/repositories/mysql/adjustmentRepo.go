@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type AdjustmentRepo struct {
+	db *sql.DB
+}
+
+func NewAdjustmentRepo(db *sql.DB) *AdjustmentRepo {
+	return &AdjustmentRepo{db: db}
+}
+
+const adjustmentColumns = "adjustmentid, ticketid, vehiclenumber, amount, currency, reason, status, requestedby, requestedat, reviewedby, reviewedat, appliedat, refundreference"
+
+func (r *AdjustmentRepo) SaveAdjustment(ctx context.Context, adj domain.FeeAdjustment) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "INSERT INTO fee_adjustments ("+adjustmentColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		adj.AdjustmentId, adj.TicketId, adj.VehicleNumber, adj.Amount.Amount, adj.Amount.Currency, adj.Reason, adj.Status,
+		adj.RequestedBy, adj.RequestedAt, adj.ReviewedBy, adj.ReviewedAt, adj.AppliedAt, adj.RefundReference)
+	if err != nil {
+		return Wrap("error inserting fee adjustment", err)
+	}
+	return nil
+}
+
+func (r *AdjustmentRepo) UpdateAdjustmentFrom(ctx context.Context, adj domain.FeeAdjustment, status string) (bool, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE fee_adjustments SET status=?, reviewedby=?, reviewedat=?, appliedat=?, refundreference=? WHERE adjustmentid=? AND status=?",
+		adj.Status, adj.ReviewedBy, adj.ReviewedAt, adj.AppliedAt, adj.RefundReference, adj.AdjustmentId, status)
+	if err != nil {
+		return false, Wrap("error updating fee adjustment", err)
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return false, Wrap("error checking rows affected for fee adjustment update", err)
+	}
+	return row == 1, nil
+}
+
+func (r *AdjustmentRepo) FindAdjustmentByID(ctx context.Context, adjustmentid int64) (*domain.FeeAdjustment, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, "SELECT "+adjustmentColumns+" FROM fee_adjustments WHERE adjustmentid = ?", adjustmentid)
+	adj, err := scanAdjustment(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAdjustmentNotFound
+		}
+		return nil, ErrDBQueryFailed
+	}
+	return adj, nil
+}
+
+func (r *AdjustmentRepo) ListAdjustments(ctx context.Context, status string) ([]domain.FeeAdjustment, error) {
+	query := "SELECT " + adjustmentColumns + " FROM fee_adjustments"
+	var args []any
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	return r.list(ctx, query+" ORDER BY adjustmentid", args...)
+}
+
+func (r *AdjustmentRepo) ListAdjustmentsByTicket(ctx context.Context, ticketid int64) ([]domain.FeeAdjustment, error) {
+	return r.list(ctx, "SELECT "+adjustmentColumns+" FROM fee_adjustments WHERE ticketid = ? ORDER BY adjustmentid", ticketid)
+}
+
+func (r *AdjustmentRepo) list(ctx context.Context, query string, args ...any) ([]domain.FeeAdjustment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Wrap("error fetching fee adjustments", err)
+	}
+	defer rows.Close()
+
+	var adjustments []domain.FeeAdjustment
+	for rows.Next() {
+		adj, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, *adj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap("error fetching fee adjustments", err)
+	}
+	return adjustments, nil
+}
+
+func scanAdjustment(row rowScanner) (*domain.FeeAdjustment, error) {
+	var adj domain.FeeAdjustment
+	var requestedAtStr string
+	var reviewedAtStr, appliedAtStr sql.NullString
+	err := row.Scan(&adj.AdjustmentId, &adj.TicketId, &adj.VehicleNumber, &adj.Amount.Amount, &adj.Amount.Currency, &adj.Reason, &adj.Status,
+		&adj.RequestedBy, &requestedAtStr, &adj.ReviewedBy, &reviewedAtStr, &appliedAtStr, &adj.RefundReference)
+	if err != nil {
+		return nil, err
+	}
+	if adj.RequestedAt, err = parseDBTime(requestedAtStr); err != nil {
+		return nil, Wrap("error parsing requested time", err)
+	}
+	if adj.ReviewedAt, err = parseNullDBTime(reviewedAtStr); err != nil {
+		return nil, Wrap("error parsing reviewed time", err)
+	}
+	if adj.AppliedAt, err = parseNullDBTime(appliedAtStr); err != nil {
+		return nil, Wrap("error parsing applied time", err)
+	}
+	return &adj, nil
+}
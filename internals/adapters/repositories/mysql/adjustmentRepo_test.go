@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var adjustmentRowColumns = []string{"adjustmentid", "ticketid", "vehiclenumber", "amount", "currency", "reason", "status",
+	"requestedby", "requestedat", "reviewedby", "reviewedat", "appliedat", "refundreference"}
+
+func TestSaveAdjustment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAdjustmentRepo(db)
+	adj := domain.FeeAdjustment{
+		AdjustmentId: 1, TicketId: 10, VehicleNumber: "UP16AB1234", Amount: domain.NewMoney(2000, domain.CurrencyINR), Reason: "overcharged",
+		Status: domain.AdjustmentRequested, RequestedBy: "att", RequestedAt: time.Now(),
+	}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+fee_adjustments`).
+		WithArgs(int64(1), int64(10), "UP16AB1234", int64(2000), domain.CurrencyINR, "overcharged", domain.AdjustmentRequested,
+			"att", sqlmock.AnyArg(), "", sqlmock.AnyArg(), sqlmock.AnyArg(), "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveAdjustment(ctx, adj))
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+fee_adjustments`).
+		WillReturnError(errors.New("insert failed"))
+	assert.Error(t, repo.SaveAdjustment(ctx, adj))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAdjustmentFrom(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAdjustmentRepo(db)
+	reviewedAt := time.Now()
+	adj := domain.FeeAdjustment{AdjustmentId: 1, Status: domain.AdjustmentApproved, ReviewedBy: "sup", ReviewedAt: &reviewedAt}
+	query := `(?i)UPDATE\s+fee_adjustments\s+SET\s+status=\?.*WHERE\s+adjustmentid=\?\s+AND\s+status=\?`
+
+	mock.ExpectExec(query).
+		WithArgs(domain.AdjustmentApproved, "sup", sqlmock.AnyArg(), sqlmock.AnyArg(), "", int64(1), domain.AdjustmentRequested).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	updated, err := repo.UpdateAdjustmentFrom(ctx, adj, domain.AdjustmentRequested)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	mock.ExpectExec(query).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	updated, err = repo.UpdateAdjustmentFrom(ctx, adj, domain.AdjustmentRequested)
+	assert.NoError(t, err)
+	assert.False(t, updated, "another reviewer got there first")
+
+	mock.ExpectExec(query).
+		WillReturnError(errors.New("update failed"))
+	_, err = repo.UpdateAdjustmentFrom(ctx, adj, domain.AdjustmentRequested)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFindAdjustmentByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAdjustmentRepo(db)
+	query := `(?i)SELECT\s+adjustmentid,.*FROM\s+fee_adjustments\s+WHERE\s+adjustmentid\s*=\s*\?`
+
+	mock.ExpectQuery(query).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(adjustmentRowColumns).
+			AddRow(1, 10, "UP16AB1234", 2000, "INR", "overcharged", "applied", "att", "2025-09-08 10:00:00", "sup", "2025-09-08 11:00:00", "2025-09-08 12:00:00", "cash_000001"))
+	adj, err := repo.FindAdjustmentByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AdjustmentApplied, adj.Status)
+	assert.Equal(t, domain.NewMoney(2000, domain.CurrencyINR), adj.Amount)
+	assert.Equal(t, time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC), *adj.AppliedAt)
+
+	mock.ExpectQuery(query).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(adjustmentRowColumns))
+	_, err = repo.FindAdjustmentByID(ctx, 2)
+	assert.ErrorIs(t, err, ErrAdjustmentNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListAdjustments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAdjustmentRepo(db)
+
+	mock.ExpectQuery(`(?i)FROM\s+fee_adjustments\s+WHERE\s+status\s*=\s*\?`).
+		WithArgs("requested").
+		WillReturnRows(sqlmock.NewRows(adjustmentRowColumns).
+			AddRow(1, 10, "UP16AB1234", 2000, "INR", "overcharged", "requested", "att", "2025-09-08 10:00:00", "", nil, nil, ""))
+	adjustments, err := repo.ListAdjustments(ctx, domain.AdjustmentRequested)
+	assert.NoError(t, err)
+	assert.Len(t, adjustments, 1)
+	assert.Nil(t, adjustments[0].ReviewedAt)
+
+	mock.ExpectQuery(`(?i)FROM\s+fee_adjustments\s+WHERE\s+ticketid\s*=\s*\?`).
+		WithArgs(int64(10)).
+		WillReturnError(errors.New("query error"))
+	_, err = repo.ListAdjustmentsByTicket(ctx, 10)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
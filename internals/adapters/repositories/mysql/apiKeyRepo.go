@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type APIKeyRepo struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepo(db *sql.DB) *APIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+const apiKeyColumns = "id, name, secrethash, scopes, createdby, createdat, rotatedat, revokedat"
+
+// SaveAPIKey stores a new key. Scopes are stored as JSON.
+func (r *APIKeyRepo) SaveAPIKey(ctx context.Context, key domain.APIKey) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return Wrap("error encoding api key scopes", err)
+	}
+	_, err = r.db.ExecContext(ctx, "INSERT INTO api_keys ("+apiKeyColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		key.ID, key.Name, key.SecretHash, string(scopes), key.CreatedBy, key.CreatedAt, key.RotatedAt, key.RevokedAt)
+	if err != nil {
+		return Wrap("error saving api key", err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepo) UpdateAPIKey(ctx context.Context, key domain.APIKey) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return Wrap("error encoding api key scopes", err)
+	}
+	res, err := r.db.ExecContext(ctx, "UPDATE api_keys SET name=?, secrethash=?, scopes=?, rotatedat=?, revokedat=? WHERE id=?",
+		key.Name, key.SecretHash, string(scopes), key.RotatedAt, key.RevokedAt, key.ID)
+	if err != nil {
+		return Wrap("error updating api key", err)
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return Wrap("error checking rows affected for api key update", err)
+	}
+	if row == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes, createdAtStr string
+	var rotatedAtStr, revokedAtStr sql.NullString
+	err := row.Scan(&key.ID, &key.Name, &key.SecretHash, &scopes, &key.CreatedBy, &createdAtStr, &rotatedAtStr, &revokedAtStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, Wrap("error decoding api key scopes", err)
+	}
+	if key.CreatedAt, err = parseDBTime(createdAtStr); err != nil {
+		return nil, Wrap("error parsing created time", err)
+	}
+	if key.RotatedAt, err = parseNullDBTime(rotatedAtStr); err != nil {
+		return nil, Wrap("error parsing rotated time", err)
+	}
+	if key.RevokedAt, err = parseNullDBTime(revokedAtStr); err != nil {
+		return nil, Wrap("error parsing revoked time", err)
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepo) FindAPIKeyByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, ErrDBQueryFailed
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepo) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY createdat")
+	if err != nil {
+		return nil, Wrap("error fetching api keys", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
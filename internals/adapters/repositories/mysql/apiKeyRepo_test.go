@@ -0,0 +1,134 @@
+package mysql
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyRowColumns = []string{"id", "name", "secrethash", "scopes", "createdby", "createdat", "rotatedat", "revokedat"}
+
+func TestSaveAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepo(db)
+	key := domain.APIKey{ID: "3f9a1c2b7d4e", Name: "gate-1", SecretHash: "abc123", Scopes: []domain.Permission{domain.PermParkingOperate},
+		CreatedBy: "admin", CreatedAt: time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+api_keys`).
+		WithArgs(key.ID, key.Name, key.SecretHash, `["parking:operate"]`, "admin", sqlmock.AnyArg(), nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveAPIKey(ctx, key))
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+api_keys`).
+		WillReturnError(errors.New("duplicate entry"))
+	assert.Error(t, repo.SaveAPIKey(ctx, key))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepo(db)
+	revokedAt := time.Date(2025, 9, 9, 10, 0, 0, 0, time.UTC)
+	key := domain.APIKey{ID: "3f9a1c2b7d4e", Name: "gate-1", SecretHash: "abc123", Scopes: []domain.Permission{domain.PermParkingOperate},
+		RevokedAt: &revokedAt}
+	query := `(?i)UPDATE\s+api_keys\s+SET\s+name=\?,\s*secrethash=\?,\s*scopes=\?,\s*rotatedat=\?,\s*revokedat=\?\s+WHERE\s+id=\?`
+
+	mock.ExpectExec(query).WithArgs("gate-1", "abc123", `["parking:operate"]`, nil, sqlmock.AnyArg(), key.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateAPIKey(ctx, key))
+
+	mock.ExpectExec(query).WithArgs("gate-1", "abc123", `["parking:operate"]`, nil, sqlmock.AnyArg(), key.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.UpdateAPIKey(ctx, key), ErrAPIKeyNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFindAPIKeyByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepo(db)
+	query := `(?i)SELECT\s+id,\s*name,\s*secrethash,\s*scopes,\s*createdby,\s*createdat,\s*rotatedat,\s*revokedat\s+FROM\s+api_keys\s+WHERE\s+id\s*=\s*\?`
+	revokedAt := time.Date(2025, 9, 9, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		mockFunc    func()
+		expectedKey *domain.APIKey
+		expectedErr bool
+	}{
+		{
+			name: "successfully find key",
+			mockFunc: func() {
+				mock.ExpectQuery(query).WithArgs("3f9a1c2b7d4e").
+					WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).
+						AddRow("3f9a1c2b7d4e", "kiosk", "abc123", `["receipts:read","reports:read"]`, "admin", "2025-09-08 10:00:00", nil, "2025-09-09 10:00:00"))
+			},
+			expectedKey: &domain.APIKey{ID: "3f9a1c2b7d4e", Name: "kiosk", SecretHash: "abc123",
+				Scopes: []domain.Permission{domain.PermReceiptsRead, domain.PermReportsRead}, CreatedBy: "admin",
+				CreatedAt: time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC), RevokedAt: &revokedAt},
+		},
+		{
+			name: "key not found",
+			mockFunc: func() {
+				mock.ExpectQuery(query).WithArgs("3f9a1c2b7d4e").
+					WillReturnRows(sqlmock.NewRows(apiKeyRowColumns))
+			},
+		},
+		{
+			name: "db error",
+			mockFunc: func() {
+				mock.ExpectQuery(query).WithArgs("3f9a1c2b7d4e").WillReturnError(errors.New("connection lost"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			key, err := repo.FindAPIKeyByID(ctx, "3f9a1c2b7d4e")
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedKey, key)
+		})
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListAPIKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAPIKeyRepo(db)
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+api_keys\s+ORDER\s+BY\s+createdat`).
+		WillReturnRows(sqlmock.NewRows(apiKeyRowColumns).
+			AddRow("3f9a1c2b7d4e", "gate-1", "abc123", `["parking:operate"]`, "admin", "2025-09-08 10:00:00", "2025-09-10 10:00:00", nil).
+			AddRow("8b2e4d6f1a3c", "kiosk", "def456", `["receipts:read"]`, "admin", "2025-09-08 11:00:00", nil, nil))
+
+	keys, err := repo.ListAPIKeys(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "gate-1", keys[0].Name)
+	assert.NotNil(t, keys[0].RotatedAt)
+	assert.Equal(t, []domain.Permission{domain.PermReceiptsRead}, keys[1].Scopes)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,96 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+)
+
+type AuditRepo struct {
+	db *sql.DB
+}
+
+func NewAuditRepo(db *sql.DB) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+const auditColumns = "entryid, at, actor, action, target, beforevalue, aftervalue, requestid, address, detail"
+
+// likeEscaper escapes LIKE wildcards, so that a filter matches them
+// literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func nullJSON(value []byte) sql.NullString {
+	return sql.NullString{String: string(value), Valid: len(value) > 0}
+}
+
+func (r *AuditRepo) Record(ctx context.Context, entry domain.AuditEntry) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO audit_log (at, actor, action, target, beforevalue, aftervalue, requestid, address, detail) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.At, entry.Actor, entry.Action, entry.Target, nullJSON(entry.Before), nullJSON(entry.After),
+		entry.RequestID, entry.Address, entry.Detail)
+	if err != nil {
+		return Wrap("error recording audit entry", err)
+	}
+	return nil
+}
+
+// ListAuditEntries returns matching entries newest first.
+func (r *AuditRepo) ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var where []string
+	var args []any
+	if filter.Actor != "" {
+		where, args = append(where, "actor = ?"), append(args, filter.Actor)
+	}
+	if filter.Action != "" {
+		where, args = append(where, `(action = ? OR action LIKE ? ESCAPE '\\')`), append(args, filter.Action, likeEscaper.Replace(filter.Action)+".%")
+	}
+	if filter.Target != "" {
+		where, args = append(where, "target = ?"), append(args, filter.Target)
+	}
+	if filter.RequestID != "" {
+		where, args = append(where, "requestid = ?"), append(args, filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		where, args = append(where, "at >= ?"), append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		where, args = append(where, "at < ?"), append(args, filter.To)
+	}
+	query := "SELECT " + auditColumns + " FROM audit_log"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY entryid DESC"
+	if filter.Limit > 0 {
+		query, args = query+" LIMIT ?", append(args, filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Wrap("error fetching audit entries", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var atStr string
+		var before, after sql.NullString
+		if err := rows.Scan(&entry.EntryId, &atStr, &entry.Actor, &entry.Action, &entry.Target, &before, &after,
+			&entry.RequestID, &entry.Address, &entry.Detail); err != nil {
+			return nil, Wrap("error scanning audit entry", err)
+		}
+		if entry.At, err = parseDBTime(atStr); err != nil {
+			return nil, Wrap("error parsing audit time", err)
+		}
+		if before.Valid {
+			entry.Before = []byte(before.String)
+		}
+		if after.Valid {
+			entry.After = []byte(after.String)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
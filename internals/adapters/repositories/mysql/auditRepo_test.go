@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var auditRowColumns = []string{"entryid", "at", "actor", "action", "target", "beforevalue", "aftervalue", "requestid", "address", "detail"}
+
+func TestRecordAuditEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepo(db)
+	entry := domain.AuditEntry{At: time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC), Actor: "ravi",
+		Action: domain.AuditSlotAdd, Target: "slot:1", After: []byte(`{"slotid":1}`), RequestID: "req-1", Address: "10.0.0.7"}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+audit_log`).
+		WithArgs(entry.At, "ravi", "slot.add", "slot:1", nil, `{"slotid":1}`, "req-1", "10.0.0.7", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.Record(ctx, entry))
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+audit_log`).WillReturnError(errors.New("insert failed"))
+	assert.Error(t, repo.Record(ctx, entry))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListAuditEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewAuditRepo(db)
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(auditRowColumns).
+			AddRow(2, "2025-09-08 10:05:00", "ravi", "ticket.unpark", "ticket:7", `{"ticketid":7}`, `{"ticketid":7,"fee":"10.00"}`, "req-2", "10.0.0.7", "").
+			AddRow(1, "2025-09-08 10:00:00", "ravi", "ticket.park", "ticket:7", nil, `{"ticketid":7}`, "req-1", "10.0.0.7", "")
+	}
+
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+audit_log\s+WHERE\s+actor\s*=\s*\?\s+AND\s+\(action\s*=\s*\?\s+OR\s+action\s+LIKE\s+\?\s+ESCAPE\s+'\\\\'\)\s+AND\s+at\s*>=\s*\?\s+ORDER\s+BY\s+entryid\s+DESC\s+LIMIT\s+\?`).
+		WithArgs("ravi", "ticket", "ticket.%", from, 50).
+		WillReturnRows(rows())
+	entries, err := repo.ListAuditEntries(ctx, domain.AuditFilter{Actor: "ravi", Action: "ticket", From: from, Limit: 50})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "ticket.unpark", entries[0].Action)
+	assert.Nil(t, entries[1].Before)
+	assert.JSONEq(t, `{"ticketid":7}`, string(entries[1].After))
+	assert.Equal(t, time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC), entries[1].At)
+
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+audit_log\s+WHERE\s+\(action\s*=\s*\?\s+OR\s+action\s+LIKE\s+\?\s+ESCAPE`).
+		WithArgs(`100%_off\`, `100\%\_off\\.%`).
+		WillReturnRows(sqlmock.NewRows(auditRowColumns))
+	_, err = repo.ListAuditEntries(ctx, domain.AuditFilter{Action: `100%_off\`})
+	assert.NoError(t, err, "wildcards in the action filter should be matched literally")
+
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+audit_log\s+ORDER\s+BY\s+entryid\s+DESC$`).
+		WillReturnRows(rows().RowError(1, errors.New("connection lost")))
+	_, err = repo.ListAuditEntries(ctx, domain.AuditFilter{})
+	assert.Error(t, err, "an error after the first row should not be dropped")
+
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+audit_log\s+ORDER\s+BY\s+entryid\s+DESC$`).
+		WillReturnError(errors.New("connection lost"))
+	_, err = repo.ListAuditEntries(ctx, domain.AuditFilter{})
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -3,6 +3,8 @@ package mysql
 import (
 	"errors"
 	"fmt"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
 )
 
 var (
@@ -11,6 +13,12 @@ var (
 	ErrSlotNotFoundByID = errors.New(" not found slot by  slot ID")
 	ErrInvalidSlotType  = errors.New("invalid slot type")
 	ErrDBQueryFailed    = errors.New("database query failed")
+
+	ErrAdjustmentNotFound = errors.New("fee adjustment not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrWebhookNotFound    = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound   = errors.New("webhook delivery not found")
 )
 
 func Wrap(content string, err error) error {
@@ -20,3 +28,12 @@ func Wrap(content string, err error) error {
 	}
 	return nil
 }
+
+// erDupEntry is the MySQL error number for a duplicate key.
+const erDupEntry = 1062
+
+// isDuplicate reports whether err is MySQL refusing a duplicate key.
+func isDuplicate(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erDupEntry
+}
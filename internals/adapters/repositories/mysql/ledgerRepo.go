@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type LedgerRepo struct {
+	db *sql.DB
+}
+
+func NewLedgerRepo(db *sql.DB) *LedgerRepo {
+	return &LedgerRepo{db: db}
+}
+
+func (r *LedgerRepo) SaveLedgerEntry(ctx context.Context, entry domain.LedgerEntry) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "INSERT INTO ledger_entries (entryid, vehiclenumber, ticketid, kind, amount, currency, note, createdat, paymentmethod, paymentreference) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.EntryId, entry.VehicleNumber, entry.TicketId, entry.Kind, entry.Amount.Amount, entry.Amount.Currency, entry.Note, entry.CreatedAt, entry.PaymentMethod, entry.PaymentReference)
+	if err != nil {
+		return Wrap("error inserting ledger entry", err)
+	}
+	return nil
+}
+
+func (r *LedgerRepo) ListLedgerEntries(ctx context.Context, vehiclenumber string) ([]domain.LedgerEntry, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT entryid, vehiclenumber, ticketid, kind, amount, currency, note, createdat, paymentmethod, paymentreference FROM ledger_entries WHERE vehiclenumber = ? ORDER BY createdat", vehiclenumber)
+	if err != nil {
+		return nil, Wrap("error fetching ledger entries", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.LedgerEntry
+	for rows.Next() {
+		var entry domain.LedgerEntry
+		var createdAtStr string
+		if err := rows.Scan(&entry.EntryId, &entry.VehicleNumber, &entry.TicketId, &entry.Kind, &entry.Amount.Amount, &entry.Amount.Currency, &entry.Note, &createdAtStr, &entry.PaymentMethod, &entry.PaymentReference); err != nil {
+			return nil, err
+		}
+		if entry.CreatedAt, err = parseDBTime(createdAtStr); err != nil {
+			return nil, Wrap("error parsing created time", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, Wrap("error fetching ledger entries", err)
+	}
+	return entries, nil
+}
+
+// GetBalance sums the vehicle's entries. A vehicle's account is kept in a
+// single currency, so entries in more than one are reported as an error.
+func (r *LedgerRepo) GetBalance(ctx context.Context, vehiclenumber string) (domain.Money, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT currency, SUM(amount) FROM ledger_entries WHERE vehiclenumber = ? GROUP BY currency", vehiclenumber)
+	if err != nil {
+		return domain.Money{}, ErrDBQueryFailed
+	}
+	defer rows.Close()
+
+	var balance domain.Money
+	for rows.Next() {
+		if balance.Currency != "" {
+			return domain.Money{}, Wrap("error summing ledger entries", domain.ErrCurrencyMismatch)
+		}
+		if err := rows.Scan(&balance.Currency, &balance.Amount); err != nil {
+			return domain.Money{}, ErrDBQueryFailed
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Money{}, ErrDBQueryFailed
+	}
+	return balance, nil
+}
+
+// LockBalance locks the vehicle's entries FOR UPDATE before summing them.
+// It only holds the lock when ctx carries a transaction.
+func (r *LedgerRepo) LockBalance(ctx context.Context, vehiclenumber string) (domain.Money, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT entryid FROM ledger_entries WHERE vehiclenumber = ? FOR UPDATE", vehiclenumber)
+	if err != nil {
+		return domain.Money{}, ErrDBQueryFailed
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return domain.Money{}, ErrDBQueryFailed
+	}
+	return r.GetBalance(ctx, vehiclenumber)
+}
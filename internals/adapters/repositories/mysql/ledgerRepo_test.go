@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLedgerEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLedgerRepo(db)
+	entry := domain.LedgerEntry{
+		EntryId:       1,
+		VehicleNumber: "UP16AB1234",
+		TicketId:      10,
+		Kind:          domain.LedgerUnpaid,
+		Amount:        domain.NewMoney(12000, domain.CurrencyINR),
+		Note:          "payment failure",
+		CreatedAt:     time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC),
+	}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+ledger_entries`).
+		WithArgs(entry.EntryId, entry.VehicleNumber, entry.TicketId, entry.Kind, int64(12000), domain.CurrencyINR, entry.Note, sqlmock.AnyArg(), "", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveLedgerEntry(ctx, entry))
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+ledger_entries`).
+		WillReturnError(errors.New("insert failed"))
+	assert.Error(t, repo.SaveLedgerEntry(ctx, entry))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListLedgerEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLedgerRepo(db)
+
+	mock.ExpectQuery(`(?i)SELECT\s+entryid,.*FROM\s+ledger_entries\s+WHERE\s+vehiclenumber\s*=\s*\?`).
+		WithArgs("UP16AB1234").
+		WillReturnRows(sqlmock.NewRows([]string{"entryid", "vehiclenumber", "ticketid", "kind", "amount", "currency", "note", "createdat", "paymentmethod", "paymentreference"}).
+			AddRow(1, "UP16AB1234", 10, "unpaid", 12000, "INR", "", "2025-09-08 10:00:00", "", "").
+			AddRow(2, "UP16AB1234", 0, "settlement", -2000, "INR", "", "2025-09-09 10:00:00", "cash", "cash_1"))
+	entries, err := repo.ListLedgerEntries(ctx, "UP16AB1234")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, domain.NewMoney(-2000, domain.CurrencyINR), entries[1].Amount)
+	assert.Equal(t, "cash_1", entries[1].PaymentReference)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLedgerRepo(db)
+	query := `(?i)SELECT\s+currency,\s*SUM\(amount\)\s+FROM\s+ledger_entries\s+WHERE\s+vehiclenumber\s*=\s*\?\s+GROUP\s+BY\s+currency`
+
+	mock.ExpectQuery(query).
+		WithArgs("UP16AB1234").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "balance"}).AddRow("INR", 10000))
+	balance, err := repo.GetBalance(ctx, "UP16AB1234")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(10000, domain.CurrencyINR), balance)
+
+	mock.ExpectQuery(query).
+		WithArgs("DL3CAF0001").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "balance"}))
+	balance, err = repo.GetBalance(ctx, "DL3CAF0001")
+	assert.NoError(t, err)
+	assert.True(t, balance.IsZero())
+
+	mock.ExpectQuery(query).
+		WithArgs("UP16AB1234").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "balance"}).AddRow("INR", 10000).AddRow("USD", 500))
+	_, err = repo.GetBalance(ctx, "UP16AB1234")
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+	mock.ExpectQuery(query).
+		WithArgs("UP16AB1234").
+		WillReturnError(errors.New("query error"))
+	_, err = repo.GetBalance(ctx, "UP16AB1234")
+	assert.ErrorIs(t, err, ErrDBQueryFailed)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestLockBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLedgerRepo(db)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT\s+entryid\s+FROM\s+ledger_entries\s+WHERE\s+vehiclenumber\s*=\s*\?\s+FOR\s+UPDATE`).
+		WithArgs("UP16AB1234").
+		WillReturnRows(sqlmock.NewRows([]string{"entryid"}).AddRow(1))
+	mock.ExpectQuery(`(?i)SELECT\s+currency,\s*SUM\(amount\)`).
+		WithArgs("UP16AB1234").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "balance"}).AddRow("INR", 10000))
+	mock.ExpectCommit()
+
+	err = NewTransactor(db).WithinTx(ctx, func(ctx context.Context) error {
+		balance, err := repo.LockBalance(ctx, "UP16AB1234")
+		assert.Equal(t, domain.NewMoney(10000, domain.CurrencyINR), balance)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
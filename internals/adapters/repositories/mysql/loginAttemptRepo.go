@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type LoginAttemptRepo struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepo(db *sql.DB) *LoginAttemptRepo {
+	return &LoginAttemptRepo{db: db}
+}
+
+const loginAttemptColumns = "attemptkey, failures, lastfailure, lockeduntil"
+
+func scanLoginAttempts(row rowScanner) (*domain.LoginAttempts, error) {
+	var attempts domain.LoginAttempts
+	var lastFailureStr string
+	var lockedUntilStr sql.NullString
+	if err := row.Scan(&attempts.Key, &attempts.Failures, &lastFailureStr, &lockedUntilStr); err != nil {
+		return nil, err
+	}
+	var err error
+	if attempts.LastFailure, err = parseDBTime(lastFailureStr); err != nil {
+		return nil, Wrap("error parsing last failure time", err)
+	}
+	if attempts.LockedUntil, err = parseNullDBTime(lockedUntilStr); err != nil {
+		return nil, Wrap("error parsing locked until time", err)
+	}
+	return &attempts, nil
+}
+
+func (r *LoginAttemptRepo) FindLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	attempts, err := scanLoginAttempts(r.db.QueryRowContext(ctx, "SELECT "+loginAttemptColumns+" FROM login_attempts WHERE attemptkey = ?", key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, ErrDBQueryFailed
+	}
+	return attempts, nil
+}
+
+func (r *LoginAttemptRepo) SaveLoginAttempts(ctx context.Context, attempts domain.LoginAttempts) error {
+	_, err := r.db.ExecContext(ctx, "REPLACE INTO login_attempts ("+loginAttemptColumns+") VALUES (?, ?, ?, ?)",
+		attempts.Key, attempts.Failures, attempts.LastFailure, attempts.LockedUntil)
+	if err != nil {
+		return Wrap("error saving login attempts", err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepo) DeleteLoginAttempts(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM login_attempts WHERE attemptkey = ?", key); err != nil {
+		return Wrap("error deleting login attempts", err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepo) ListLoginAttempts(ctx context.Context) ([]domain.LoginAttempts, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+loginAttemptColumns+" FROM login_attempts ORDER BY attemptkey")
+	if err != nil {
+		return nil, Wrap("error fetching login attempts", err)
+	}
+	defer rows.Close()
+
+	var list []domain.LoginAttempts
+	for rows.Next() {
+		attempts, err := scanLoginAttempts(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *attempts)
+	}
+	return list, nil
+}
@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var loginAttemptRowColumns = []string{"attemptkey", "failures", "lastfailure", "lockeduntil"}
+
+func TestSaveLoginAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepo(db)
+	lastFailure := time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)
+	attempts := domain.LoginAttempts{Key: "user:ravi", Failures: 2, LastFailure: lastFailure}
+
+	mock.ExpectExec(`(?i)REPLACE\s+INTO\s+login_attempts`).
+		WithArgs("user:ravi", 2, lastFailure, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveLoginAttempts(ctx, attempts))
+
+	mock.ExpectExec(`(?i)REPLACE\s+INTO\s+login_attempts`).
+		WillReturnError(errors.New("insert failed"))
+	assert.Error(t, repo.SaveLoginAttempts(ctx, attempts))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFindLoginAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepo(db)
+	query := `(?i)SELECT\s+attemptkey,\s*failures,\s*lastfailure,\s*lockeduntil\s+FROM\s+login_attempts\s+WHERE\s+attemptkey\s*=\s*\?`
+	lockedUntil := time.Date(2025, 9, 8, 10, 15, 0, 0, time.UTC)
+
+	mock.ExpectQuery(query).WithArgs("user:ravi").
+		WillReturnRows(sqlmock.NewRows(loginAttemptRowColumns).AddRow("user:ravi", 5, "2025-09-08 10:00:00", "2025-09-08 10:15:00"))
+	attempts, err := repo.FindLoginAttempts(ctx, "user:ravi")
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.LoginAttempts{Key: "user:ravi", Failures: 5,
+		LastFailure: time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC), LockedUntil: &lockedUntil}, attempts)
+
+	mock.ExpectQuery(query).WithArgs("user:meena").WillReturnRows(sqlmock.NewRows(loginAttemptRowColumns))
+	attempts, err = repo.FindLoginAttempts(ctx, "user:meena")
+	assert.NoError(t, err)
+	assert.Nil(t, attempts)
+
+	mock.ExpectQuery(query).WithArgs("user:ravi").WillReturnError(errors.New("connection lost"))
+	_, err = repo.FindLoginAttempts(ctx, "user:ravi")
+	assert.ErrorIs(t, err, ErrDBQueryFailed)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAndListLoginAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewLoginAttemptRepo(db)
+	mock.ExpectExec(`(?i)DELETE\s+FROM\s+login_attempts\s+WHERE\s+attemptkey\s*=\s*\?`).
+		WithArgs("ip:10.0.0.7").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteLoginAttempts(ctx, "ip:10.0.0.7"))
+
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+login_attempts\s+ORDER\s+BY\s+attemptkey`).
+		WillReturnRows(sqlmock.NewRows(loginAttemptRowColumns).
+			AddRow("ip:10.0.0.7", 20, "2025-09-08 10:00:00", "2025-09-08 10:15:00").
+			AddRow("user:ravi", 1, "2025-09-08 10:00:00", nil))
+	list, err := repo.ListLoginAttempts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.NotNil(t, list[0].LockedUntil)
+	assert.Nil(t, list[1].LockedUntil)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"sync"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
@@ -16,6 +17,24 @@ var (
 	once sync.Once
 )
 
+// DATETIME columns come back as strings because the DSN doesn't set parseTime.
+const dbTimeLayout = "2006-01-02 15:04:05"
+
+func parseDBTime(value string) (time.Time, error) {
+	return time.Parse(dbTimeLayout, value)
+}
+
+func parseNullDBTime(value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	t, err := parseDBTime(value.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 func GetInstance() *sql.DB {
 	once.Do(func() {
 
@@ -31,7 +50,6 @@ func GetInstance() *sql.DB {
 		dbname := os.Getenv("DB_NAME")
 
 		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", user, password, host, port, dbname)
-		fmt.Println("DSN:", dsn)
 		db, err = sql.Open("mysql", dsn)
 		if err != nil {
 			log.Fatalf("Failed to open DB: %v", err)
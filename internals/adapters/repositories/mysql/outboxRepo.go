@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+	"time"
+)
+
+type OutboxRepo struct {
+	db *sql.DB
+}
+
+func NewOutboxRepo(db *sql.DB) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+// AddEvents inserts the events in the transaction carried by ctx, so they
+// are stored if and only if the change they report is.
+func (r *OutboxRepo) AddEvents(ctx context.Context, events ...domain.Event) error {
+	now := time.Now()
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return Wrap("error encoding outbox event", err)
+		}
+		_, err = conn(ctx, r.db).ExecContext(ctx, "INSERT INTO outbox (eventid, eventtype, payload, createdat) VALUES (?, ?, ?, ?)",
+			event.ID, event.Type, string(payload), now)
+		if err != nil {
+			return Wrap("error inserting outbox event", err)
+		}
+	}
+	return nil
+}
+
+// ListUnpublished locks the rows it returns FOR UPDATE SKIP LOCKED, so
+// relays in several servers each take their own batch.
+func (r *OutboxRepo) ListUnpublished(ctx context.Context, limit int, now time.Time) ([]domain.OutboxMessage, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT id, payload, createdat FROM outbox WHERE publishedat IS NULL AND (claimeduntil IS NULL OR claimeduntil < ?) ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED", now, limit)
+	if err != nil {
+		return nil, Wrap("error fetching outbox", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var message domain.OutboxMessage
+		var payload, createdAt string
+		if err := rows.Scan(&message.ID, &payload, &createdAt); err != nil {
+			return nil, Wrap("error scanning outbox message", err)
+		}
+		if err := json.Unmarshal([]byte(payload), &message.Event); err != nil {
+			return nil, Wrap("error decoding outbox event", err)
+		}
+		if message.CreatedAt, err = parseDBTime(createdAt); err != nil {
+			return nil, Wrap("error parsing outbox time", err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+func (r *OutboxRepo) Claim(ctx context.Context, ids []int64, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var claimedUntil *time.Time
+	if !until.IsZero() {
+		claimedUntil = &until
+	}
+	args := []any{claimedUntil}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	if _, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE outbox SET claimeduntil = ? WHERE id IN ("+placeholders+")", args...); err != nil {
+		return Wrap("error claiming outbox messages", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkPublished(ctx context.Context, ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := []any{at}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	if _, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE outbox SET publishedat = ? WHERE id IN ("+placeholders+")", args...); err != nil {
+		return Wrap("error marking outbox messages published", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM outbox WHERE publishedat < ?", before)
+	if err != nil {
+		return 0, Wrap("error deleting published outbox messages", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, Wrap("error checking rows affected for outbox delete", err)
+	}
+	return deleted, nil
+}
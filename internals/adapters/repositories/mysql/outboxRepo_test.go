@@ -0,0 +1,133 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParkingChangeAndOutboxShareATransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	slots, tickets, outbox := NewSlotRepo(db), NewTicketRepo(db), NewOutboxRepo(db)
+	tx := NewTransactor(db)
+	slot := domain.Slot{SlotId: 1, SlotType: "car", IsFree: false}
+	ticket := domain.Ticket{TicketId: 7, VehicleNumber: "UP16AB1234", SlotId: 1, EntryTime: time.Now()}
+	event := domain.NewTicketEvent(domain.EventTicketOpened, ticket, slot)
+	park := func(ctx context.Context) error {
+		if err := slots.UpdateSlot(ctx, &slot); err != nil {
+			return err
+		}
+		if err := tickets.SaveTicket(ctx, ticket); err != nil {
+			return err
+		}
+		return outbox.AddEvents(ctx, event)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)UPDATE\s+slots`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+tickets`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+outbox\s+\(eventid,\s*eventtype,\s*payload,\s*createdat\)`).
+		WithArgs(event.ID, domain.EventTicketOpened, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, tx.WithinTx(ctx, park))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)UPDATE\s+slots`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+tickets`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+outbox`).WillReturnError(errors.New("disk full"))
+	mock.ExpectRollback()
+	assert.Error(t, tx.WithinTx(ctx, park))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`(?i)UPDATE\s+slots`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+tickets`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+outbox`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		return tx.WithinTx(ctx, park)
+	})
+	assert.NoError(t, err, "a nested call joins the outer transaction")
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListUnpublishedOutboxMessages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepo(db)
+	now := time.Now()
+	mock.ExpectQuery(`(?i)FROM\s+outbox\s+WHERE\s+publishedat\s+IS\s+NULL\s+AND\s+\(claimeduntil\s+IS\s+NULL\s+OR\s+claimeduntil\s*<\s*\?\)\s+ORDER\s+BY\s+id\s+LIMIT\s+\?\s+FOR\s+UPDATE\s+SKIP\s+LOCKED`).
+		WithArgs(now, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "createdat"}).
+			AddRow(3, `{"id":"e3","type":"slot.freed","at":"2025-09-08T10:00:00Z","slotid":1,"slottype":"car"}`, "2025-09-08 10:00:00"))
+	messages, err := repo.ListUnpublished(ctx, 100, now)
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, int64(3), messages[0].ID)
+		assert.Equal(t, "e3", messages[0].Event.ID)
+		assert.Equal(t, domain.EventSlotFreed, messages[0].Event.Type)
+		assert.Nil(t, messages[0].PublishedAt)
+	}
+
+	mock.ExpectQuery(`(?i)FROM\s+outbox`).WillReturnError(errors.New("connection lost"))
+	_, err = repo.ListUnpublished(ctx, 100, now)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimOutboxMessages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepo(db)
+	until := time.Now().Add(time.Minute)
+	query := `(?i)UPDATE\s+outbox\s+SET\s+claimeduntil\s*=\s*\?\s+WHERE\s+id\s+IN\s+\(\?,\s*\?\)`
+	mock.ExpectExec(query).
+		WithArgs(until, int64(3), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, repo.Claim(ctx, []int64{3, 4}, until))
+
+	mock.ExpectExec(query).
+		WithArgs(nil, int64(3), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, repo.Claim(ctx, []int64{3, 4}, time.Time{}), "a zero time releases the claim")
+	assert.NoError(t, repo.Claim(ctx, nil, until), "nothing to claim runs no query")
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkAndDeletePublishedOutboxMessages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewOutboxRepo(db)
+	now := time.Now()
+	mock.ExpectExec(`(?i)UPDATE\s+outbox\s+SET\s+publishedat\s*=\s*\?\s+WHERE\s+id\s+IN\s+\(\?,\s*\?\)`).
+		WithArgs(now, int64(3), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, repo.MarkPublished(ctx, []int64{3, 4}, now))
+	assert.NoError(t, repo.MarkPublished(ctx, nil, now), "nothing to mark runs no query")
+
+	mock.ExpectExec(`(?i)DELETE\s+FROM\s+outbox\s+WHERE\s+publishedat\s*<\s*\?`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	deleted, err := repo.DeletePublished(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), deleted)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,41 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type RevocationRepo struct {
+	db *sql.DB
+}
+
+func NewRevocationRepo(db *sql.DB) *RevocationRepo {
+	return &RevocationRepo{db: db}
+}
+
+// Revoke records tokenID and clears out entries whose tokens have expired
+// since. A row that is already there is left alone and counts zero rows
+// affected, which is how a second revocation is told apart.
+func (r *RevocationRepo) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "INSERT INTO revoked_tokens (tokenid, expiresat) VALUES (?, ?) ON DUPLICATE KEY UPDATE tokenid = tokenid", tokenID, expiresAt)
+	if err != nil {
+		return false, Wrap("error revoking token", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, Wrap("error checking rows affected for token revocation", err)
+	}
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expiresat < ?", time.Now()); err != nil {
+		return false, Wrap("error purging expired revocations", err)
+	}
+	return rows == 1, nil
+}
+
+func (r *RevocationRepo) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM revoked_tokens WHERE tokenid = ?", tokenID).Scan(&count); err != nil {
+		return false, ErrDBQueryFailed
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokeToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRevocationRepo(db)
+	expiresAt := time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)
+
+	insert := `(?i)INSERT\s+INTO\s+revoked_tokens\s+\(tokenid,\s*expiresat\)\s+VALUES\s+\(\?,\s*\?\)\s+ON\s+DUPLICATE\s+KEY\s+UPDATE`
+	purge := `(?i)DELETE\s+FROM\s+revoked_tokens\s+WHERE\s+expiresat\s*<\s*\?`
+
+	mock.ExpectExec(insert).
+		WithArgs("jti-1", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(purge).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	revoked, err := repo.Revoke(ctx, "jti-1", expiresAt)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	mock.ExpectExec(insert).
+		WithArgs("jti-1", expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(purge).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	revoked, err = repo.Revoke(ctx, "jti-1", expiresAt)
+	assert.NoError(t, err)
+	assert.False(t, revoked, "a token revoked twice should be reported as already revoked")
+
+	mock.ExpectExec(insert).
+		WithArgs("jti-1", expiresAt).
+		WillReturnError(errors.New("insert failed"))
+	_, err = repo.Revoke(ctx, "jti-1", expiresAt)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestIsTokenRevoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewRevocationRepo(db)
+	query := `(?i)SELECT\s+COUNT\(\*\)\s+FROM\s+revoked_tokens\s+WHERE\s+tokenid\s*=\s*\?`
+
+	mock.ExpectQuery(query).WithArgs("jti-1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	revoked, err := repo.IsRevoked(ctx, "jti-1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	mock.ExpectQuery(query).WithArgs("jti-2").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	revoked, err = repo.IsRevoked(ctx, "jti-2")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	mock.ExpectQuery(query).WithArgs("jti-3").WillReturnError(errors.New("query error"))
+	_, err = repo.IsRevoked(ctx, "jti-3")
+	assert.ErrorIs(t, err, ErrDBQueryFailed)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
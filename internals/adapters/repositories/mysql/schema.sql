@@ -0,0 +1,165 @@
+-- Money is stored in the minor unit of its currency (paise for INR) next
+-- to the ISO 4217 currency code.
+
+CREATE TABLE IF NOT EXISTS slots (
+    slotid    INT PRIMARY KEY,
+    slottype  VARCHAR(20) NOT NULL,
+    isfree    BOOLEAN NOT NULL DEFAULT TRUE,
+    updatedby VARCHAR(64) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS tickets (
+    ticketid         BIGINT PRIMARY KEY,
+    vehiclenumber    VARCHAR(20) NOT NULL,
+    entrytime        DATETIME NOT NULL,
+    slotid           INT NOT NULL,
+    feeexempt        BOOLEAN NOT NULL DEFAULT FALSE,
+    parkedby         VARCHAR(64) NOT NULL DEFAULT '',
+    exittime         DATETIME NULL,
+    closedby         VARCHAR(64) NOT NULL DEFAULT '',
+    fee              BIGINT NOT NULL DEFAULT 0,
+    netfee           BIGINT NOT NULL DEFAULT 0,
+    tax              BIGINT NOT NULL DEFAULT 0,
+    currency         CHAR(3) NOT NULL DEFAULT '',
+    taxlines         TEXT NULL,
+    paymentmethod    VARCHAR(20) NOT NULL DEFAULT '',
+    paymentreference VARCHAR(64) NOT NULL DEFAULT '',
+    INDEX idx_tickets_vehiclenumber (vehiclenumber)
+);
+
+CREATE TABLE IF NOT EXISTS vehicle_lists (
+    vehiclenumber VARCHAR(20) PRIMARY KEY,
+    listtype      VARCHAR(10) NOT NULL,
+    reason        VARCHAR(255) NOT NULL DEFAULT '',
+    createdat     DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS entry_rejections (
+    id            BIGINT AUTO_INCREMENT PRIMARY KEY,
+    vehiclenumber VARCHAR(20) NOT NULL,
+    reason        VARCHAR(255) NOT NULL,
+    rejectedat    DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    entryid       BIGINT PRIMARY KEY,
+    vehiclenumber VARCHAR(20) NOT NULL,
+    ticketid      BIGINT NOT NULL DEFAULT 0,
+    kind          VARCHAR(20) NOT NULL,
+    amount        BIGINT NOT NULL,
+    currency      CHAR(3) NOT NULL,
+    note          VARCHAR(255) NOT NULL DEFAULT '',
+    createdat     DATETIME NOT NULL,
+    paymentmethod    VARCHAR(20) NOT NULL DEFAULT '',
+    paymentreference VARCHAR(64) NOT NULL DEFAULT '',
+    INDEX idx_ledger_vehiclenumber (vehiclenumber)
+);
+
+CREATE TABLE IF NOT EXISTS fee_adjustments (
+    adjustmentid    BIGINT PRIMARY KEY,
+    ticketid        BIGINT NOT NULL,
+    vehiclenumber   VARCHAR(20) NOT NULL,
+    amount          BIGINT NOT NULL,
+    currency        CHAR(3) NOT NULL,
+    reason          VARCHAR(255) NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    requestedby     VARCHAR(64) NOT NULL,
+    requestedat     DATETIME NOT NULL,
+    reviewedby      VARCHAR(64) NOT NULL DEFAULT '',
+    reviewedat      DATETIME NULL,
+    appliedat       DATETIME NULL,
+    refundreference VARCHAR(64) NOT NULL DEFAULT '',
+    INDEX idx_adjustments_ticketid (ticketid)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id           VARCHAR(64) PRIMARY KEY,
+    username     VARCHAR(64) NOT NULL UNIQUE,
+    passwordhash VARCHAR(255) NOT NULL,
+    role         VARCHAR(20) NOT NULL,
+    disabled     BOOLEAN NOT NULL DEFAULT FALSE,
+    tokenversion INT NOT NULL DEFAULT 0,
+    createdat    DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    tokenid   VARCHAR(64) PRIMARY KEY,
+    expiresat DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id         VARCHAR(64) PRIMARY KEY,
+    name       VARCHAR(64) NOT NULL,
+    secrethash CHAR(64) NOT NULL,
+    scopes     TEXT NOT NULL,
+    createdby  VARCHAR(80) NOT NULL,
+    createdat  DATETIME NOT NULL,
+    rotatedat  DATETIME NULL,
+    revokedat  DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    attemptkey  VARCHAR(100) PRIMARY KEY,
+    failures    INT NOT NULL,
+    lastfailure DATETIME NOT NULL,
+    lockeduntil DATETIME NULL
+);
+
+-- audit_log is append-only; the application never updates or deletes rows.
+CREATE TABLE IF NOT EXISTS audit_log (
+    entryid     BIGINT AUTO_INCREMENT PRIMARY KEY,
+    at          DATETIME NOT NULL,
+    actor       VARCHAR(80) NOT NULL,
+    action      VARCHAR(40) NOT NULL,
+    target      VARCHAR(100) NOT NULL,
+    beforevalue JSON NULL,
+    aftervalue  JSON NULL,
+    requestid   VARCHAR(64) NOT NULL DEFAULT '',
+    address     VARCHAR(64) NOT NULL DEFAULT '',
+    detail      VARCHAR(255) NOT NULL DEFAULT '',
+    INDEX idx_audit_at (at),
+    INDEX idx_audit_actor (actor),
+    INDEX idx_audit_target (target)
+);
+
+-- secret is kept in the clear: it is needed to sign every delivery.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         VARCHAR(64) PRIMARY KEY,
+    url        VARCHAR(2048) NOT NULL,
+    eventtypes TEXT NOT NULL,
+    secret     VARCHAR(255) NOT NULL,
+    createdby  VARCHAR(80) NOT NULL,
+    createdat  DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id             VARCHAR(64) PRIMARY KEY,
+    subscriptionid VARCHAR(64) NOT NULL,
+    eventid        VARCHAR(64) NOT NULL,
+    eventtype      VARCHAR(40) NOT NULL,
+    payload        JSON NOT NULL,
+    status         VARCHAR(20) NOT NULL,
+    attempts       INT NOT NULL DEFAULT 0,
+    nextattemptat  DATETIME NOT NULL,
+    lastattemptat  DATETIME NULL,
+    laststatuscode INT NOT NULL DEFAULT 0,
+    lasterror      VARCHAR(255) NOT NULL DEFAULT '',
+    createdat      DATETIME NOT NULL,
+    deliveredat    DATETIME NULL,
+    INDEX idx_webhook_deliveries_due (status, nextattemptat),
+    INDEX idx_webhook_deliveries_subscription (subscriptionid),
+    INDEX idx_webhook_deliveries_event (eventid)
+);
+
+-- Events are written here in the same transaction as the slot and ticket
+-- changes they report, and relayed from here to publishers.
+CREATE TABLE IF NOT EXISTS outbox (
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    eventid     VARCHAR(64) NOT NULL UNIQUE,
+    eventtype   VARCHAR(40) NOT NULL,
+    payload     JSON NOT NULL,
+    createdat   DATETIME NOT NULL,
+    publishedat DATETIME NULL,
+    claimeduntil DATETIME NULL,
+    INDEX idx_outbox_published (publishedat)
+);
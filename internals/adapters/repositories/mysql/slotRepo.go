@@ -1,8 +1,10 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
 )
 
 type SlotRepo struct {
@@ -13,10 +15,12 @@ func NewSlotRepo(db *sql.DB) *SlotRepo {
 	return &SlotRepo{db: db}
 }
 
-func (r *SlotRepo) SaveSlot(slot domain.Slot) error {
-	_, err := r.db.Exec("INSERT INTO slots (slotid, slottype, isfree) VALUES (?, ?, ?)",
-		slot.SlotId, slot.SlotType, slot.IsFree)
-
+func (r *SlotRepo) SaveSlot(ctx context.Context, slot domain.Slot) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "INSERT INTO slots (slotid, slottype, isfree, updatedby) VALUES (?, ?, ?, ?)",
+		slot.SlotId, slot.SlotType, slot.IsFree, slot.UpdatedBy)
+	if isDuplicate(err) {
+		return ports.ErrSlotExists
+	}
 	if err != nil {
 		return Wrap("error inserting slot", err)
 	}
@@ -24,9 +28,9 @@ func (r *SlotRepo) SaveSlot(slot domain.Slot) error {
 
 }
 
-func (r *SlotRepo) UpdateSlot(slot *domain.Slot) error {
-	res, err := r.db.Exec("UPDATE slots SET slottype=?, isfree=? WHERE slotid=?",
-		slot.SlotType, slot.IsFree, slot.SlotId)
+func (r *SlotRepo) UpdateSlot(ctx context.Context, slot *domain.Slot) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE slots SET slottype=?, isfree=?, updatedby=? WHERE slotid=?",
+		slot.SlotType, slot.IsFree, slot.UpdatedBy, slot.SlotId)
 	if err != nil {
 		return Wrap("error executing update slot query", err)
 	}
@@ -39,8 +43,25 @@ func (r *SlotRepo) UpdateSlot(slot *domain.Slot) error {
 	}
 	return nil
 }
-func (r *SlotRepo) ListAvailableSlots() ([]domain.Slot, error) {
-	rows, err := r.db.Query("SELECT slotid,slottype,isfree FROM slots WHERE isfree=true")
+func (r *SlotRepo) ListSlots(ctx context.Context) ([]domain.Slot, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT slotid, slottype, isfree, updatedby FROM slots ORDER BY slotid")
+	if err != nil {
+		return nil, Wrap("error fetching slots", err)
+	}
+	defer rows.Close()
+
+	var slots []domain.Slot
+	for rows.Next() {
+		var s domain.Slot
+		if err := rows.Scan(&s.SlotId, &s.SlotType, &s.IsFree, &s.UpdatedBy); err != nil {
+			return nil, Wrap("error scanning slot", err)
+		}
+		slots = append(slots, s)
+	}
+	return slots, rows.Err()
+}
+func (r *SlotRepo) ListAvailableSlots(ctx context.Context) ([]domain.Slot, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT slotid,slottype,isfree FROM slots WHERE isfree=true")
 	if err != nil {
 		return nil, Wrap("error fetching slots :", err)
 	}
@@ -56,9 +77,9 @@ func (r *SlotRepo) ListAvailableSlots() ([]domain.Slot, error) {
 	}
 	return slots, nil
 }
-func (r *SlotRepo) FindSlotByType(slottype string) ([]domain.Slot, error) {
+func (r *SlotRepo) FindSlotByType(ctx context.Context, slottype string) ([]domain.Slot, error) {
 	var Slots []domain.Slot
-	rows, err := r.db.Query("SELECT slotid, slottype, isfree FROM slots WHERE slottype=? AND isfree=true", slottype)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT slotid, slottype, isfree FROM slots WHERE slottype=? AND isfree=true", slottype)
 	if err != nil {
 		return nil, Wrap("error fetching slot by type :", err)
 	}
@@ -74,9 +95,9 @@ func (r *SlotRepo) FindSlotByType(slottype string) ([]domain.Slot, error) {
 	return Slots, nil
 }
 
-func (r *SlotRepo) FindSlotTypebyID(SlotId int) (string, error) {
+func (r *SlotRepo) FindSlotTypebyID(ctx context.Context, SlotId int) (string, error) {
 	var slottype string
-	row := r.db.QueryRow("SELECT slottype from slots WHERE slotid=?", SlotId)
+	row := conn(ctx, r.db).QueryRowContext(ctx, "SELECT slottype from slots WHERE slotid=?", SlotId)
 	err := row.Scan(&slottype)
 	if err != nil {
 
@@ -88,9 +109,9 @@ func (r *SlotRepo) FindSlotTypebyID(SlotId int) (string, error) {
 	return slottype, nil
 
 }
-func (r *SlotRepo) FindSlotByID(SlotId int) (*domain.Slot, error) {
+func (r *SlotRepo) FindSlotByID(ctx context.Context, SlotId int) (*domain.Slot, error) {
 	var Slot domain.Slot
-	row := r.db.QueryRow("SELECT slotid, slottype, isfree FROM slots WHERE slotid = ?", SlotId)
+	row := conn(ctx, r.db).QueryRowContext(ctx, "SELECT slotid, slottype, isfree FROM slots WHERE slotid = ?", SlotId)
 	err := row.Scan(&Slot.SlotId, &Slot.SlotType, &Slot.IsFree)
 	if err != nil {
 		if err == sql.ErrNoRows {
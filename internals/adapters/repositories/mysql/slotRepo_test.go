@@ -1,15 +1,20 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
+var ctx = context.Background()
+
 func TestSaveSlot(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -28,7 +33,7 @@ func TestSaveSlot(t *testing.T) {
 			slot: domain.Slot{SlotId: 1, SlotType: "car", IsFree: true},
 			mockBehavior: func() {
 				mock.ExpectExec("INSERT INTO slots").
-					WithArgs(1, "car", true).
+					WithArgs(1, "car", true, "").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			expectedError: false,
@@ -38,7 +43,7 @@ func TestSaveSlot(t *testing.T) {
 			slot: domain.Slot{SlotId: 2, SlotType: "bike", IsFree: false},
 			mockBehavior: func() {
 				mock.ExpectExec("INSERT INTO slots").
-					WithArgs(2, "bike", false).
+					WithArgs(2, "bike", false, "").
 					WillReturnError(errors.New("error inserting slot"))
 			},
 			expectedError: true,
@@ -49,7 +54,7 @@ func TestSaveSlot(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior()
 
-			err := repo.SaveSlot(tt.slot)
+			err := repo.SaveSlot(ctx, tt.slot)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -61,6 +66,20 @@ func TestSaveSlot(t *testing.T) {
 	}
 }
 
+func TestSaveSlot_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO slots").
+		WithArgs(1, "car", true, "").
+		WillReturnError(&mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"})
+
+	err = NewSlotRepo(db).SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true})
+	assert.ErrorIs(t, err, ports.ErrSlotExists)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdateSlot(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -76,8 +95,8 @@ func TestUpdateSlot(t *testing.T) {
 			name: "successfully update slot",
 			slot: domain.Slot{SlotId: 1, SlotType: "car", IsFree: false},
 			mockFunc: func() {
-				mock.ExpectExec(`(?i)UPDATE\s+slots\s+SET\s+slottype=\?,\s*isfree=\?\s+WHERE\s+slotid=\?`).
-					WithArgs("car", false, 1).
+				mock.ExpectExec(`(?i)UPDATE\s+slots\s+SET\s+slottype=\?,\s*isfree=\?,\s*updatedby=\?\s+WHERE\s+slotid=\?`).
+					WithArgs("car", false, "", 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
 			},
@@ -87,8 +106,8 @@ func TestUpdateSlot(t *testing.T) {
 			name: "fail to update slot in DB",
 			slot: domain.Slot{SlotId: 1, SlotType: "car", IsFree: false},
 			mockFunc: func() {
-				mock.ExpectExec(`(?i)UPDATE\s+slots\s+SET\s+slottype=\?,\s*isfree=\?\s+WHERE\s+slotid=\?`).
-					WithArgs("car", false, 1).
+				mock.ExpectExec(`(?i)UPDATE\s+slots\s+SET\s+slottype=\?,\s*isfree=\?,\s*updatedby=\?\s+WHERE\s+slotid=\?`).
+					WithArgs("car", false, "", 1).
 					WillReturnError(errors.New("error updating slot"))
 
 			},
@@ -98,12 +117,62 @@ func TestUpdateSlot(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			err := SlotRepo.UpdateSlot(&tt.slot)
+			err := SlotRepo.UpdateSlot(ctx, &tt.slot)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListSlots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	SlotRepo := NewSlotRepo(db)
+	tests := []struct {
+		name          string
+		mockFunc      func()
+		expectedSlots []domain.Slot
+		expectedError bool
+	}{
+		{
+			name: "successfully list slots",
+			mockFunc: func() {
+				mock.ExpectQuery("SELECT slotid, slottype, isfree, updatedby FROM slots ORDER BY slotid").
+					WillReturnRows(sqlmock.NewRows([]string{"slotid", "slottype", "isfree", "updatedby"}).
+						AddRow(1, "car", false, "ravi").
+						AddRow(2, "bike", true, "admin"))
+			},
+			expectedSlots: []domain.Slot{
+				{SlotId: 1, SlotType: "car", IsFree: false, UpdatedBy: "ravi"},
+				{SlotId: 2, SlotType: "bike", IsFree: true, UpdatedBy: "admin"},
+			},
+			expectedError: false,
+		},
+		{
+			name: "failed to list slots",
+			mockFunc: func() {
+				mock.ExpectQuery("SELECT slotid, slottype, isfree, updatedby FROM slots ORDER BY slotid").
+					WillReturnError(errors.New("error fetching slots"))
+			},
+			expectedSlots: nil,
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			slots, err := SlotRepo.ListSlots(ctx)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.Equal(t, tt.expectedSlots, slots)
 			assert.Nil(t, mock.ExpectationsWereMet())
 		})
 	}
@@ -147,7 +216,7 @@ func TestListAvailableSlots(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			_, err := SlotRepo.ListAvailableSlots()
+			_, err := SlotRepo.ListAvailableSlots(ctx)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -203,7 +272,7 @@ func TestFindSlotByType(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(tt.slotType)
 
-			_, err := SlotRepo.FindSlotByType(tt.slotType)
+			_, err := SlotRepo.FindSlotByType(ctx, tt.slotType)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -265,7 +334,7 @@ func TestFindSlotTypeByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			slotType, err := repo.FindSlotTypebyID(tt.slotID)
+			slotType, err := repo.FindSlotTypebyID(ctx, tt.slotID)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -330,7 +399,7 @@ func TestFindSlotByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			slot, err := repo.FindSlotByID(tt.slotID)
+			slot, err := repo.FindSlotByID(ctx, tt.slotID)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -1,7 +1,9 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"parkingSlotManagement/internals/core/domain"
 	"time"
 )
@@ -13,16 +15,40 @@ type TicketRepo struct {
 func NewTicketRepo(db *sql.DB) *TicketRepo {
 	return &TicketRepo{db: db}
 }
-func (t *TicketRepo) SaveTicket(ticket domain.Ticket) error {
-	_, err := t.db.Exec("INSERT INTO  tickets (ticketid,vehiclenumber,entrytime,slotid)VALUES (?,?,?,?)",
-		ticket.TicketId, ticket.VehicleNumber, ticket.EntryTime, ticket.SlotId)
+func (t *TicketRepo) SaveTicket(ctx context.Context, ticket domain.Ticket) error {
+	_, err := conn(ctx, t.db).ExecContext(ctx, "INSERT INTO  tickets (ticketid,vehiclenumber,entrytime,slotid,feeexempt,parkedby)VALUES (?,?,?,?,?,?)",
+		ticket.TicketId, ticket.VehicleNumber, ticket.EntryTime, ticket.SlotId, ticket.FeeExempt, ticket.ParkedBy)
 	if err != nil {
 		return ErrDBQueryFailed
 	}
 	return nil
 }
-func (t *TicketRepo) DeleteTicket(ticketid int64) error {
-	_, err := t.db.Exec("DELETE FROM tickets WHERE ticketid=?", ticketid)
+
+// CloseTicket stores the exit details of a ticket. Closed tickets are kept
+// for receipts and reports but no longer count as parked. Tax lines are
+// stored as JSON.
+func (t *TicketRepo) CloseTicket(ctx context.Context, ticket domain.Ticket) error {
+	taxLines, err := json.Marshal(ticket.TaxLines)
+	if err != nil {
+		return Wrap("error encoding tax lines", err)
+	}
+	res, err := conn(ctx, t.db).ExecContext(ctx, "UPDATE tickets SET exittime=?, closedby=?, fee=?, netfee=?, tax=?, currency=?, taxlines=?, paymentmethod=?, paymentreference=? WHERE ticketid=?",
+		ticket.ExitTime, ticket.ClosedBy, ticket.Fee.Amount, ticket.NetFee.Amount, ticket.Tax.Amount, ticket.Fee.Currency, string(taxLines),
+		ticket.PaymentMethod, ticket.PaymentReference, ticket.TicketId)
+	if err != nil {
+		return ErrDBQueryFailed
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return Wrap("error checking rows affected for ticket close", err)
+	}
+	if row == 0 {
+		return ErrTicketNotFound
+	}
+	return nil
+}
+func (t *TicketRepo) DeleteTicket(ctx context.Context, ticketid int64) error {
+	_, err := conn(ctx, t.db).ExecContext(ctx, "DELETE FROM tickets WHERE ticketid=?", ticketid)
 
 	if err != nil {
 		return ErrDBQueryFailed
@@ -30,12 +56,12 @@ func (t *TicketRepo) DeleteTicket(ticketid int64) error {
 	return nil
 }
 
-func (t *TicketRepo) FindTicketByVehicleNumber(Vehiclenumber string) (*domain.Ticket, error) {
+func (t *TicketRepo) FindTicketByVehicleNumber(ctx context.Context, Vehiclenumber string) (*domain.Ticket, error) {
 	var Ticket domain.Ticket
 	var entryTimeStr string
 
-	row := t.db.QueryRow("SELECT ticketid, vehiclenumber, entrytime, slotid FROM tickets WHERE vehiclenumber = ?", Vehiclenumber)
-	err := row.Scan(&Ticket.TicketId, &Ticket.VehicleNumber, &entryTimeStr, &Ticket.SlotId)
+	row := conn(ctx, t.db).QueryRowContext(ctx, "SELECT ticketid, vehiclenumber, entrytime, slotid, feeexempt, parkedby FROM tickets WHERE vehiclenumber = ? AND exittime IS NULL", Vehiclenumber)
+	err := row.Scan(&Ticket.TicketId, &Ticket.VehicleNumber, &entryTimeStr, &Ticket.SlotId, &Ticket.FeeExempt, &Ticket.ParkedBy)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -44,7 +70,7 @@ func (t *TicketRepo) FindTicketByVehicleNumber(Vehiclenumber string) (*domain.Ti
 		return nil, ErrDBQueryFailed
 	}
 
-	Ticket.EntryTime, err = time.Parse("2006-01-02 15:04:05", entryTimeStr)
+	Ticket.EntryTime, err = parseDBTime(entryTimeStr)
 	if err != nil {
 		return nil, Wrap("error parsing entry time", err)
 	}
@@ -52,3 +78,89 @@ func (t *TicketRepo) FindTicketByVehicleNumber(Vehiclenumber string) (*domain.Ti
 	return &Ticket, nil
 
 }
+
+const closedTicketColumns = "ticketid, vehiclenumber, entrytime, slotid, feeexempt, parkedby, exittime, closedby, fee, netfee, tax, currency, taxlines, paymentmethod, paymentreference"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanClosedTicket(row rowScanner) (*domain.Ticket, error) {
+	var ticket domain.Ticket
+	var entryTimeStr string
+	var currency domain.Currency
+	var exitTimeStr, taxLines sql.NullString
+	err := row.Scan(&ticket.TicketId, &ticket.VehicleNumber, &entryTimeStr, &ticket.SlotId, &ticket.FeeExempt, &ticket.ParkedBy,
+		&exitTimeStr, &ticket.ClosedBy, &ticket.Fee.Amount, &ticket.NetFee.Amount, &ticket.Tax.Amount, &currency, &taxLines,
+		&ticket.PaymentMethod, &ticket.PaymentReference)
+	if err != nil {
+		return nil, err
+	}
+	ticket.Fee.Currency, ticket.NetFee.Currency, ticket.Tax.Currency = currency, currency, currency
+	if taxLines.Valid && taxLines.String != "" {
+		if err := json.Unmarshal([]byte(taxLines.String), &ticket.TaxLines); err != nil {
+			return nil, Wrap("error decoding tax lines", err)
+		}
+	}
+	if ticket.EntryTime, err = parseDBTime(entryTimeStr); err != nil {
+		return nil, Wrap("error parsing entry time", err)
+	}
+	if ticket.ExitTime, err = parseNullDBTime(exitTimeStr); err != nil {
+		return nil, Wrap("error parsing exit time", err)
+	}
+	return &ticket, nil
+}
+
+func (t *TicketRepo) FindTicketByID(ctx context.Context, ticketid int64) (*domain.Ticket, error) {
+	row := conn(ctx, t.db).QueryRowContext(ctx, "SELECT "+closedTicketColumns+" FROM tickets WHERE ticketid = ?", ticketid)
+	ticket, err := scanClosedTicket(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTicketNotFound
+		}
+		return nil, ErrDBQueryFailed
+	}
+	return ticket, nil
+}
+
+func (t *TicketRepo) ListClosedTickets(ctx context.Context, from, to time.Time) ([]domain.Ticket, error) {
+	rows, err := conn(ctx, t.db).QueryContext(ctx, "SELECT "+closedTicketColumns+" FROM tickets WHERE exittime >= ? AND exittime < ? ORDER BY exittime", from, to)
+	if err != nil {
+		return nil, Wrap("error fetching closed tickets", err)
+	}
+	defer rows.Close()
+
+	var tickets []domain.Ticket
+	for rows.Next() {
+		ticket, err := scanClosedTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, *ticket)
+	}
+	return tickets, nil
+}
+
+// ListOpenTickets returns the tickets of vehicles still parked, oldest
+// first.
+func (t *TicketRepo) ListOpenTickets(ctx context.Context) ([]domain.Ticket, error) {
+	rows, err := conn(ctx, t.db).QueryContext(ctx, "SELECT ticketid, vehiclenumber, entrytime, slotid, feeexempt, parkedby FROM tickets WHERE exittime IS NULL ORDER BY entrytime")
+	if err != nil {
+		return nil, Wrap("error fetching open tickets", err)
+	}
+	defer rows.Close()
+
+	var tickets []domain.Ticket
+	for rows.Next() {
+		var ticket domain.Ticket
+		var entryTimeStr string
+		if err := rows.Scan(&ticket.TicketId, &ticket.VehicleNumber, &entryTimeStr, &ticket.SlotId, &ticket.FeeExempt, &ticket.ParkedBy); err != nil {
+			return nil, Wrap("error scanning open ticket", err)
+		}
+		if ticket.EntryTime, err = parseDBTime(entryTimeStr); err != nil {
+			return nil, Wrap("error parsing entry time", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, rows.Err()
+}
@@ -32,11 +32,12 @@ func TestSaveTicket(t *testing.T) {
 				VehicleNumber: "UP16AB1234",
 				EntryTime:     time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC),
 				SlotId:        1,
+				ParkedBy:      "ravi",
 			},
 			mockFunc: func(ticket domain.Ticket) {
 
-				mock.ExpectExec(`(?i)INSERT\s+INTO\s+tickets\s*\(ticketid,vehiclenumber,entrytime,slotid\)\s*VALUES\s*\(\?,\?,\?,\?\)`).
-					WithArgs(ticket.TicketId, ticket.VehicleNumber, sqlmock.AnyArg(), ticket.SlotId).
+				mock.ExpectExec(`(?i)INSERT\s+INTO\s+tickets\s*\(ticketid,vehiclenumber,entrytime,slotid,feeexempt,parkedby\)\s*VALUES\s*\(\?,\?,\?,\?,\?,\?\)`).
+					WithArgs(ticket.TicketId, ticket.VehicleNumber, sqlmock.AnyArg(), ticket.SlotId, ticket.FeeExempt, ticket.ParkedBy).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 			},
@@ -51,8 +52,8 @@ func TestSaveTicket(t *testing.T) {
 				SlotId:        2,
 			},
 			mockFunc: func(ticket domain.Ticket) {
-				mock.ExpectExec(`(?i)INSERT\s+INTO\s+tickets\s*\(ticketid,vehiclenumber,entrytime,slotid\)\s*VALUES\s*\(\?,\?,\?,\?\)`).
-					WithArgs(ticket.TicketId, ticket.VehicleNumber, sqlmock.AnyArg(), ticket.SlotId).
+				mock.ExpectExec(`(?i)INSERT\s+INTO\s+tickets\s*\(ticketid,vehiclenumber,entrytime,slotid,feeexempt,parkedby\)\s*VALUES\s*\(\?,\?,\?,\?,\?,\?\)`).
+					WithArgs(ticket.TicketId, ticket.VehicleNumber, sqlmock.AnyArg(), ticket.SlotId, ticket.FeeExempt, ticket.ParkedBy).
 					WillReturnError(errors.New("insert failed"))
 			},
 			expectedError: true,
@@ -62,7 +63,7 @@ func TestSaveTicket(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc(tt.ticket)
-			err := repo.SaveTicket(tt.ticket)
+			err := repo.SaveTicket(ctx, tt.ticket)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -112,7 +113,7 @@ func TestDeleteTicket(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			err := repo.DeleteTicket(tt.ticketID)
+			err := repo.DeleteTicket(ctx, tt.ticketID)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -140,16 +141,17 @@ func TestFindTicketByVehicleNumber(t *testing.T) {
 			name:          "successfully find ticket",
 			vehicleNumber: "UP16AB1234",
 			mockFunc: func() {
-				mock.ExpectQuery(`(?i)SELECT\s+ticketid,\s*vehiclenumber,\s*entrytime,\s*slotid\s+FROM\s+tickets\s+WHERE\s+vehiclenumber\s*=\s*\?`).
+				mock.ExpectQuery(`(?i)SELECT\s+ticketid,\s*vehiclenumber,\s*entrytime,\s*slotid,\s*feeexempt,\s*parkedby\s+FROM\s+tickets\s+WHERE\s+vehiclenumber\s*=\s*\?\s+AND\s+exittime\s+IS\s+NULL`).
 					WithArgs("UP16AB1234").
-					WillReturnRows(sqlmock.NewRows([]string{"ticketid", "vehiclenumber", "entrytime", "slotid"}).
-						AddRow(1, "UP16AB1234", "2025-09-08 10:00:00", 101))
+					WillReturnRows(sqlmock.NewRows([]string{"ticketid", "vehiclenumber", "entrytime", "slotid", "feeexempt", "parkedby"}).
+						AddRow(1, "UP16AB1234", "2025-09-08 10:00:00", 101, false, "ravi"))
 			},
 			expectedTicket: &domain.Ticket{
 				TicketId:      1,
 				VehicleNumber: "UP16AB1234",
 				EntryTime:     time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC),
 				SlotId:        101,
+				ParkedBy:      "ravi",
 			},
 			expectedError: false,
 		},
@@ -157,7 +159,7 @@ func TestFindTicketByVehicleNumber(t *testing.T) {
 			name:          "fail to find ticket",
 			vehicleNumber: "UP16XY5678",
 			mockFunc: func() {
-				mock.ExpectQuery(`(?i)SELECT\s+ticketid,\s*vehiclenumber,\s*entrytime,\s*slotid\s+FROM\s+tickets\s+WHERE\s+vehiclenumber\s*=\s*\?`).
+				mock.ExpectQuery(`(?i)SELECT\s+ticketid,\s*vehiclenumber,\s*entrytime,\s*slotid,\s*feeexempt,\s*parkedby\s+FROM\s+tickets\s+WHERE\s+vehiclenumber\s*=\s*\?\s+AND\s+exittime\s+IS\s+NULL`).
 					WithArgs("UP16XY5678").
 					WillReturnError(errors.New("query error"))
 			},
@@ -169,7 +171,7 @@ func TestFindTicketByVehicleNumber(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			ticket, err := repo.FindTicketByVehicleNumber(tt.vehicleNumber)
+			ticket, err := repo.FindTicketByVehicleNumber(ctx, tt.vehicleNumber)
 			if tt.expectedError {
 				assert.Error(t, err)
 			} else {
@@ -180,3 +182,170 @@ func TestFindTicketByVehicleNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestCloseTicket(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketRepo(db)
+	exitTime := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	ticket := domain.Ticket{
+		TicketId:      1,
+		ClosedBy:      "ravi",
+		VehicleNumber: "UP16AB1234",
+		ExitTime:      &exitTime,
+		Fee:           domain.NewMoney(11800, domain.CurrencyINR),
+		NetFee:        domain.NewMoney(10000, domain.CurrencyINR),
+		Tax:           domain.NewMoney(1800, domain.CurrencyINR),
+		TaxLines: []domain.TaxLine{
+			{Name: "CGST", Rate: 0.09, Amount: domain.NewMoney(900, domain.CurrencyINR)},
+			{Name: "SGST", Rate: 0.09, Amount: domain.NewMoney(900, domain.CurrencyINR)},
+		},
+		PaymentMethod:    "cash",
+		PaymentReference: "cash_000001",
+	}
+	taxLines := `[{"name":"CGST","rate":0.09,"amount":{"amount":"9.00","currency":"INR"}},` +
+		`{"name":"SGST","rate":0.09,"amount":{"amount":"9.00","currency":"INR"}}]`
+	query := `(?i)UPDATE\s+tickets\s+SET\s+exittime=\?,\s*closedby=\?,\s*fee=\?,\s*netfee=\?,\s*tax=\?,\s*currency=\?,\s*taxlines=\?,\s*paymentmethod=\?,\s*paymentreference=\?\s+WHERE\s+ticketid=\?`
+
+	tests := []struct {
+		name          string
+		mockFunc      func()
+		expectedError error
+	}{
+		{
+			name: "successfully close ticket",
+			mockFunc: func() {
+				mock.ExpectExec(query).
+					WithArgs(sqlmock.AnyArg(), "ravi", int64(11800), int64(10000), int64(1800), domain.CurrencyINR, taxLines, "cash", "cash_000001", int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "ticket does not exist",
+			mockFunc: func() {
+				mock.ExpectExec(query).
+					WithArgs(sqlmock.AnyArg(), "ravi", int64(11800), int64(10000), int64(1800), domain.CurrencyINR, taxLines, "cash", "cash_000001", int64(1)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedError: ErrTicketNotFound,
+		},
+		{
+			name: "fail to close ticket",
+			mockFunc: func() {
+				mock.ExpectExec(query).
+					WithArgs(sqlmock.AnyArg(), "ravi", int64(11800), int64(10000), int64(1800), domain.CurrencyINR, taxLines, "cash", "cash_000001", int64(1)).
+					WillReturnError(errors.New("update error"))
+			},
+			expectedError: ErrDBQueryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := repo.CloseTicket(ctx, ticket)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+var closedTicketRowColumns = []string{"ticketid", "vehiclenumber", "entrytime", "slotid", "feeexempt", "parkedby", "exittime", "closedby", "fee", "netfee", "tax", "currency", "taxlines", "paymentmethod", "paymentreference"}
+
+func TestFindTicketByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketRepo(db)
+	query := `(?i)SELECT\s+ticketid,.*FROM\s+tickets\s+WHERE\s+ticketid\s*=\s*\?`
+
+	mock.ExpectQuery(query).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(closedTicketRowColumns).
+			AddRow(1, "UP16AB1234", "2025-09-08 10:00:00", 101, false, "ravi", "2025-09-08 12:00:00", "meena", 11800, 10000, 1800, "INR",
+				`[{"name":"CGST","rate":0.09,"amount":"9.00"},{"name":"SGST","rate":0.09,"amount":"9.00"}]`, "cash", "cash_000001"))
+	ticket, err := repo.FindTicketByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, ticket.TaxLines, 2)
+	assert.Equal(t, domain.NewMoney(1800, domain.CurrencyINR), ticket.Tax)
+	assert.Equal(t, domain.NewMoney(900, domain.CurrencyINR), ticket.TaxLines[1].Amount)
+	assert.Equal(t, time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC), *ticket.ExitTime)
+	assert.Equal(t, "cash_000001", ticket.PaymentReference)
+	assert.Equal(t, "ravi", ticket.ParkedBy)
+	assert.Equal(t, "meena", ticket.ClosedBy)
+
+	mock.ExpectQuery(query).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(closedTicketRowColumns).
+			AddRow(2, "UP16AB1234", "2025-09-08 10:00:00", 101, false, "ravi", nil, "", 0, 0, 0, "", nil, "", ""))
+	ticket, err = repo.FindTicketByID(ctx, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, ticket.ExitTime)
+
+	mock.ExpectQuery(query).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(closedTicketRowColumns))
+	_, err = repo.FindTicketByID(ctx, 3)
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListClosedTickets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketRepo(db)
+	from := time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	query := `(?i)FROM\s+tickets\s+WHERE\s+exittime\s*>=\s*\?\s+AND\s+exittime\s*<\s*\?`
+
+	mock.ExpectQuery(query).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows(closedTicketRowColumns).
+			AddRow(1, "UP16AB1234", "2025-09-08 10:00:00", 101, false, "ravi", "2025-09-08 12:00:00", "meena", 12000, 12000, 0, "INR", "null", "cash", "cash_000001"))
+	tickets, err := repo.ListClosedTickets(ctx, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 1)
+
+	mock.ExpectQuery(query).
+		WithArgs(from, to).
+		WillReturnError(errors.New("query error"))
+	_, err = repo.ListClosedTickets(ctx, from, to)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListOpenTickets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTicketRepo(db)
+	query := `(?i)SELECT\s+ticketid,\s*vehiclenumber,\s*entrytime,\s*slotid,\s*feeexempt,\s*parkedby\s+FROM\s+tickets\s+WHERE\s+exittime\s+IS\s+NULL`
+
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"ticketid", "vehiclenumber", "entrytime", "slotid", "feeexempt", "parkedby"}).
+			AddRow(1, "UP16AB1234", "2025-09-08 10:00:00", 101, false, "ravi").
+			AddRow(2, "DL01CD5678", "2025-09-08 11:00:00", 102, true, "meena"))
+	tickets, err := repo.ListOpenTickets(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	assert.Equal(t, 102, tickets[1].SlotId)
+	assert.Nil(t, tickets[0].ExitTime)
+
+	mock.ExpectQuery(query).WillReturnError(errors.New("query error"))
+	_, err = repo.ListOpenTickets(ctx)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
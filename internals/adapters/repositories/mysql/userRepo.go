@@ -0,0 +1,110 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type UserRepo struct {
+	db *sql.DB
+}
+
+func NewUserRepo(db *sql.DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+const userColumns = "id, username, passwordhash, role, disabled, tokenversion, createdat"
+
+func (r *UserRepo) SaveUser(ctx context.Context, user domain.User) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO users ("+userColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)",
+		user.ID, user.Username, user.PasswordHash, user.Role, user.Disabled, user.TokenVersion, user.CreatedAt)
+	if err != nil {
+		return Wrap("error saving user", err)
+	}
+	return nil
+}
+
+// UpdateUser never lowers tokenversion, so an update made from a copy read
+// before a password change doesn't bring the old tokens back.
+func (r *UserRepo) UpdateUser(ctx context.Context, user domain.User) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE users SET username=?, passwordhash=?, role=?, disabled=?, tokenversion=GREATEST(tokenversion, ?) WHERE id=?",
+		user.Username, user.PasswordHash, user.Role, user.Disabled, user.TokenVersion, user.ID)
+	if err != nil {
+		return Wrap("error updating user", err)
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return Wrap("error checking rows affected for user update", err)
+	}
+	if row == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var user domain.User
+	var createdAtStr string
+	if err := row.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled, &user.TokenVersion, &createdAtStr); err != nil {
+		return nil, err
+	}
+	createdAt, err := parseDBTime(createdAtStr)
+	if err != nil {
+		return nil, Wrap("error parsing created time", err)
+	}
+	user.CreatedAt = createdAt
+	return &user, nil
+}
+
+func (r *UserRepo) findUser(ctx context.Context, column, value string) (*domain.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE "+column+" = ?", value))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, ErrDBQueryFailed
+	}
+	return user, nil
+}
+
+func (r *UserRepo) FindUserByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.findUser(ctx, "id", id)
+}
+
+func (r *UserRepo) FindUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.findUser(ctx, "username", username)
+}
+
+func (r *UserRepo) ListUsers(ctx context.Context) ([]domain.User, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY username")
+	if err != nil {
+		return nil, Wrap("error fetching users", err)
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+func (r *UserRepo) DeleteUser(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+	if err != nil {
+		return Wrap("error deleting user", err)
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return Wrap("error checking rows affected for user delete", err)
+	}
+	if row == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package mysql
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var userRowColumns = []string{"id", "username", "passwordhash", "role", "disabled", "tokenversion", "createdat"}
+
+func TestSaveUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepo(db)
+	user := domain.User{ID: "user-1", Username: "ravi", PasswordHash: "$2a$10$hash", Role: domain.RoleAttendant,
+		CreatedAt: time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+users`).
+		WithArgs(user.ID, user.Username, user.PasswordHash, user.Role, false, 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveUser(ctx, user))
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+users`).
+		WithArgs(user.ID, user.Username, user.PasswordHash, user.Role, false, 0, sqlmock.AnyArg()).
+		WillReturnError(errors.New("duplicate entry"))
+	assert.Error(t, repo.SaveUser(ctx, user))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepo(db)
+	user := domain.User{ID: "user-1", Username: "ravi", PasswordHash: "$2a$10$hash", Role: domain.RoleSupervisor}
+	query := `(?i)UPDATE\s+users\s+SET\s+username=\?,\s*passwordhash=\?,\s*role=\?,\s*disabled=\?,\s*tokenversion=GREATEST\(tokenversion,\s*\?\)\s+WHERE\s+id=\?`
+
+	mock.ExpectExec(query).WithArgs("ravi", "$2a$10$hash", domain.RoleSupervisor, false, 0, "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateUser(ctx, user))
+
+	mock.ExpectExec(query).WithArgs("ravi", "$2a$10$hash", domain.RoleSupervisor, false, 0, "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.UpdateUser(ctx, user), ErrUserNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFindUserByUsername(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepo(db)
+	query := `(?i)SELECT\s+id,\s*username,\s*passwordhash,\s*role,\s*disabled,\s*tokenversion,\s*createdat\s+FROM\s+users\s+WHERE\s+username\s*=\s*\?`
+
+	tests := []struct {
+		name         string
+		mockFunc     func()
+		expectedUser *domain.User
+		expectedErr  bool
+	}{
+		{
+			name: "successfully find user",
+			mockFunc: func() {
+				mock.ExpectQuery(query).WithArgs("ravi").
+					WillReturnRows(sqlmock.NewRows(userRowColumns).
+						AddRow("user-1", "ravi", "$2a$10$hash", "attendant", false, 0, "2025-09-08 10:00:00"))
+			},
+			expectedUser: &domain.User{ID: "user-1", Username: "ravi", PasswordHash: "$2a$10$hash", Role: domain.RoleAttendant,
+				CreatedAt: time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "user does not exist",
+			mockFunc: func() {
+				mock.ExpectQuery(query).WithArgs("ravi").WillReturnRows(sqlmock.NewRows(userRowColumns))
+			},
+			expectedUser: nil,
+		},
+		{
+			name: "query fails",
+			mockFunc: func() {
+				mock.ExpectQuery(query).WithArgs("ravi").WillReturnError(errors.New("query error"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			user, err := repo.FindUserByUsername(ctx, "ravi")
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUser, user)
+			}
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFindUserByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepo(db)
+	mock.ExpectQuery(`(?i)FROM\s+users\s+WHERE\s+id\s*=\s*\?`).WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows(userRowColumns).
+			AddRow("user-1", "ravi", "$2a$10$hash", "auditor", true, 3, "2025-09-08 10:00:00"))
+	user, err := repo.FindUserByID(ctx, "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleAuditor, user.Role)
+	assert.True(t, user.Disabled)
+	assert.Equal(t, 3, user.TokenVersion)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepo(db)
+	query := `(?i)FROM\s+users\s+ORDER\s+BY\s+username`
+
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(userRowColumns).
+			AddRow("user-1", "admin", "$2a$10$hash", "admin", false, 0, "2025-09-08 10:00:00").
+			AddRow("user-2", "ravi", "$2a$10$hash", "attendant", false, 0, "2025-09-08 11:00:00"))
+	users, err := repo.ListUsers(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	mock.ExpectQuery(query).WillReturnError(errors.New("query error"))
+	_, err = repo.ListUsers(ctx)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUserRepo(db)
+	query := `(?i)DELETE\s+FROM\s+users\s+WHERE\s+id\s*=\s*\?`
+
+	mock.ExpectExec(query).WithArgs("user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteUser(ctx, "user-1"))
+
+	mock.ExpectExec(query).WithArgs("user-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteUser(ctx, "user-1"), ErrUserNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
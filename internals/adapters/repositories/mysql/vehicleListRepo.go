@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+)
+
+type VehicleListRepo struct {
+	db *sql.DB
+}
+
+func NewVehicleListRepo(db *sql.DB) *VehicleListRepo {
+	return &VehicleListRepo{db: db}
+}
+
+func (r *VehicleListRepo) SaveEntry(ctx context.Context, entry domain.VehicleListEntry) error {
+	_, err := r.db.ExecContext(ctx, "REPLACE INTO vehicle_lists (vehiclenumber, listtype, reason, createdat) VALUES (?, ?, ?, ?)",
+		entry.VehicleNumber, entry.ListType, entry.Reason, entry.CreatedAt)
+	if err != nil {
+		return Wrap("error saving vehicle list entry", err)
+	}
+	return nil
+}
+
+func (r *VehicleListRepo) FindEntry(ctx context.Context, vehiclenumber string) (*domain.VehicleListEntry, error) {
+	var entry domain.VehicleListEntry
+	var createdAtStr string
+	row := r.db.QueryRowContext(ctx, "SELECT vehiclenumber, listtype, reason, createdat FROM vehicle_lists WHERE vehiclenumber = ?", vehiclenumber)
+	err := row.Scan(&entry.VehicleNumber, &entry.ListType, &entry.Reason, &createdAtStr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, ErrDBQueryFailed
+	}
+	if entry.CreatedAt, err = parseDBTime(createdAtStr); err != nil {
+		return nil, Wrap("error parsing created time", err)
+	}
+	return &entry, nil
+}
+
+func (r *VehicleListRepo) ListEntries(ctx context.Context, listtype string) ([]domain.VehicleListEntry, error) {
+	query := "SELECT vehiclenumber, listtype, reason, createdat FROM vehicle_lists"
+	var args []any
+	if listtype != "" {
+		query += " WHERE listtype = ?"
+		args = append(args, listtype)
+	}
+	rows, err := r.db.QueryContext(ctx, query+" ORDER BY vehiclenumber", args...)
+	if err != nil {
+		return nil, Wrap("error fetching vehicle list entries", err)
+	}
+	defer rows.Close()
+
+	var entries []domain.VehicleListEntry
+	for rows.Next() {
+		var entry domain.VehicleListEntry
+		var createdAtStr string
+		if err := rows.Scan(&entry.VehicleNumber, &entry.ListType, &entry.Reason, &createdAtStr); err != nil {
+			return nil, err
+		}
+		if entry.CreatedAt, err = parseDBTime(createdAtStr); err != nil {
+			return nil, Wrap("error parsing created time", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (r *VehicleListRepo) DeleteEntry(ctx context.Context, vehiclenumber string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM vehicle_lists WHERE vehiclenumber = ?", vehiclenumber)
+	if err != nil {
+		return Wrap("error deleting vehicle list entry", err)
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return Wrap("error checking rows affected for vehicle list delete", err)
+	}
+	if row == 0 {
+		return ports.ErrVehicleListEntryNotFound
+	}
+	return nil
+}
+
+func (r *VehicleListRepo) SaveRejection(ctx context.Context, rejection domain.EntryRejection) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO entry_rejections (vehiclenumber, reason, rejectedat) VALUES (?, ?, ?)",
+		rejection.VehicleNumber, rejection.Reason, rejection.RejectedAt)
+	if err != nil {
+		return Wrap("error saving entry rejection", err)
+	}
+	return nil
+}
+
+func (r *VehicleListRepo) ListRejections(ctx context.Context) ([]domain.EntryRejection, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT vehiclenumber, reason, rejectedat FROM entry_rejections ORDER BY rejectedat")
+	if err != nil {
+		return nil, Wrap("error fetching entry rejections", err)
+	}
+	defer rows.Close()
+
+	var rejections []domain.EntryRejection
+	for rows.Next() {
+		var rejection domain.EntryRejection
+		var rejectedAtStr string
+		if err := rows.Scan(&rejection.VehicleNumber, &rejection.Reason, &rejectedAtStr); err != nil {
+			return nil, err
+		}
+		if rejection.RejectedAt, err = parseDBTime(rejectedAtStr); err != nil {
+			return nil, Wrap("error parsing rejected time", err)
+		}
+		rejections = append(rejections, rejection)
+	}
+	return rejections, nil
+}
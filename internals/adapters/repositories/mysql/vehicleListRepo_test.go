@@ -0,0 +1,169 @@
+package mysql
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveVehicleListEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewVehicleListRepo(db)
+	entry := domain.VehicleListEntry{
+		VehicleNumber: "UP16AB1234",
+		ListType:      domain.BlockList,
+		Reason:        "unpaid fines",
+		CreatedAt:     time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC),
+	}
+
+	mock.ExpectExec(`(?i)REPLACE\s+INTO\s+vehicle_lists`).
+		WithArgs(entry.VehicleNumber, entry.ListType, entry.Reason, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveEntry(ctx, entry))
+
+	mock.ExpectExec(`(?i)REPLACE\s+INTO\s+vehicle_lists`).
+		WithArgs(entry.VehicleNumber, entry.ListType, entry.Reason, sqlmock.AnyArg()).
+		WillReturnError(errors.New("insert failed"))
+	assert.Error(t, repo.SaveEntry(ctx, entry))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFindVehicleListEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewVehicleListRepo(db)
+	query := `(?i)SELECT\s+vehiclenumber,\s*listtype,\s*reason,\s*createdat\s+FROM\s+vehicle_lists\s+WHERE\s+vehiclenumber\s*=\s*\?`
+
+	tests := []struct {
+		name          string
+		mockFunc      func()
+		expectedEntry *domain.VehicleListEntry
+		expectedError bool
+	}{
+		{
+			name: "successfully find entry",
+			mockFunc: func() {
+				mock.ExpectQuery(query).
+					WithArgs("UP16AB1234").
+					WillReturnRows(sqlmock.NewRows([]string{"vehiclenumber", "listtype", "reason", "createdat"}).
+						AddRow("UP16AB1234", "allow", "staff", "2025-09-08 10:00:00"))
+			},
+			expectedEntry: &domain.VehicleListEntry{
+				VehicleNumber: "UP16AB1234",
+				ListType:      domain.AllowList,
+				Reason:        "staff",
+				CreatedAt:     time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "vehicle not on any list",
+			mockFunc: func() {
+				mock.ExpectQuery(query).
+					WithArgs("UP16AB1234").
+					WillReturnRows(sqlmock.NewRows([]string{"vehiclenumber", "listtype", "reason", "createdat"}))
+			},
+			expectedEntry: nil,
+		},
+		{
+			name: "query fails",
+			mockFunc: func() {
+				mock.ExpectQuery(query).
+					WithArgs("UP16AB1234").
+					WillReturnError(errors.New("query error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			entry, err := repo.FindEntry(ctx, "UP16AB1234")
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedEntry, entry)
+			}
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListVehicleListEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewVehicleListRepo(db)
+
+	mock.ExpectQuery(`(?i)FROM\s+vehicle_lists\s+WHERE\s+listtype\s*=\s*\?\s+ORDER\s+BY\s+vehiclenumber`).
+		WithArgs("block").
+		WillReturnRows(sqlmock.NewRows([]string{"vehiclenumber", "listtype", "reason", "createdat"}).
+			AddRow("UP16AB1234", "block", "banned", "2025-09-08 10:00:00"))
+	entries, err := repo.ListEntries(ctx, domain.BlockList)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	mock.ExpectQuery(`(?i)FROM\s+vehicle_lists\s+ORDER\s+BY\s+vehiclenumber`).
+		WillReturnError(errors.New("query error"))
+	_, err = repo.ListEntries(ctx, "")
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteVehicleListEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewVehicleListRepo(db)
+	query := `(?i)DELETE\s+FROM\s+vehicle_lists\s+WHERE\s+vehiclenumber\s*=\s*\?`
+
+	mock.ExpectExec(query).WithArgs("UP16AB1234").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteEntry(ctx, "UP16AB1234"))
+
+	mock.ExpectExec(query).WithArgs("UP16AB1234").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteEntry(ctx, "UP16AB1234"), ports.ErrVehicleListEntryNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestEntryRejections(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewVehicleListRepo(db)
+	rejection := domain.EntryRejection{
+		VehicleNumber: "UP16AB1234",
+		Reason:        "banned",
+		RejectedAt:    time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC),
+	}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+entry_rejections`).
+		WithArgs(rejection.VehicleNumber, rejection.Reason, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveRejection(ctx, rejection))
+
+	mock.ExpectQuery(`(?i)SELECT\s+vehiclenumber,\s*reason,\s*rejectedat\s+FROM\s+entry_rejections`).
+		WillReturnRows(sqlmock.NewRows([]string{"vehiclenumber", "reason", "rejectedat"}).
+			AddRow("UP16AB1234", "banned", "2025-09-08 10:00:00"))
+	rejections, err := repo.ListRejections(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.EntryRejection{rejection}, rejections)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,209 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+	"time"
+)
+
+type WebhookRepo struct {
+	db *sql.DB
+}
+
+func NewWebhookRepo(db *sql.DB) *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+const (
+	webhookColumns  = "id, url, eventtypes, secret, createdby, createdat"
+	deliveryColumns = "id, subscriptionid, eventid, eventtype, payload, status, attempts, nextattemptat, lastattemptat, laststatuscode, lasterror, createdat, deliveredat"
+)
+
+// SaveSubscription stores a new subscription. Event types are stored as
+// JSON.
+func (r *WebhookRepo) SaveSubscription(ctx context.Context, sub domain.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return Wrap("error encoding webhook event types", err)
+	}
+	_, err = r.db.ExecContext(ctx, "INSERT INTO webhook_subscriptions ("+webhookColumns+") VALUES (?, ?, ?, ?, ?, ?)",
+		sub.ID, sub.URL, string(eventTypes), sub.Secret, sub.CreatedBy, sub.CreatedAt)
+	if err != nil {
+		return Wrap("error saving webhook subscription", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = ?", id)
+	if err != nil {
+		return Wrap("error deleting webhook subscription", err)
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return Wrap("error checking rows affected for webhook subscription delete", err)
+	}
+	if row == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func scanWebhook(row rowScanner) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	var eventTypes, createdAtStr string
+	if err := row.Scan(&sub.ID, &sub.URL, &eventTypes, &sub.Secret, &sub.CreatedBy, &createdAtStr); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(eventTypes), &sub.EventTypes); err != nil {
+		return nil, Wrap("error decoding webhook event types", err)
+	}
+	var err error
+	if sub.CreatedAt, err = parseDBTime(createdAtStr); err != nil {
+		return nil, Wrap("error parsing created time", err)
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepo) FindSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, err := scanWebhook(r.db.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhook_subscriptions WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, ErrDBQueryFailed
+	}
+	return sub, nil
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+webhookColumns+" FROM webhook_subscriptions ORDER BY createdat")
+	if err != nil {
+		return nil, Wrap("error fetching webhook subscriptions", err)
+	}
+	defer rows.Close()
+
+	var subs []domain.WebhookSubscription
+	for rows.Next() {
+		sub, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepo) SaveDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO webhook_deliveries ("+deliveryColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		d.ID, d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), d.Status, d.Attempts, d.NextAttemptAt,
+		d.LastAttemptAt, d.LastStatusCode, d.LastError, d.CreatedAt, d.DeliveredAt)
+	if err != nil {
+		return Wrap("error saving webhook delivery", err)
+	}
+	return nil
+}
+
+// UpdateDelivery records the outcome of an attempt. The event and payload
+// never change.
+func (r *WebhookRepo) UpdateDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE webhook_deliveries SET status=?, attempts=?, nextattemptat=?, lastattemptat=?, laststatuscode=?, lasterror=?, deliveredat=? WHERE id=?",
+		d.Status, d.Attempts, d.NextAttemptAt, d.LastAttemptAt, d.LastStatusCode, d.LastError, d.DeliveredAt, d.ID)
+	if err != nil {
+		return Wrap("error updating webhook delivery", err)
+	}
+	row, err := res.RowsAffected()
+	if err != nil {
+		return Wrap("error checking rows affected for webhook delivery update", err)
+	}
+	if row == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func scanDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	var payload, nextAttemptAtStr, createdAtStr string
+	var lastAttemptAtStr, deliveredAtStr sql.NullString
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts, &nextAttemptAtStr,
+		&lastAttemptAtStr, &d.LastStatusCode, &d.LastError, &createdAtStr, &deliveredAtStr)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = json.RawMessage(payload)
+	if d.NextAttemptAt, err = parseDBTime(nextAttemptAtStr); err != nil {
+		return nil, Wrap("error parsing next attempt time", err)
+	}
+	if d.CreatedAt, err = parseDBTime(createdAtStr); err != nil {
+		return nil, Wrap("error parsing created time", err)
+	}
+	if d.LastAttemptAt, err = parseNullDBTime(lastAttemptAtStr); err != nil {
+		return nil, Wrap("error parsing last attempt time", err)
+	}
+	if d.DeliveredAt, err = parseNullDBTime(deliveredAtStr); err != nil {
+		return nil, Wrap("error parsing delivered time", err)
+	}
+	return &d, nil
+}
+
+func (r *WebhookRepo) FindDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	d, err := scanDelivery(r.db.QueryRowContext(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, ErrDBQueryFailed
+	}
+	return d, nil
+}
+
+// ListDeliveries returns matching deliveries newest first.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	var where []string
+	var args []any
+	if filter.SubscriptionID != "" {
+		where, args = append(where, "subscriptionid = ?"), append(args, filter.SubscriptionID)
+	}
+	if filter.EventID != "" {
+		where, args = append(where, "eventid = ?"), append(args, filter.EventID)
+	}
+	if filter.Status != "" {
+		where, args = append(where, "status = ?"), append(args, filter.Status)
+	}
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY createdat DESC"
+	if filter.Limit > 0 {
+		query, args = query+" LIMIT ?", append(args, filter.Limit)
+	}
+	return r.listDeliveries(ctx, query, args...)
+}
+
+func (r *WebhookRepo) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	return r.listDeliveries(ctx, "SELECT "+deliveryColumns+" FROM webhook_deliveries WHERE status = ? AND nextattemptat <= ? ORDER BY nextattemptat LIMIT ?",
+		domain.DeliveryPending, now, limit)
+}
+
+func (r *WebhookRepo) listDeliveries(ctx context.Context, query string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Wrap("error fetching webhook deliveries", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, nil
+}
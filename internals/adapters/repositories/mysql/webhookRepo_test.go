@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	webhookRowColumns  = []string{"id", "url", "eventtypes", "secret", "createdby", "createdat"}
+	deliveryRowColumns = []string{"id", "subscriptionid", "eventid", "eventtype", "payload", "status", "attempts", "nextattemptat",
+		"lastattemptat", "laststatuscode", "lasterror", "createdat", "deliveredat"}
+)
+
+func TestSaveWebhookSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepo(db)
+	sub := domain.WebhookSubscription{ID: "a1b2c3", URL: "https://billing.example.com/hooks", EventTypes: []string{domain.EventTicketClosed},
+		Secret: "s3cret", CreatedBy: "admin", CreatedAt: time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+webhook_subscriptions`).
+		WithArgs(sub.ID, sub.URL, `["ticket.closed"]`, "s3cret", "admin", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveSubscription(ctx, sub))
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+webhook_subscriptions`).
+		WillReturnError(errors.New("duplicate entry"))
+	assert.Error(t, repo.SaveSubscription(ctx, sub))
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteWebhookSubscription(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepo(db)
+	query := `(?i)DELETE\s+FROM\s+webhook_subscriptions\s+WHERE\s+id\s*=\s*\?`
+
+	mock.ExpectExec(query).WithArgs("a1b2c3").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteSubscription(ctx, "a1b2c3"))
+
+	mock.ExpectExec(query).WithArgs("missing").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteSubscription(ctx, "missing"), ErrWebhookNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFindAndListWebhookSubscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepo(db)
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+webhook_subscriptions\s+WHERE\s+id\s*=\s*\?`).
+		WithArgs("a1b2c3").
+		WillReturnRows(sqlmock.NewRows(webhookRowColumns).
+			AddRow("a1b2c3", "https://billing.example.com/hooks", `["ticket.opened","ticket.closed"]`, "s3cret", "admin", "2025-09-08 10:00:00"))
+	sub, err := repo.FindSubscription(ctx, "a1b2c3")
+	assert.NoError(t, err)
+	if assert.NotNil(t, sub) {
+		assert.Equal(t, []string{domain.EventTicketOpened, domain.EventTicketClosed}, sub.EventTypes)
+		assert.Equal(t, "s3cret", sub.Secret)
+	}
+
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+webhook_subscriptions\s+WHERE\s+id\s*=\s*\?`).
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(webhookRowColumns))
+	sub, err = repo.FindSubscription(ctx, "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, sub)
+
+	mock.ExpectQuery(`(?i)SELECT\s+.+\s+FROM\s+webhook_subscriptions\s+ORDER\s+BY\s+createdat`).
+		WillReturnRows(sqlmock.NewRows(webhookRowColumns).
+			AddRow("a1b2c3", "https://billing.example.com/hooks", `["ticket.closed"]`, "s3cret", "admin", "2025-09-08 10:00:00").
+			AddRow("d4e5f6", "https://crm.example.com/hooks", `["ticket.opened"]`, "other", "admin", "2025-09-09 10:00:00"))
+	subs, err := repo.ListSubscriptions(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, subs, 2)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveAndUpdateWebhookDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepo(db)
+	now := time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)
+	d := domain.WebhookDelivery{ID: "d1", SubscriptionID: "a1b2c3", EventID: "e1", EventType: domain.EventTicketClosed,
+		Payload: []byte(`{"id":"e1"}`), Status: domain.DeliveryPending, NextAttemptAt: now, CreatedAt: now}
+
+	mock.ExpectExec(`(?i)INSERT\s+INTO\s+webhook_deliveries`).
+		WithArgs("d1", "a1b2c3", "e1", domain.EventTicketClosed, `{"id":"e1"}`, domain.DeliveryPending, 0, sqlmock.AnyArg(),
+			nil, 0, "", sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, repo.SaveDelivery(ctx, d))
+
+	d.Status, d.Attempts, d.LastStatusCode, d.LastError = domain.DeliveryPending, 1, 500, "receiver answered 500"
+	d.LastAttemptAt = &now
+	query := `(?i)UPDATE\s+webhook_deliveries\s+SET\s+status=\?.*WHERE\s+id=\?`
+	mock.ExpectExec(query).
+		WithArgs(domain.DeliveryPending, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), 500, "receiver answered 500", nil, "d1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateDelivery(ctx, d))
+
+	mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.UpdateDelivery(ctx, d), ErrDeliveryNotFound)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := NewWebhookRepo(db)
+	row := []driver.Value{"d1", "a1b2c3", "e1", domain.EventTicketClosed, `{"id":"e1"}`, domain.DeliveryDead, 8, "2025-09-08 10:00:00",
+		"2025-09-08 11:00:00", 503, "receiver answered 503", "2025-09-08 09:00:00", nil}
+
+	mock.ExpectQuery(`(?i)FROM\s+webhook_deliveries\s+WHERE\s+subscriptionid\s*=\s*\?\s+AND\s+status\s*=\s*\?\s+ORDER\s+BY\s+createdat\s+DESC\s+LIMIT\s+\?`).
+		WithArgs("a1b2c3", domain.DeliveryDead, 10).
+		WillReturnRows(sqlmock.NewRows(deliveryRowColumns).AddRow(row...))
+	deliveries, err := repo.ListDeliveries(ctx, domain.WebhookDeliveryFilter{SubscriptionID: "a1b2c3", Status: domain.DeliveryDead, Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, 8, deliveries[0].Attempts)
+		assert.NotNil(t, deliveries[0].LastAttemptAt)
+		assert.Nil(t, deliveries[0].DeliveredAt)
+		assert.JSONEq(t, `{"id":"e1"}`, string(deliveries[0].Payload))
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(`(?i)FROM\s+webhook_deliveries\s+WHERE\s+status\s*=\s*\?\s+AND\s+nextattemptat\s*<=\s*\?\s+ORDER\s+BY\s+nextattemptat\s+LIMIT\s+\?`).
+		WithArgs(domain.DeliveryPending, now, 50).
+		WillReturnError(errors.New("connection lost"))
+	_, err = repo.ListDueDeliveries(ctx, now, 50)
+	assert.Error(t, err)
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,116 @@
+package requestHandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func writeAdjustment(w http.ResponseWriter, status int, adj *domain.FeeAdjustment) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(adj)
+}
+
+func (h *Handlers) RequestAdjustment(w http.ResponseWriter, r *http.Request) {
+	var req dto.Adjustment
+	if !h.decode(w, r, &req) {
+		return
+	}
+	adj := domain.FeeAdjustment{TicketId: req.TicketId, Amount: req.Amount, Reason: req.Reason}
+	saved, err := h.service.RequestAdjustment(r.Context(), adj)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeAdjustment(w, http.StatusCreated, saved)
+}
+
+func (h *Handlers) ApproveAdjustment(w http.ResponseWriter, r *http.Request) {
+	h.changeAdjustment(w, r, h.service.ApproveAdjustment)
+}
+
+func (h *Handlers) RejectAdjustment(w http.ResponseWriter, r *http.Request) {
+	h.changeAdjustment(w, r, h.service.RejectAdjustment)
+}
+
+// adjustmentID reads the adjustment from the {id} path variable of the
+// /api/v1 routes, or from the body of the legacy ones.
+func adjustmentID(r *http.Request) (int64, error) {
+	if _, ok := mux.Vars(r)["id"]; ok {
+		return pathInt64(r, "id")
+	}
+	var req struct {
+		AdjustmentId int64 `json:"adjustmentid"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	return req.AdjustmentId, err
+}
+
+func (h *Handlers) changeAdjustment(w http.ResponseWriter, r *http.Request, change func(context.Context, int64) (*domain.FeeAdjustment, error)) {
+	adjustmentId, err := adjustmentID(r)
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("id"))
+		return
+	}
+	adj, err := change(r.Context(), adjustmentId)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeAdjustment(w, http.StatusOK, adj)
+}
+
+func (h *Handlers) ApplyAdjustment(w http.ResponseWriter, r *http.Request) {
+	h.changeAdjustment(w, r, h.service.ApplyAdjustment)
+}
+
+func (h *Handlers) GetAdjustments(w http.ResponseWriter, r *http.Request) {
+	adjustments, err := h.service.GetAdjustments(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if adjustments == nil {
+		adjustments = []domain.FeeAdjustment{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(adjustments)
+}
+
+// parseReportTime accepts a date (2006-01-02, local time) or an RFC 3339
+// timestamp.
+func parseReportTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (h *Handlers) GetRevenueReport(w http.ResponseWriter, r *http.Request) {
+	from, err := parseReportTime(r.URL.Query().Get("from"))
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("from"))
+		return
+	}
+	to, err := parseReportTime(r.URL.Query().Get("to"))
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("to"))
+		return
+	}
+	report, err := h.service.GetRevenueReport(r.Context(), from, to)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
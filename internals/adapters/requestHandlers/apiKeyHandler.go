@@ -0,0 +1,90 @@
+package requestHandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// apiKeyWithSecret is returned when a key is created or rotated; it is the
+// only response that carries the key itself.
+type apiKeyWithSecret struct {
+	*domain.APIKey
+	Key string `json:"key"`
+}
+
+func writeAPIKey(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// apiKeyID reads the key from the {id} path variable of the /api/v1
+// routes, or from the body of the legacy ones.
+func apiKeyID(r *http.Request) (string, error) {
+	if id, ok := mux.Vars(r)["id"]; ok {
+		return id, nil
+	}
+	var req struct {
+		ID string `json:"id"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	return req.ID, err
+}
+
+func (h *UserHandlers) AddAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	key, raw, err := h.authService.CreateAPIKey(r.Context(), req.Name, req.Scopes)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeAPIKey(w, http.StatusCreated, apiKeyWithSecret{APIKey: key, Key: raw})
+}
+
+func (h *UserHandlers) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.authService.ListAPIKeys(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeAPIKey(w, http.StatusOK, keys)
+}
+
+func (h *UserHandlers) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := apiKeyID(r)
+	if err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	key, raw, err := h.authService.RotateAPIKey(r.Context(), id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeAPIKey(w, http.StatusOK, apiKeyWithSecret{APIKey: key, Key: raw})
+}
+
+func (h *UserHandlers) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := apiKeyID(r)
+	if err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	key, err := h.authService.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeAPIKey(w, http.StatusOK, key)
+}
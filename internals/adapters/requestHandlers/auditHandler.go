@@ -0,0 +1,64 @@
+package requestHandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/audit"
+	"strconv"
+	"time"
+)
+
+type AuditHandlers struct {
+	service *audit.Service
+}
+
+func NewAuditHandlers(service *audit.Service) *AuditHandlers {
+	return &AuditHandlers{service: service}
+}
+
+// GetAudit lists audit entries, newest first, filtered by the actor,
+// action, target, requestid, from, to and limit query parameters.
+func (h *AuditHandlers) GetAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.AuditFilter{
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		Target:    query.Get("target"),
+		RequestID: query.Get("requestid"),
+	}
+	var err error
+	if filter.From, err = optionalReportTime(query.Get("from")); err != nil {
+		problem.Write(w, r, problem.Invalid("from"))
+		return
+	}
+	if filter.To, err = optionalReportTime(query.Get("to")); err != nil {
+		problem.Write(w, r, problem.Invalid("to"))
+		return
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			problem.Write(w, r, problem.Invalid("limit"))
+			return
+		}
+	}
+	entries, err := h.service.Query(r.Context(), filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if entries == nil {
+		entries = []domain.AuditEntry{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entries)
+}
+
+func optionalReportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return parseReportTime(value)
+}
@@ -0,0 +1,204 @@
+package requestHandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/availability"
+	"parkingSlotManagement/internals/core/services/parking"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultHeartbeat is how often an idle availability stream is pinged.
+const DefaultHeartbeat = 15 * time.Second
+
+// Kinds of AvailabilityUpdate.
+const (
+	AvailabilitySnapshot = "snapshot"
+	AvailabilityChange   = "change"
+)
+
+var errStreamingUnsupported = errors.New("response does not support streaming")
+
+// AvailabilityUpdate is one message on an availability stream: the slot
+// counts per type, and for a change the event that caused it.
+type AvailabilityUpdate struct {
+	Kind  string                             `json:"kind"`
+	Event *domain.Event                      `json:"event,omitempty"`
+	Slots map[string]domain.SlotAvailability `json:"slots"`
+	At    time.Time                          `json:"at"`
+}
+
+// AvailabilityHandlers serve the slot counts for display boards, either
+// once or as a stream over server-sent events or a WebSocket. A stream
+// opens with a snapshot and then sends a change for every update on feed.
+type AvailabilityHandlers struct {
+	service  *parking.ParkingService
+	feed     *availability.Feed
+	upgrader websocket.Upgrader
+
+	// Heartbeat is how often an idle stream is pinged. The feed's latest
+	// counts are checked at the same time and resent if they differ from
+	// the last ones sent, in case the stream fell behind and missed one.
+	Heartbeat time.Duration
+}
+
+func NewAvailabilityHandlers(service *parking.ParkingService, feed *availability.Feed) *AvailabilityHandlers {
+	return &AvailabilityHandlers{service: service, feed: feed, Heartbeat: DefaultHeartbeat}
+}
+
+// GetAvailability answers with a snapshot of the slot counts.
+func (h *AvailabilityHandlers) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	slots, err := h.service.GetAvailability(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, AvailabilityUpdate{Kind: AvailabilitySnapshot, Slots: slots, At: time.Now()})
+}
+
+// StreamAvailability sends updates as server-sent events named after
+// their kind, with a comment line as the heartbeat.
+func (h *AvailabilityHandlers) StreamAvailability(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		problem.Write(w, r, errStreamingUnsupported)
+		return
+	}
+	sub, first, err := h.feed.Subscribe(r.Context(), 0)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(update AvailabilityUpdate) error {
+		data, err := json.Marshal(update)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", update.Kind, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	ping := func() error {
+		if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	if err := h.stream(r.Context(), sub, first, send, ping); err != nil && r.Context().Err() == nil {
+		log.Printf("availability stream ended: %v", err)
+	}
+}
+
+// WatchAvailability upgrades to a WebSocket and sends each update as a
+// JSON text message, with WebSocket pings as the heartbeat. Anything the
+// client sends is ignored.
+func (h *AvailabilityHandlers) WatchAvailability(w http.ResponseWriter, r *http.Request) {
+	sub, first, err := h.feed.Subscribe(r.Context(), 0)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already answered the client.
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		// Reading handles pongs and close frames; stop when the client goes.
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	deadline := func() time.Time { return time.Now().Add(h.heartbeat()) }
+	send := func(update AvailabilityUpdate) error {
+		conn.SetWriteDeadline(deadline())
+		return conn.WriteJSON(update)
+	}
+	ping := func() error {
+		return conn.WriteControl(websocket.PingMessage, nil, deadline())
+	}
+	if err := h.stream(ctx, sub, first, send, ping); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("availability stream ended: %v", err)
+		}
+		return
+	}
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), deadline())
+}
+
+// stream sends first as a snapshot, then a change for every update on
+// sub, until ctx is done, sub is closed or a send fails.
+func (h *AvailabilityHandlers) stream(ctx context.Context, sub *availability.Subscription, first availability.Update, send func(AvailabilityUpdate) error, ping func() error) error {
+	last := toAvailabilityUpdate(AvailabilitySnapshot, first)
+	if err := send(last); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(h.heartbeat())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			last = toAvailabilityUpdate(AvailabilityChange, update)
+			if err := send(last); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			var err error
+			if latest := h.feed.Latest(); maps.Equal(latest.Slots, last.Slots) {
+				err = ping()
+			} else {
+				last = toAvailabilityUpdate(AvailabilitySnapshot, latest)
+				last.Event = nil
+				err = send(last)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toAvailabilityUpdate(kind string, update availability.Update) AvailabilityUpdate {
+	return AvailabilityUpdate{Kind: kind, Event: update.Event, Slots: update.Slots, At: update.At}
+}
+
+func (h *AvailabilityHandlers) heartbeat() time.Duration {
+	if h.Heartbeat <= 0 {
+		return DefaultHeartbeat
+	}
+	return h.Heartbeat
+}
@@ -0,0 +1,86 @@
+package dto
+
+import "parkingSlotManagement/internals/core/domain"
+
+// CreateSlot is the body of POST /api/v1/slots.
+type CreateSlot struct {
+	SlotId   int    `json:"slotid" validate:"gte=1"`
+	SlotType string `json:"slottype" validate:"required,slottype"`
+	IsFree   bool   `json:"isfree"`
+}
+
+func (c CreateSlot) Slot() domain.Slot {
+	return domain.Slot{SlotId: c.SlotId, SlotType: c.SlotType, IsFree: c.IsFree}
+}
+
+// ParkVehicle is the body of POST /api/v1/tickets.
+type ParkVehicle struct {
+	VehicleNumber string `json:"vehiclenumber" validate:"required,plate"`
+	VehicleType   string `json:"vehicletype" validate:"required,slottype"`
+}
+
+func (p ParkVehicle) Vehicle() domain.Vehicle {
+	return domain.Vehicle{VehicleNumber: p.VehicleNumber, VehicleType: p.VehicleType}
+}
+
+// Payment is how the driver pays at exit. An empty method means cash.
+type Payment struct {
+	Method    string `json:"method"`
+	CardToken string `json:"cardtoken"`
+}
+
+func (p Payment) PaymentRequest() domain.PaymentRequest {
+	return domain.PaymentRequest{Method: p.Method, CardToken: p.CardToken}
+}
+
+// UnparkVehicle is the body of the legacy POST /UnparkVehicle.
+type UnparkVehicle struct {
+	VehicleNumber string `json:"vehiclenumber" validate:"required,plate"`
+	Payment
+}
+
+// Vehicle names a parked vehicle, as in the legacy POST /QuoteExit.
+type Vehicle struct {
+	VehicleNumber string `json:"vehiclenumber" validate:"required,plate"`
+}
+
+// ForceExit is the body of POST /api/v1/tickets/{id}/force-exit.
+type ForceExit struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// ForceUnparkVehicle is the body of the legacy POST /ForceUnparkVehicle.
+type ForceUnparkVehicle struct {
+	VehicleNumber string `json:"vehiclenumber" validate:"required,plate"`
+	ForceExit
+}
+
+// Settlement is the body of POST /api/v1/vehicles/{vehiclenumber}/settlements.
+type Settlement struct {
+	Amount domain.Money `json:"amount" validate:"gt=0"`
+	Payment
+}
+
+// SettleBalance is the body of the legacy POST /SettleBalance.
+type SettleBalance struct {
+	VehicleNumber string `json:"vehiclenumber" validate:"required,plate"`
+	Settlement
+}
+
+// VehicleListEntry is the body of POST /api/v1/vehicle-list-entries.
+type VehicleListEntry struct {
+	VehicleNumber string `json:"vehiclenumber" validate:"required,plate"`
+	ListType      string `json:"listtype" validate:"required,oneof=block allow"`
+	Reason        string `json:"reason" validate:"max=500"`
+}
+
+func (e VehicleListEntry) Entry() domain.VehicleListEntry {
+	return domain.VehicleListEntry{VehicleNumber: e.VehicleNumber, ListType: e.ListType, Reason: e.Reason}
+}
+
+// Adjustment is the body of POST /api/v1/adjustments.
+type Adjustment struct {
+	TicketId int64        `json:"ticketid" validate:"gte=1"`
+	Amount   domain.Money `json:"amount" validate:"gt=0"`
+	Reason   string       `json:"reason" validate:"required,max=500"`
+}
@@ -0,0 +1,118 @@
+// Package dto holds the request bodies the API accepts and the rules each
+// field must meet. Requests are validated here before they are turned into
+// domain values, so the domain structs carry no wire concerns.
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrInvalidRequest is matched by every Errors.
+var ErrInvalidRequest = errors.New("request failed validation")
+
+// FieldError says which field of a request broke which rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors lists every field of a request that failed validation.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	fields := make([]string, len(e))
+	for i, f := range e {
+		fields[i] = f.Field + " " + f.Message
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidRequest, strings.Join(fields, "; "))
+}
+
+func (e Errors) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// Rules are the checks that depend on how the lot is configured, which a
+// tag can't spell out.
+type Rules interface {
+	// NormaliseVehicleNumber backs the "plate" tag.
+	NormaliseVehicleNumber(raw string) (string, error)
+	// SlotTypes backs the "slottype" tag.
+	SlotTypes() []string
+}
+
+// Validator checks requests against their `validate` tags. Besides the
+// standard tags it knows "plate", a vehicle number the lot accepts, and
+// "slottype", a slot type the lot has a tariff for.
+type Validator struct {
+	validate *validator.Validate
+	rules    Rules
+}
+
+func NewValidator(rules Rules) *Validator {
+	v := &Validator{validate: validator.New(validator.WithRequiredStructEnabled()), rules: rules}
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	// Amounts are compared by their minor units, so gt=0 means positive.
+	v.validate.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		return field.Interface().(domain.Money).Amount
+	}, domain.Money{})
+	v.validate.RegisterValidation("plate", func(fl validator.FieldLevel) bool {
+		_, err := v.rules.NormaliseVehicleNumber(fl.Field().String())
+		return err == nil
+	})
+	v.validate.RegisterValidation("slottype", func(fl validator.FieldLevel) bool {
+		return slices.Contains(v.rules.SlotTypes(), fl.Field().String())
+	})
+	return v
+}
+
+// Validate returns Errors naming every field of req that breaks a rule, or
+// nil.
+func (v *Validator) Validate(req any) error {
+	err := v.validate.Struct(req)
+	var failed validator.ValidationErrors
+	if !errors.As(err, &failed) {
+		return err
+	}
+	errs := make(Errors, len(failed))
+	for i, f := range failed {
+		errs[i] = FieldError{Field: f.Field(), Rule: f.Tag(), Message: v.message(f)}
+	}
+	return errs
+}
+
+func (v *Validator) message(f validator.FieldError) string {
+	switch f.Tag() {
+	case "required":
+		return "is required"
+	case "plate":
+		_, err := v.rules.NormaliseVehicleNumber(fmt.Sprint(f.Value()))
+		return "is not valid: " + err.Error()
+	case "slottype":
+		return "must be one of " + strings.Join(v.rules.SlotTypes(), ", ")
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(f.Param(), " ", ", ")
+	case "gt":
+		return "must be greater than " + f.Param()
+	case "gte":
+		return "must be at least " + f.Param()
+	case "lte":
+		return "must be at most " + f.Param()
+	case "max":
+		return "must be at most " + f.Param() + " characters"
+	}
+	return "breaks the " + f.Tag() + " rule"
+}
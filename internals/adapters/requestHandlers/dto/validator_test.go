@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/plate"
+	"testing"
+)
+
+type lotRules struct{}
+
+func (lotRules) NormaliseVehicleNumber(raw string) (string, error) {
+	return plate.Parse(raw, plate.NewIndianValidator())
+}
+
+func (lotRules) SlotTypes() []string { return []string{"bike", "car"} }
+
+func fields(err error) map[string]string {
+	var errs Errors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	got := map[string]string{}
+	for _, f := range errs {
+		got[f.Field] = f.Rule
+	}
+	return got
+}
+
+func TestValidate(t *testing.T) {
+	v := NewValidator(lotRules{})
+	tests := []struct {
+		name string
+		req  any
+		want map[string]string
+	}{
+		{"valid vehicle", ParkVehicle{VehicleNumber: "up16 ab-1234", VehicleType: "car"}, nil},
+		{"empty vehicle", ParkVehicle{}, map[string]string{"vehiclenumber": "required", "vehicletype": "required"}},
+		{"bad plate and type", ParkVehicle{VehicleNumber: "ZZ99", VehicleType: "truck"}, map[string]string{"vehiclenumber": "plate", "vehicletype": "slottype"}},
+		{"negative slot", CreateSlot{SlotId: -3, SlotType: "bike"}, map[string]string{"slotid": "gte"}},
+		{"zero settlement", SettleBalance{VehicleNumber: "UP16AB1234", Settlement: Settlement{Amount: domain.NewMoney(0, domain.CurrencyINR)}}, map[string]string{"amount": "gt"}},
+		{"unknown list", VehicleListEntry{VehicleNumber: "UP16AB1234", ListType: "grey"}, map[string]string{"listtype": "oneof"}},
+		{"adjustment", Adjustment{TicketId: 1, Amount: domain.NewMoney(500, domain.CurrencyINR)}, map[string]string{"reason": "required"}},
+	}
+	for _, test := range tests {
+		err := v.Validate(test.req)
+		got := fields(err)
+		if len(got) != len(test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, err)
+			continue
+		}
+		for field, rule := range test.want {
+			if got[field] != rule {
+				t.Errorf("%s: expected %s to break %s, got %v", test.name, field, rule, err)
+			}
+		}
+		if test.want != nil && !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%s: expected the error to match ErrInvalidRequest", test.name)
+		}
+	}
+}
+
+func TestMessages(t *testing.T) {
+	err := NewValidator(lotRules{}).Validate(ParkVehicle{VehicleNumber: "ZZ99", VehicleType: "truck"})
+	want := `request failed validation: vehiclenumber is not valid: invalid vehicle number "ZZ99": unknown state code ZZ; vehicletype must be one of bike, car`
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected %q, got %v", want, err)
+	}
+}
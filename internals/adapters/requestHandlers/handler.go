@@ -2,33 +2,61 @@ package requestHandlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/middleware"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
 	"parkingSlotManagement/internals/core/domain"
 	"parkingSlotManagement/internals/core/services/auth"
 	"parkingSlotManagement/internals/core/services/parking"
+	"strconv"
 )
 
 type Handlers struct {
-	service *parking.ParkingService
+	service   *parking.ParkingService
+	validator *dto.Validator
 }
 
 func NewHandlers(service *parking.ParkingService) *Handlers {
 	return &Handlers{
-		service: service,
+		service:   service,
+		validator: dto.NewValidator(service),
 	}
 }
+
+// decode reads the JSON body into req, a pointer to one of the dto
+// requests, and checks it against its rules. If either fails it answers
+// the client and returns false.
+func (h *Handlers) decode(w http.ResponseWriter, r *http.Request, req any) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return false
+	}
+	if err := h.validator.Validate(req); err != nil {
+		problem.Write(w, r, err)
+		return false
+	}
+	return true
+}
 func (h *Handlers) ParkVehicleRequest(w http.ResponseWriter, r *http.Request) {
-	var vehicle domain.Vehicle
-	if err := json.NewDecoder(r.Body).Decode(&vehicle); err != nil {
-		http.Error(w, "Invalid Body Request", http.StatusInternalServerError)
+	var req dto.ParkVehicle
+	if !h.decode(w, r, &req) {
+		return
+	}
+	vehicle := req.Vehicle()
+	number, err := h.service.NormaliseVehicleNumber(vehicle.VehicleNumber)
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
-	fmt.Println(vehicle)
-	ticket, err := h.service.ParkVehicle(vehicle)
+	vehicle.VehicleNumber = number
+	ticket, err := h.service.ParkVehicle(r.Context(), vehicle)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 	w.Header().Set("content-type", "application/json")
@@ -37,36 +65,70 @@ func (h *Handlers) ParkVehicleRequest(w http.ResponseWriter, r *http.Request) {
 
 }
 func (h *Handlers) UnparkVehicleRequest(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Vehiclenumber string `json:"vehiclenumber"`
+	var req dto.UnparkVehicle
+	if !h.decode(w, r, &req) {
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid Body Request", http.StatusInternalServerError)
+	number, err := h.service.NormaliseVehicleNumber(req.VehicleNumber)
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
-	fee, err := h.service.UnparkVehicle(req.Vehiclenumber)
+	ticket, err := h.service.UnparkVehicle(r.Context(), number, req.PaymentRequest())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
-		"vehiclenumber": req.Vehiclenumber,
-		"fee":           math.Round(fee*100) / 100,
-		"message":       "Successfully Unpark The Vehicle",
+		"vehiclenumber":    number,
+		"fee":              ticket.Fee,
+		"netfee":           ticket.NetFee,
+		"tax":              ticket.Tax,
+		"taxlines":         ticket.TaxLines,
+		"paymentreference": ticket.PaymentReference,
+		"message":          "Successfully Unpark The Vehicle",
 	})
 
 }
+func (h *Handlers) QuoteExitRequest(w http.ResponseWriter, r *http.Request) {
+	var req dto.Vehicle
+	if !h.decode(w, r, &req) {
+		return
+	}
+	quote, err := h.service.QuoteExit(r.Context(), req.VehicleNumber)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quote)
+}
+func (h *Handlers) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	ticketId, err := strconv.ParseInt(r.URL.Query().Get("ticketid"), 10, 64)
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("ticketid"))
+		return
+	}
+	receipt, err := h.service.GetReceipt(r.Context(), ticketId)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(receipt)
+}
 func (h *Handlers) AddSlot(w http.ResponseWriter, r *http.Request) {
-	var Slot domain.Slot
-	if err := json.NewDecoder(r.Body).Decode(&Slot); err != nil {
-		http.Error(w, "Invalid Body Request", http.StatusBadRequest)
+	var req dto.CreateSlot
+	if !h.decode(w, r, &req) {
 		return
 	}
-	err := h.service.AddSlot(Slot)
+	err := h.service.AddSlot(r.Context(), req.Slot())
 	if err != nil {
-		http.Error(w, "Unableto add slot", http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -75,18 +137,16 @@ func (h *Handlers) AddSlot(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handlers) GetAvailableSlots(w http.ResponseWriter, r *http.Request) {
-	slots, err := h.service.GetAvailableSlots()
+	slots, err := h.service.GetAvailableSlots(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	if err := json.NewEncoder(w).Encode(slots); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-	}
+	json.NewEncoder(w).Encode(slots)
 
 }
 
@@ -96,14 +156,111 @@ func LoginHandler(authService auth.AuthService) http.HandlerFunc {
 			Username string `json:"username"`
 			Password string `json:"password"`
 		}
-		json.NewDecoder(r.Body).Decode(&creds)
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+			problem.Write(w, r, problem.ErrInvalidBody)
+			return
+		}
 
-		token, err := authService.Login(creds.Username, creds.Password)
+		ctx := domain.WithClientAddress(r.Context(), middleware.ClientAddress(r))
+		tokens, err := authService.Login(ctx, creds.Username, creds.Password)
+		var throttled *auth.ThrottledError
+		if errors.As(err, &throttled) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			problem.Write(w, r, err)
+			return
+		}
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			problem.Write(w, r, problem.As(problem.ErrUnauthorized, err))
+			return
+		}
 		if err != nil {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			problem.Write(w, r, err)
 			return
 		}
 
-		json.NewEncoder(w).Encode(map[string]string{"token": token})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tokens)
+	}
+}
+
+// JWKSHandler publishes the public keys tokens are signed with, so other
+// services can verify them.
+func JWKSHandler(authService auth.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "max-age=300")
+		json.NewEncoder(w).Encode(authService.JWKS())
+	}
+}
+
+// RefreshHandler exchanges a refresh token for a new token pair.
+func RefreshHandler(authService auth.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RefreshToken string `json:"refreshtoken"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.Write(w, r, problem.ErrInvalidBody)
+			return
+		}
+
+		tokens, err := authService.Refresh(r.Context(), req.RefreshToken)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tokens)
+	}
+}
+
+// StreamTokenHandler issues the caller a short-lived token to open an
+// availability stream from a browser with.
+func StreamTokenHandler(authService auth.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := domain.UserFromContext(r.Context())
+		if !ok {
+			problem.Write(w, r, problem.ErrUnauthorized)
+			return
+		}
+
+		token, err := authService.IssueStreamToken(r.Context(), *user)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(token)
+	}
+}
+
+// LogoutHandler revokes the caller's access token and, if the body carries
+// one, their refresh token.
+func LogoutHandler(authService auth.AuthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RefreshToken string `json:"refreshtoken"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			problem.Write(w, r, problem.ErrInvalidBody)
+			return
+		}
+		token, _ := middleware.BearerToken(r)
+
+		err := authService.Logout(r.Context(), token, req.RefreshToken)
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) {
+			problem.Write(w, r, problem.As(problem.ErrInvalidParameter, fmt.Errorf("refreshtoken: %w", err)))
+			return
+		}
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Logged out successfully"))
 	}
 }
@@ -1,20 +1,41 @@
 package requestHandlers
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"parkingSlotManagement/internals/adapters/payments"
 	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/adapters/requestHandlers/middleware"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
 	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/audit"
 	"parkingSlotManagement/internals/core/services/auth"
+	"parkingSlotManagement/internals/core/services/availability"
+	"parkingSlotManagement/internals/core/services/events"
+	"parkingSlotManagement/internals/core/services/outbox"
 	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/core/services/webhook"
+	"parkingSlotManagement/internals/ports"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"golang.org/x/crypto/bcrypt"
 )
 
+var ctx = context.Background()
+
 func TestAddSlot(t *testing.T) {
 	slotRepo := inmemmory.NewSlotInMemmory()
 	ticketRepo := inmemmory.NewTicketInMemmory()
@@ -57,11 +78,24 @@ func TestAddSlot(t *testing.T) {
 		t.Errorf("Invalid JSON: Expected status 400 Bad Request, got %d", resp2.Code)
 	}
 
-	expected2 := "Invalid Body Request"
+	expected2 := `"code":"invalid_body"`
 	if !strings.Contains(resp2.Body.String(), expected2) {
 		t.Errorf("Invalid JSON: Expected error message %q, got %q", expected2, resp2.Body.String())
 	}
 
+	req3 := httptest.NewRequest(http.MethodPost, "/AddSlot", bytes.NewReader(body))
+	req3.Header.Set("Content-Type", "application/json")
+	resp3 := httptest.NewRecorder()
+
+	h.AddSlot(resp3, req3)
+
+	if resp3.Code != http.StatusConflict {
+		t.Errorf("Duplicate slot: Expected status 409 Conflict, got %d", resp3.Code)
+	}
+	expected3 := `"code":"slot_exists"`
+	if !strings.Contains(resp3.Body.String(), expected3) {
+		t.Errorf("Duplicate slot: Expected error message %q, got %q", expected3, resp3.Body.String())
+	}
 }
 
 func TestGetAvailableSlot(t *testing.T) {
@@ -81,7 +115,7 @@ func TestGetAvailableSlot(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to marshal slot: %v", err)
 	}
-	slotRepo.SaveSlot(Slot)
+	slotRepo.SaveSlot(ctx, Slot)
 	req := httptest.NewRequest(http.MethodPost, "/GetAvailableSlot", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
@@ -109,7 +143,7 @@ func TestParkVehicleRequest(t *testing.T) {
 		SlotType: "car",
 		IsFree:   true,
 	}
-	slotRepo.SaveSlot(slot)
+	slotRepo.SaveSlot(ctx, slot)
 
 	vehicle := domain.Vehicle{
 		VehicleNumber: "UP16AB1234",
@@ -139,16 +173,16 @@ func TestParkVehicleRequest(t *testing.T) {
 
 	h.ParkVehicleRequest(resp2, req2)
 
-	if resp2.Code != http.StatusInternalServerError {
-		t.Errorf("Expected status 500 Internal Server Error for invalid JSON, got %d", resp2.Code)
+	if resp2.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 Bad Request for invalid JSON, got %d", resp2.Code)
 	}
 
-	if !strings.Contains(resp2.Body.String(), "Invalid Body Request") {
-		t.Errorf("Expected error message 'Invalid Body Request', got %q", resp2.Body.String())
+	if !strings.Contains(resp2.Body.String(), `"code":"invalid_body"`) {
+		t.Errorf("Expected error message invalid_body code, got %q", resp2.Body.String())
 	}
 
 	failVehicle := domain.Vehicle{
-		VehicleNumber: "FAIL123",
+		VehicleNumber: "UP16AB9999",
 		VehicleType:   "car",
 	}
 	body3 := new(bytes.Buffer)
@@ -160,8 +194,8 @@ func TestParkVehicleRequest(t *testing.T) {
 
 	h.ParkVehicleRequest(resp3, req3)
 
-	if resp3.Code != http.StatusInternalServerError {
-		t.Errorf("Expected status 500 Internal Server Error for service error, got %d", resp3.Code)
+	if resp3.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 Conflict when no slot is free, got %d", resp3.Code)
 	}
 
 	if !strings.Contains(strings.TrimSpace(resp3.Body.String()), "failed to fetch slots by type") {
@@ -174,6 +208,7 @@ func TestUnparkVehicleRequest(t *testing.T) {
 	slotRepo := inmemmory.NewSlotInMemmory()
 	ticketRepo := inmemmory.NewTicketInMemmory()
 	service := parking.NewParkingService(slotRepo, ticketRepo)
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCash: payments.NewCashGateway()}
 	h := NewHandlers(service)
 
 	// Step 1: Save a slot
@@ -182,7 +217,7 @@ func TestUnparkVehicleRequest(t *testing.T) {
 		SlotType: "car",
 		IsFree:   false,
 	}
-	slotRepo.SaveSlot(slot)
+	slotRepo.SaveSlot(ctx, slot)
 
 	// Step 2: Save a ticket
 	ticket := domain.Ticket{
@@ -191,7 +226,7 @@ func TestUnparkVehicleRequest(t *testing.T) {
 		SlotId:        1,
 		EntryTime:     time.Now().Add(-2 * time.Hour),
 	}
-	ticketRepo.SaveTicket(ticket)
+	ticketRepo.SaveTicket(ctx, ticket)
 
 	// Step 3: Create request to unpark
 	body := `{"vehiclenumber":"UP16AB1234"}`
@@ -216,6 +251,103 @@ func TestUnparkVehicleRequest(t *testing.T) {
 	if response["message"] != "Successfully Unpark The Vehicle" {
 		t.Errorf("Expected success message, got '%v'", response["message"])
 	}
+	if response["paymentreference"] == "" {
+		t.Errorf("Expected a payment reference, got empty string")
+	}
+	fee, ok := response["fee"].(map[string]interface{})
+	if !ok || fee["currency"] != "INR" || fee["amount"] == "" {
+		t.Errorf("Expected fee as an amount with currency, got %v", response["fee"])
+	}
+}
+
+func TestGetReceipt(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	service := parking.NewParkingService(slotRepo, ticketRepo)
+	h := NewHandlers(service)
+
+	exitTime := time.Now()
+	ticketRepo.SaveTicket(ctx, domain.Ticket{TicketId: 1, VehicleNumber: "UP16AB1234", SlotId: 1, EntryTime: exitTime.Add(-time.Hour)})
+	ticketRepo.CloseTicket(ctx, domain.Ticket{
+		TicketId: 1, VehicleNumber: "UP16AB1234", SlotId: 1, EntryTime: exitTime.Add(-time.Hour), ExitTime: &exitTime,
+		Fee: domain.NewMoney(7080, domain.CurrencyINR), NetFee: domain.NewMoney(6000, domain.CurrencyINR), Tax: domain.NewMoney(1080, domain.CurrencyINR),
+		TaxLines: []domain.TaxLine{
+			{Name: "CGST", Rate: 0.09, Amount: domain.NewMoney(540, domain.CurrencyINR)},
+			{Name: "SGST", Rate: 0.09, Amount: domain.NewMoney(540, domain.CurrencyINR)},
+		},
+	})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{TicketId: 2, VehicleNumber: "DL3CAF0001", SlotId: 2, EntryTime: exitTime})
+
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{"ticketid=1", http.StatusOK},
+		{"ticketid=2", http.StatusConflict},
+		{"ticketid=3", http.StatusNotFound},
+		{"ticketid=abc", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/GetReceipt?"+tt.query, nil)
+		resp := httptest.NewRecorder()
+		h.GetReceipt(resp, req)
+		if resp.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.query, tt.status, resp.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/GetReceipt?ticketid=1", nil)
+	resp := httptest.NewRecorder()
+	h.GetReceipt(resp, req)
+	var receipt domain.Receipt
+	if err := json.NewDecoder(resp.Body).Decode(&receipt); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if receipt.Total != domain.NewMoney(7080, domain.CurrencyINR) || len(receipt.TaxLines) != 2 {
+		t.Errorf("Expected total 70.8 with two tax lines, got %+v", receipt)
+	}
+}
+
+func TestUnparkVehicleRequest_PaymentDeclined(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	service := parking.NewParkingService(slotRepo, ticketRepo)
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCard: payments.NewFakeCardGateway()}
+	h := NewHandlers(service)
+
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: false})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{
+		TicketId:      123456789,
+		VehicleNumber: "UP16AB1234",
+		SlotId:        1,
+		EntryTime:     time.Now().Add(-2 * time.Hour),
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/QuoteExit", strings.NewReader(`{"vehiclenumber":"UP16AB1234"}`))
+	resp := httptest.NewRecorder()
+	h.QuoteExitRequest(resp, req)
+	var quote domain.ExitQuote
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		t.Fatalf("Failed to decode quote: %v", err)
+	}
+	if !quote.Fee.IsPositive() {
+		t.Errorf("Expected a positive fee, got %v", quote.Fee)
+	}
+
+	body := `{"vehiclenumber":"UP16AB1234","method":"card","cardtoken":"` + payments.FakeCardDeclineToken + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/UnparkVehicle", strings.NewReader(body))
+	resp = httptest.NewRecorder()
+	h.UnparkVehicleRequest(resp, req)
+	if resp.Code != http.StatusPaymentRequired {
+		t.Errorf("Expected status 402 Payment Required, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/UnparkVehicle", strings.NewReader(`{"vehiclenumber":"UP16AB1234","method":"cheque"}`))
+	resp = httptest.NewRecorder()
+	h.UnparkVehicleRequest(resp, req)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity for unknown method, got %d", resp.Code)
+	}
 }
 func TestUnparkVehicleRequest_InvalidVehicle(t *testing.T) {
 	slotRepo := inmemmory.NewSlotInMemmory()
@@ -223,15 +355,15 @@ func TestUnparkVehicleRequest_InvalidVehicle(t *testing.T) {
 	service := parking.NewParkingService(slotRepo, ticketRepo)
 	h := NewHandlers(service)
 
-	body := `{"vehiclenumber":"NOTFOUND123"}`
+	body := `{"vehiclenumber":"DL3CAF0001"}`
 	req := httptest.NewRequest(http.MethodPost, "/UnparkVehicle", strings.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	resp := httptest.NewRecorder()
 
 	h.UnparkVehicleRequest(resp, req)
 
-	if resp.Code != http.StatusInternalServerError {
-		t.Errorf("Expected status 500 Internal Server Error, got %d", resp.Code)
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 Not Found, got %d", resp.Code)
 	}
 
 	if !strings.Contains(resp.Body.String(), "ticket of this vehicle number not found") {
@@ -239,13 +371,282 @@ func TestUnparkVehicleRequest_InvalidVehicle(t *testing.T) {
 	}
 }
 
-func TestLoginHandler(t *testing.T) {
-	// Set env variables manually for testing
-	os.Setenv("ADMIN_USERNAME", "admin")
-	os.Setenv("ADMIN_PASSWORD", "admin123")
-	os.Setenv("JWT_SECRET", "testsecret")
+func TestParkVehicleRequest_InvalidVehicleNumber(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	service := parking.NewParkingService(slotRepo, ticketRepo)
+	h := NewHandlers(service)
+
+	body := `{"vehiclenumber":"not a plate","vehicletype":"car"}`
+	req := httptest.NewRequest(http.MethodPost, "/ParkVehicle", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	h.ParkVehicleRequest(resp, req)
+
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity, got %d", resp.Code)
+	}
+
+	if !strings.Contains(resp.Body.String(), "invalid vehicle number") {
+		t.Errorf("Expected invalid vehicle number message, got '%s'", resp.Body.String())
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	service := parking.NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	h := NewHandlers(service)
+
+	req := httptest.NewRequest(http.MethodPost, "/AddSlot", strings.NewReader(`{"slotid":-1,"slottype":"truck"}`))
+	resp := httptest.NewRecorder()
+	h.AddSlot(resp, req)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422 Unprocessable Entity, got %d", resp.Code)
+	}
+	var p problem.Problem
+	json.NewDecoder(resp.Body).Decode(&p)
+	if p.Code != "validation_failed" || len(p.Errors) != 2 {
+		t.Fatalf("Expected two field errors, got %+v", p)
+	}
+	if p.Errors[0].Field != "slotid" || p.Errors[1].Field != "slottype" || p.Errors[1].Message != "must be one of bike, car" {
+		t.Errorf("Expected slotid and slottype to be reported, got %+v", p.Errors)
+	}
+	if slots, _ := service.GetSlots(context.Background()); len(slots) != 0 {
+		t.Errorf("Expected the invalid slot not to be saved, got %+v", slots)
+	}
+
+	service.Lot.HourlyRates["truck"] = domain.NewMoney(9000, domain.CurrencyINR)
+	req = httptest.NewRequest(http.MethodPost, "/AddSlot", strings.NewReader(`{"slotid":7,"slottype":"truck","isfree":true}`))
+	resp = httptest.NewRecorder()
+	h.AddSlot(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Errorf("Expected a slot type with a tariff to be accepted, got %d %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestVehicleListRequests(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	service := parking.NewParkingService(slotRepo, ticketRepo)
+	service.VehicleListRepo = inmemmory.NewVehicleListInMemmory()
+	h := NewHandlers(service)
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true})
+
+	body := `{"vehiclenumber":"up16ab1234","listtype":"block","reason":"banned"}`
+	req := httptest.NewRequest(http.MethodPost, "/AddVehicleListEntry", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	h.AddVehicleListEntry(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Errorf("Expected status 201 Created, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/AddVehicleListEntry", strings.NewReader(`{"vehiclenumber":"UP16AB1234","listtype":"grey"}`))
+	resp = httptest.NewRecorder()
+	h.AddVehicleListEntry(resp, req)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity for unknown list type, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/ParkVehicle", strings.NewReader(`{"vehiclenumber":"UP16AB1234","vehicletype":"car"}`))
+	resp = httptest.NewRecorder()
+	h.ParkVehicleRequest(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden for blocklisted vehicle, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/GetVehicleList?listtype=block", nil)
+	resp = httptest.NewRecorder()
+	h.GetVehicleList(resp, req)
+	var entries []domain.VehicleListEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(entries) != 1 || entries[0].VehicleNumber != "UP16AB1234" {
+		t.Errorf("Expected one blocklisted vehicle, got %v", entries)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/GetEntryRejections", nil)
+	resp = httptest.NewRecorder()
+	h.GetEntryRejections(resp, req)
+	var rejections []domain.EntryRejection
+	if err := json.NewDecoder(resp.Body).Decode(&rejections); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(rejections) != 1 {
+		t.Errorf("Expected one rejection, got %d", len(rejections))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/RemoveVehicleListEntry", strings.NewReader(`{"vehiclenumber":"UP16AB1234"}`))
+	resp = httptest.NewRecorder()
+	h.RemoveVehicleListEntry(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/RemoveVehicleListEntry", strings.NewReader(`{"vehiclenumber":"UP16AB1234"}`))
+	resp = httptest.NewRecorder()
+	h.RemoveVehicleListEntry(resp, req)
+	if resp.Code != http.StatusNotFound || !strings.Contains(resp.Body.String(), `"code":"vehicle_list_entry_not_found"`) {
+		t.Errorf("Expected status 404 vehicle_list_entry_not_found removing it again, got %d %s", resp.Code, resp.Body.String())
+	}
+}
+
+func TestUnpaidBalanceRequests(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	service := parking.NewParkingService(slotRepo, ticketRepo)
+	service.LedgerRepo = inmemmory.NewLedgerInMemmory()
+	service.MaxUnpaidBalance = domain.NewMoney(5000, domain.CurrencyINR)
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCash: payments.NewCashGateway()}
+	h := NewHandlers(service)
+
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: false})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{
+		TicketId:      123456789,
+		VehicleNumber: "UP16AB1234",
+		SlotId:        1,
+		EntryTime:     time.Now().Add(-2 * time.Hour),
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/ForceUnparkVehicle", strings.NewReader(`{"vehiclenumber":"UP16AB1234","reason":"payment failure"}`))
+	resp := httptest.NewRecorder()
+	h.ForceUnparkVehicleRequest(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK, got %d", resp.Code)
+	}
 
-	authService := auth.NewAuthService()
+	req = httptest.NewRequest(http.MethodPost, "/ParkVehicle", strings.NewReader(`{"vehiclenumber":"UP16AB1234","vehicletype":"car"}`))
+	resp = httptest.NewRecorder()
+	h.ParkVehicleRequest(resp, req)
+	if resp.Code != http.StatusPaymentRequired {
+		t.Errorf("Expected status 402 Payment Required, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/GetUnpaidBalance?vehiclenumber=UP16AB1234", nil)
+	resp = httptest.NewRecorder()
+	h.GetUnpaidBalance(resp, req)
+	var account struct {
+		Balance domain.Money         `json:"balance"`
+		Entries []domain.LedgerEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&account); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if account.Balance.Amount < 11900 || len(account.Entries) != 1 {
+		t.Errorf("Expected one unpaid entry of about 120, got %v", account)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/SettleBalance", strings.NewReader(`{"vehiclenumber":"UP16AB1234","amount":1000}`))
+	resp = httptest.NewRecorder()
+	h.SettleBalanceRequest(resp, req)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity for overpayment, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/SettleBalance", strings.NewReader(`{"vehiclenumber":"UP16AB1234","amount":100}`))
+	resp = httptest.NewRecorder()
+	h.SettleBalanceRequest(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK, got %d", resp.Code)
+	}
+}
+
+func TestAdjustmentRequests(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	service := parking.NewParkingService(slotRepo, ticketRepo)
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCash: payments.NewCashGateway()}
+	service.AdjustmentRepo = inmemmory.NewAdjustmentInMemmory()
+	h := NewHandlers(service)
+
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: false})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{
+		TicketId:      123456789,
+		VehicleNumber: "UP16AB1234",
+		SlotId:        1,
+		EntryTime:     time.Now().Add(-2 * time.Hour),
+	})
+	if _, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{}); err != nil {
+		t.Fatalf("Failed to unpark: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/RequestAdjustment", strings.NewReader(`{"ticketid":123456789,"amount":20,"reason":"overcharged"}`))
+	resp := httptest.NewRecorder()
+	h.RequestAdjustment(resp, req)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 Created, got %d", resp.Code)
+	}
+	var adj domain.FeeAdjustment
+	json.NewDecoder(resp.Body).Decode(&adj)
+
+	approve := `{"adjustmentid":` + strconv.FormatInt(adj.AdjustmentId, 10) + `}`
+	req = httptest.NewRequest(http.MethodPost, "/ApproveAdjustment", strings.NewReader(approve))
+	resp = httptest.NewRecorder()
+	h.ApproveAdjustment(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden without a reviewer, got %d", resp.Code)
+	}
+
+	attendant := &domain.User{Username: "att", Role: domain.RoleAttendant}
+	req = httptest.NewRequest(http.MethodPost, "/ApproveAdjustment", strings.NewReader(approve))
+	req = req.WithContext(domain.WithUser(req.Context(), attendant))
+	resp = httptest.NewRecorder()
+	h.ApproveAdjustment(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden for attendant, got %d", resp.Code)
+	}
+
+	supervisor := &domain.User{Username: "sup", Role: domain.RoleSupervisor}
+	req = httptest.NewRequest(http.MethodPost, "/ApproveAdjustment", strings.NewReader(approve))
+	req = req.WithContext(domain.WithUser(req.Context(), supervisor))
+	resp = httptest.NewRecorder()
+	h.ApproveAdjustment(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK for supervisor, got %d", resp.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/ApplyAdjustment", strings.NewReader(approve))
+	resp = httptest.NewRecorder()
+	h.ApplyAdjustment(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK, got %d", resp.Code)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	req = httptest.NewRequest(http.MethodGet, "/GetRevenueReport?from="+today+"&to="+tomorrow, nil)
+	resp = httptest.NewRecorder()
+	h.GetRevenueReport(resp, req)
+	var report domain.RevenueReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if report.Adjustments != domain.NewMoney(2000, domain.CurrencyINR) || report.TicketCount != 1 {
+		t.Errorf("Expected one ticket and 20 of adjustments, got %+v", report)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/GetRevenueReport?from=yesterday", nil)
+	resp = httptest.NewRecorder()
+	h.GetRevenueReport(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 Bad Request for invalid date, got %d", resp.Code)
+	}
+}
+
+// newAuthService returns an auth service whose only user is the admin
+// "admin" with password "admin123".
+func newAuthService(t *testing.T) *auth.AuthServiceImpl {
+	os.Setenv("JWT_SECRET", "testsecret-testsecret-testsecret")
+	authService := auth.NewAuthService(inmemmory.NewUserInMemmory(), inmemmory.NewRevocationInMemmory())
+	authService.HashCost = bcrypt.MinCost
+	if _, err := authService.Bootstrap(ctx, "admin", "admin123"); err != nil {
+		t.Fatalf("Bootstrap failed: %v", err)
+	}
+	return authService
+}
+
+func TestLoginHandler(t *testing.T) {
+	authService := newAuthService(t)
 	handler := LoginHandler(authService)
 
 	t.Run("Valid credentials", func(t *testing.T) {
@@ -297,3 +698,594 @@ func TestLoginHandler(t *testing.T) {
 		}
 	})
 }
+
+func TestLoginHandler_Throttling(t *testing.T) {
+	authService := newAuthService(t)
+	authService.LoginAttempts = inmemmory.NewLoginAttemptInMemmory()
+	authService.LoginBackoff = 0
+	authService.MaxLoginFailures = 2
+	handler := LoginHandler(authService)
+	users := NewUserHandlers(authService)
+	tokens, _ := authService.Login(ctx, "admin", "admin123")
+
+	login := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	if resp := login(`{"username":`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 Bad Request for a malformed body, got %d", resp.Code)
+	}
+	login(`{"username":"admin","password":"wrong"}`)
+	login(`{"username":"admin","password":"wrong"}`)
+	resp := login(`{"username":"admin","password":"admin123"}`)
+	if resp.Code != http.StatusTooManyRequests || resp.Header().Get("Retry-After") == "" {
+		t.Fatalf("Expected status 429 with Retry-After once locked, got %d %v", resp.Code, resp.Header())
+	}
+
+	getLockouts := middleware.AuthMiddleware(users.GetLockouts, authService, domain.PermUsersManage)
+	unlock := middleware.AuthMiddleware(users.UnlockAccount, authService, domain.PermUsersManage)
+	call := func(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+	var lockouts []domain.LoginAttempts
+	json.NewDecoder(call(getLockouts, "").Body).Decode(&lockouts)
+	if len(lockouts) != 1 || lockouts[0].Key != "user:admin" {
+		t.Errorf("Expected the admin to be locked out, got %+v", lockouts)
+	}
+	if resp := call(unlock, `{"username":"admin","address":"10.0.0.7"}`); resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 Bad Request for both username and address, got %d", resp.Code)
+	}
+	if resp := call(unlock, `{"username":"admin"}`); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK unlocking, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if resp := call(unlock, `{"username":"admin"}`); resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 Not Found unlocking twice, got %d", resp.Code)
+	}
+	if resp := login(`{"username":"admin","password":"admin123"}`); resp.Code != http.StatusOK {
+		t.Errorf("Expected login to work after unlocking, got %d", resp.Code)
+	}
+}
+
+func TestUserHandlersAndPermissions(t *testing.T) {
+	authService := newAuthService(t)
+	users := NewUserHandlers(authService)
+	addUser := middleware.AuthMiddleware(users.AddUser, authService, domain.PermUsersManage)
+
+	login := func(username, password string) string {
+		tokens, err := authService.Login(ctx, username, password)
+		if err != nil {
+			t.Fatalf("Login failed for %s: %v", username, err)
+		}
+		return "Bearer " + tokens.AccessToken
+	}
+	call := func(handler http.HandlerFunc, token string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+		req.Header.Set("Authorization", token)
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+
+	adminToken := login("admin", "admin123")
+	resp := call(addUser, adminToken, map[string]string{"username": "ravi", "password": "ravi-pass", "role": "attendant"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 Created, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created domain.User
+	json.NewDecoder(resp.Body).Decode(&created)
+	if created.ID == "" || created.Role != domain.RoleAttendant {
+		t.Errorf("Expected a new attendant, got %+v", created)
+	}
+
+	resp = call(addUser, adminToken, map[string]string{"username": "ravi", "password": "ravi-pass", "role": "attendant"})
+	if resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 Conflict for a duplicate username, got %d", resp.Code)
+	}
+	resp = call(addUser, adminToken, map[string]string{"username": "meena", "password": "ravi-pass", "role": "owner"})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity for an unknown role, got %d", resp.Code)
+	}
+
+	attendantToken := login("ravi", "ravi-pass")
+	resp = call(addUser, attendantToken, map[string]string{"username": "meena", "password": "ravi-pass", "role": "admin"})
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden for an attendant managing users, got %d", resp.Code)
+	}
+	resp = call(addUser, "Bearer not-a-token", map[string]string{})
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 Unauthorized for a bad token, got %d", resp.Code)
+	}
+
+	updateUser := middleware.AuthMiddleware(users.UpdateUser, authService, domain.PermUsersManage)
+	resp = call(updateUser, adminToken, map[string]any{"id": created.ID, "role": "auditor"})
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK updating a role, got %d", resp.Code)
+	}
+	resp = call(updateUser, adminToken, map[string]any{"id": "missing", "role": "auditor"})
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 Not Found for an unknown user, got %d", resp.Code)
+	}
+
+	deleteUser := middleware.AuthMiddleware(users.DeleteUser, authService, domain.PermUsersManage)
+	resp = call(deleteUser, adminToken, map[string]string{"id": created.ID})
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK deleting a user, got %d", resp.Code)
+	}
+
+	getUsers := middleware.AuthMiddleware(users.GetUsers, authService, domain.PermUsersManage)
+	req := httptest.NewRequest(http.MethodGet, "/GetUsers", nil)
+	req.Header.Set("Authorization", adminToken)
+	listResp := httptest.NewRecorder()
+	getUsers(listResp, req)
+	var listed []domain.User
+	json.NewDecoder(listResp.Body).Decode(&listed)
+	if len(listed) != 1 || listed[0].Username != "admin" {
+		t.Errorf("Expected only the admin to remain, got %+v", listed)
+	}
+	if strings.Contains(listResp.Body.String(), "admin123") {
+		t.Errorf("Expected passwords to be left out of the user list")
+	}
+}
+
+func TestPasswordHandlers(t *testing.T) {
+	authService := newAuthService(t)
+	users := NewUserHandlers(authService)
+	created, _ := authService.CreateUser(ctx, domain.User{Username: "ravi", Role: domain.RoleAttendant}, "ravi-pass")
+	tokens, _ := authService.Login(ctx, "ravi", "ravi-pass")
+	token := tokens.AccessToken
+
+	call := func(handler http.HandlerFunc, token string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+
+	changePassword := middleware.AuthMiddleware(users.ChangePassword, authService, domain.PermOwnAccount)
+	resp := call(changePassword, token, map[string]string{"currentpassword": "wrong-pass", "newpassword": "ravi-new-pass"})
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden for a wrong current password, got %d", resp.Code)
+	}
+	resp = call(changePassword, token, map[string]string{"currentpassword": "ravi-pass", "newpassword": "short"})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity for a short password, got %d", resp.Code)
+	}
+	resp = call(changePassword, token, map[string]string{"currentpassword": "ravi-pass", "newpassword": "ravi-new-pass"})
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK, got %d: %s", resp.Code, resp.Body.String())
+	}
+	resp = call(changePassword, token, map[string]string{"currentpassword": "ravi-new-pass", "newpassword": "ravi-newer-pass"})
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 Unauthorized for a token issued before the change, got %d", resp.Code)
+	}
+	tokens, _ = authService.Login(ctx, "ravi", "ravi-new-pass")
+	token = tokens.AccessToken
+
+	resetPassword := middleware.AuthMiddleware(users.ResetPassword, authService, domain.PermUsersManage)
+	resp = call(resetPassword, token, map[string]string{"id": created.ID, "newpassword": "reset-pass"})
+	if resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden for an attendant resetting passwords, got %d", resp.Code)
+	}
+	adminTokens, _ := authService.Login(ctx, "admin", "admin123")
+	resp = call(resetPassword, adminTokens.AccessToken, map[string]string{"id": created.ID, "newpassword": "reset-pass"})
+	if resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if _, err := authService.Login(ctx, "ravi", "reset-pass"); err != nil {
+		t.Errorf("Expected the reset password to work, got %v", err)
+	}
+}
+
+func TestRefreshAndLogoutHandlers(t *testing.T) {
+	authService := newAuthService(t)
+	tokens, _ := authService.Login(ctx, "admin", "admin123")
+
+	post := func(handler http.HandlerFunc, token string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+
+	refresh := RefreshHandler(authService)
+	resp := post(refresh, "", map[string]string{"refreshtoken": tokens.RefreshToken})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 OK, got %d", resp.Code)
+	}
+	var refreshed domain.TokenPair
+	json.NewDecoder(resp.Body).Decode(&refreshed)
+	if refreshed.AccessToken == "" || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Errorf("Expected a new token pair, got %+v", refreshed)
+	}
+	resp = post(refresh, "", map[string]string{"refreshtoken": tokens.RefreshToken})
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 Unauthorized reusing a refresh token, got %d", resp.Code)
+	}
+
+	logout := middleware.AuthMiddleware(LogoutHandler(authService), authService, domain.PermOwnAccount)
+	resp = post(logout, refreshed.AccessToken, map[string]string{"refreshtoken": refreshed.RefreshToken})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 OK, got %d: %s", resp.Code, resp.Body.String())
+	}
+	resp = post(logout, refreshed.AccessToken, map[string]string{})
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 Unauthorized with a revoked token, got %d", resp.Code)
+	}
+	resp = post(refresh, "", map[string]string{"refreshtoken": refreshed.RefreshToken})
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 Unauthorized after logout, got %d", resp.Code)
+	}
+}
+
+func TestAPIKeyHandlersAndMiddleware(t *testing.T) {
+	authService := newAuthService(t)
+	authService.APIKeys = inmemmory.NewAPIKeyInMemmory()
+	users := NewUserHandlers(authService)
+	tokens, _ := authService.Login(ctx, "admin", "admin123")
+
+	call := func(handler http.HandlerFunc, header, value string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+		req.Header.Set(header, value)
+		resp := httptest.NewRecorder()
+		handler(resp, req)
+		return resp
+	}
+	adminToken := "Bearer " + tokens.AccessToken
+
+	addKey := middleware.AuthMiddleware(users.AddAPIKey, authService, domain.PermAPIKeysManage)
+	resp := call(addKey, "Authorization", adminToken, map[string]any{"name": "gate-1", "scopes": []string{"park-only"}})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 Created, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		ID     string              `json:"id"`
+		Key    string              `json:"key"`
+		Scopes []domain.Permission `json:"scopes"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	if created.Key == "" || len(created.Scopes) != 1 {
+		t.Fatalf("Expected the key and its scopes, got %+v", created)
+	}
+	resp = call(addKey, "Authorization", adminToken, map[string]any{"name": "gate-2", "scopes": []string{"everything"}})
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 Unprocessable Entity for an unknown scope, got %d", resp.Code)
+	}
+
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(domain.Actor(r.Context())))
+	}
+	park := middleware.AuthMiddleware(echo, authService, domain.PermParkingOperate)
+	resp = call(park, middleware.APIKeyHeader, created.Key, nil)
+	if resp.Code != http.StatusOK || resp.Body.String() != "apikey:gate-1" {
+		t.Errorf("Expected the key to park as apikey:gate-1, got %d %q", resp.Code, resp.Body.String())
+	}
+	reports := middleware.AuthMiddleware(echo, authService, domain.PermReportsRead)
+	if resp = call(reports, middleware.APIKeyHeader, created.Key, nil); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden outside the key's scopes, got %d", resp.Code)
+	}
+	if resp = call(addKey, middleware.APIKeyHeader, created.Key, map[string]any{}); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden for a key managing keys, got %d", resp.Code)
+	}
+	if resp = call(park, middleware.APIKeyHeader, "pk_nope_nope", nil); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 Unauthorized for an unknown key, got %d", resp.Code)
+	}
+
+	revokeKey := middleware.AuthMiddleware(users.RevokeAPIKey, authService, domain.PermAPIKeysManage)
+	if resp = call(revokeKey, "Authorization", adminToken, map[string]string{"id": created.ID}); resp.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK revoking a key, got %d", resp.Code)
+	}
+	if resp = call(park, middleware.APIKeyHeader, created.Key, nil); resp.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 Unauthorized for a revoked key, got %d", resp.Code)
+	}
+	rotateKey := middleware.AuthMiddleware(users.RotateAPIKey, authService, domain.PermAPIKeysManage)
+	if resp = call(rotateKey, "Authorization", adminToken, map[string]string{"id": created.ID}); resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 Conflict rotating a revoked key, got %d", resp.Code)
+	}
+	if strings.Contains(call(middleware.AuthMiddleware(users.GetAPIKeys, authService, domain.PermAPIKeysManage), "Authorization", adminToken, nil).Body.String(), "secrethash") {
+		t.Errorf("Expected key hashes to be left out of the key list")
+	}
+}
+
+func TestJWKSHandler(t *testing.T) {
+	authService := newAuthService(t)
+	_, private, _ := ed25519.GenerateKey(nil)
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	key, err := auth.ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM failed: %v", err)
+	}
+	authService.Keys, _ = auth.NewKeySet(key)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	resp := httptest.NewRecorder()
+	JWKSHandler(authService).ServeHTTP(resp, req)
+
+	var jwks auth.JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		t.Fatalf("Failed to decode JWKS: %v", err)
+	}
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != key.ID || jwks.Keys[0].Alg != auth.AlgEdDSA || jwks.Keys[0].X == "" {
+		t.Errorf("Expected the Ed25519 public key, got %+v", jwks)
+	}
+	if strings.Contains(resp.Body.String(), `"d"`) {
+		t.Errorf("Expected no private key material in the JWKS")
+	}
+}
+
+func TestAuditHandlerAndRequestID(t *testing.T) {
+	authService := newAuthService(t)
+	auditLog := inmemmory.NewAuditInMemmory()
+	authService.Audit = auditLog
+	users := NewUserHandlers(authService)
+	audits := NewAuditHandlers(audit.NewService(auditLog))
+	tokens, _ := authService.Login(ctx, "admin", "admin123")
+	adminToken := "Bearer " + tokens.AccessToken
+
+	addUser := middleware.RequestID(middleware.AuthMiddleware(users.AddUser, authService, domain.PermUsersManage))
+	payload, _ := json.Marshal(map[string]string{"username": "bob", "password": "password", "role": domain.RoleAuditor})
+	req := httptest.NewRequest(http.MethodPost, "/AddUser", bytes.NewReader(payload))
+	req.Header.Set("Authorization", adminToken)
+	req.Header.Set(middleware.RequestIDHeader, "trace-42")
+	resp := httptest.NewRecorder()
+	addUser.ServeHTTP(resp, req)
+	if resp.Code != http.StatusCreated || resp.Header().Get(middleware.RequestIDHeader) != "trace-42" {
+		t.Fatalf("Expected the user to be created under request trace-42, got %d %q", resp.Code, resp.Header().Get(middleware.RequestIDHeader))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "not a valid id!")
+	resp = httptest.NewRecorder()
+	middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(resp, req)
+	if id := resp.Header().Get(middleware.RequestIDHeader); len(id) != 32 {
+		t.Errorf("Expected a generated request ID in place of a bad one, got %q", id)
+	}
+
+	getAudit := middleware.AuthMiddleware(audits.GetAudit, authService, domain.PermAuditRead)
+	query := func(token, params string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/audit?"+params, nil)
+		req.Header.Set("Authorization", token)
+		resp := httptest.NewRecorder()
+		getAudit(resp, req)
+		return resp
+	}
+	resp = query(adminToken, "action=user&requestid=trace-42")
+	var entries []domain.AuditEntry
+	json.NewDecoder(resp.Body).Decode(&entries)
+	if resp.Code != http.StatusOK || len(entries) != 1 || entries[0].Action != domain.AuditUserCreate || entries[0].Actor != "admin" {
+		t.Fatalf("Expected the user creation, got %d %+v", resp.Code, entries)
+	}
+	if strings.Contains(string(entries[0].After), "passwordhash") {
+		t.Errorf("Expected no password hash in the audit entry, got %s", entries[0].After)
+	}
+	resp = query(adminToken, "action=login&limit=1")
+	json.NewDecoder(resp.Body).Decode(&entries)
+	if len(entries) != 1 || entries[0].Action != domain.AuditLoginSuccess {
+		t.Errorf("Expected the latest login, got %+v", entries)
+	}
+	for params, status := range map[string]int{
+		"from=yesterday":                http.StatusBadRequest,
+		"limit=5000":                    http.StatusUnprocessableEntity,
+		"from=2026-01-02&to=2026-01-01": http.StatusUnprocessableEntity,
+	} {
+		if resp = query(adminToken, params); resp.Code != status {
+			t.Errorf("Expected status %d for %s, got %d", status, params, resp.Code)
+		}
+	}
+
+	bobTokens, _ := authService.Login(ctx, "bob", "password")
+	if resp = query("Bearer "+bobTokens.AccessToken, ""); resp.Code != http.StatusOK {
+		t.Errorf("Expected auditors to read the audit log, got %d", resp.Code)
+	}
+	attendant, _ := authService.CreateUser(ctx, domain.User{Username: "ravi", Role: domain.RoleAttendant}, "password")
+	attendantTokens, _ := authService.Login(ctx, attendant.Username, "password")
+	if resp = query("Bearer "+attendantTokens.AccessToken, ""); resp.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 Forbidden for an attendant, got %d", resp.Code)
+	}
+}
+
+func newAvailabilityHandlers(t *testing.T) (*AvailabilityHandlers, *parking.ParkingService) {
+	service := parking.NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	bus := events.NewBus()
+	service.Events = bus
+	if err := service.AddSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}); err != nil {
+		t.Fatalf("AddSlot failed: %v", err)
+	}
+	return NewAvailabilityHandlers(service, availability.NewFeed(service, bus)), service
+}
+
+func TestGetAvailability(t *testing.T) {
+	h, _ := newAvailabilityHandlers(t)
+	resp := httptest.NewRecorder()
+	h.GetAvailability(resp, httptest.NewRequest(http.MethodGet, "/api/v1/availability", nil))
+
+	var update AvailabilityUpdate
+	json.NewDecoder(resp.Body).Decode(&update)
+	if resp.Code != http.StatusOK || update.Kind != AvailabilitySnapshot || update.Slots["car"] != (domain.SlotAvailability{Total: 1, Free: 1}) {
+		t.Errorf("Expected a snapshot with one free car slot, got %d %+v", resp.Code, update)
+	}
+}
+
+func TestStreamAvailability(t *testing.T) {
+	h, service := newAvailabilityHandlers(t)
+	server := httptest.NewServer(http.HandlerFunc(h.StreamAvailability))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() (string, AvailabilityUpdate) {
+		var name string
+		var update AvailabilityUpdate
+		for lines.Scan() {
+			line := lines.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &update)
+			case line == "" && name != "":
+				return name, update
+			}
+		}
+		t.Fatalf("Stream ended: %v", lines.Err())
+		return "", update
+	}
+
+	if name, update := next(); name != AvailabilitySnapshot || update.Slots["car"].Free != 1 {
+		t.Errorf("Expected a snapshot with one free car slot, got %s %+v", name, update)
+	}
+	if _, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"}); err != nil {
+		t.Fatalf("ParkVehicle failed: %v", err)
+	}
+	for _, want := range []string{domain.EventTicketOpened, domain.EventSlotOccupied} {
+		name, update := next()
+		if name != AvailabilityChange || update.Event == nil || update.Event.Type != want || update.Slots["car"].Free != 0 {
+			t.Errorf("Expected a %s change with no free car slots, got %s %+v", want, name, update)
+		}
+	}
+}
+
+func TestWatchAvailability(t *testing.T) {
+	h, service := newAvailabilityHandlers(t)
+	h.Heartbeat = 20 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(h.WatchAvailability))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var update AvailabilityUpdate
+	if err := conn.ReadJSON(&update); err != nil || update.Kind != AvailabilitySnapshot {
+		t.Fatalf("Expected a snapshot, got %+v %v", update, err)
+	}
+	if err := service.AddSlot(ctx, domain.Slot{SlotId: 2, SlotType: "bike", IsFree: true}); err != nil {
+		t.Fatalf("AddSlot failed: %v", err)
+	}
+	update = AvailabilityUpdate{}
+	if err := conn.ReadJSON(&update); err != nil || update.Kind != AvailabilityChange || update.Event.Type != domain.EventSlotAdded ||
+		update.Slots["bike"] != (domain.SlotAvailability{Total: 1, Free: 1}) {
+		t.Errorf("Expected a slot.added change counting the bike slot, got %+v %v", update, err)
+	}
+
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	deadline := time.Now().Add(time.Second)
+	for h.feed.Subscribers() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := h.feed.Subscribers(); n != 0 {
+		t.Errorf("Expected the subscription to close with the socket, %d left", n)
+	}
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	fail := true
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	webhooks := webhook.NewService(inmemmory.NewWebhookInMemmory())
+	webhooks.AllowPrivateTargets = true
+	webhooks.MaxAttempts = 1
+	h := NewWebhookHandlers(webhooks)
+	service := parking.NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	service.Outbox = inmemmory.NewOutboxInMemmory()
+	relay := outbox.NewRelay(service.Outbox, webhooks)
+
+	resp := httptest.NewRecorder()
+	h.AddWebhook(resp, httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", strings.NewReader(`{"url": "`+receiver.URL+`", "eventtypes": ["slot.added"]}`)))
+	var created struct {
+		ID     string `json:"id"`
+		Secret string `json:"secret"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	if resp.Code != http.StatusCreated || created.ID == "" || created.Secret == "" {
+		t.Fatalf("Expected the new webhook with its secret, got %d %+v", resp.Code, created)
+	}
+	resp = httptest.NewRecorder()
+	h.AddWebhook(resp, httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", strings.NewReader(`{"url": "ftp://example.com", "eventtypes": ["slot.added"]}`)))
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for a non-HTTP URL, got %d", resp.Code)
+	}
+
+	if err := service.AddSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}); err != nil {
+		t.Fatalf("AddSlot failed: %v", err)
+	}
+	if _, err := relay.RelayPending(ctx); err != nil {
+		t.Fatalf("RelayPending failed: %v", err)
+	}
+	if _, err := webhooks.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue failed: %v", err)
+	}
+
+	resp = httptest.NewRecorder()
+	h.GetDeadLetters(resp, httptest.NewRequest(http.MethodGet, "/api/v1/webhook-deliveries/dead", nil))
+	var dead []domain.WebhookDelivery
+	json.NewDecoder(resp.Body).Decode(&dead)
+	if resp.Code != http.StatusOK || len(dead) != 1 || dead[0].EventType != domain.EventSlotAdded || dead[0].LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected one dead slot.added delivery, got %d %+v", resp.Code, dead)
+	}
+
+	fail = false
+	resp = httptest.NewRecorder()
+	retry := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/api/v1/webhook-deliveries/"+dead[0].ID+"/retry", nil), map[string]string{"id": dead[0].ID})
+	h.RetryDelivery(resp, retry)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("Expected the dead delivery to be queued again, got %d", resp.Code)
+	}
+	resp = httptest.NewRecorder()
+	h.RetryDelivery(resp, retry)
+	if resp.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 Conflict retrying a pending delivery, got %d", resp.Code)
+	}
+	if _, err := webhooks.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue failed: %v", err)
+	}
+
+	resp = httptest.NewRecorder()
+	h.GetDeliveries(resp, httptest.NewRequest(http.MethodGet, "/api/v1/webhook-deliveries?status=delivered&subscription="+created.ID, nil))
+	var delivered []domain.WebhookDelivery
+	json.NewDecoder(resp.Body).Decode(&delivered)
+	if resp.Code != http.StatusOK || len(delivered) != 1 || delivered[0].ID != dead[0].ID {
+		t.Errorf("Expected the retried delivery in the log, got %d %+v", resp.Code, delivered)
+	}
+	resp = httptest.NewRecorder()
+	h.GetDeliveries(resp, httptest.NewRequest(http.MethodGet, "/api/v1/webhook-deliveries?limit=lots", nil))
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a bad limit, got %d", resp.Code)
+	}
+
+	resp = httptest.NewRecorder()
+	h.DeleteWebhook(resp, mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/api/v1/webhooks/"+created.ID, nil), map[string]string{"id": created.ID}))
+	if resp.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 No Content, got %d", resp.Code)
+	}
+}
@@ -0,0 +1,128 @@
+package requestHandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handlers) ForceUnparkVehicleRequest(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForceUnparkVehicle
+	if !h.decode(w, r, &req) {
+		return
+	}
+	fee, err := h.service.ForceUnparkVehicle(r.Context(), req.VehicleNumber, req.Reason)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"vehiclenumber": req.VehicleNumber,
+		"unpaidfee":     fee,
+		"message":       "Vehicle exited with fee recorded as unpaid",
+	})
+}
+
+func (h *Handlers) GetUnpaidBalance(w http.ResponseWriter, r *http.Request) {
+	vehicleNumber := r.URL.Query().Get("vehiclenumber")
+	balance, err := h.service.GetUnpaidBalance(r.Context(), vehicleNumber)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	entries, err := h.service.GetLedgerEntries(r.Context(), vehicleNumber)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"balance": balance,
+		"entries": entries,
+	})
+}
+
+func (h *Handlers) SettleBalanceRequest(w http.ResponseWriter, r *http.Request) {
+	var req dto.SettleBalance
+	if !h.decode(w, r, &req) {
+		return
+	}
+	remaining, err := h.service.SettleBalance(r.Context(), req.VehicleNumber, req.Amount, req.PaymentRequest())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"vehiclenumber": req.VehicleNumber,
+		"balance":       remaining,
+		"message":       "Balance settled",
+	})
+}
+
+// ForceExitTicket serves POST /api/v1/tickets/{id}/force-exit.
+func (h *Handlers) ForceExitTicket(w http.ResponseWriter, r *http.Request) {
+	id, ok := ticketID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.ForceExit
+	if !h.decode(w, r, &req) {
+		return
+	}
+	fee, err := h.service.ForceExitTicket(r.Context(), id, req.Reason)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ticketid":  id,
+		"unpaidfee": fee,
+	})
+}
+
+// GetVehicleBalance serves GET /api/v1/vehicles/{vehiclenumber}/balance.
+func (h *Handlers) GetVehicleBalance(w http.ResponseWriter, r *http.Request) {
+	vehicleNumber := mux.Vars(r)["vehiclenumber"]
+	balance, err := h.service.GetUnpaidBalance(r.Context(), vehicleNumber)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	entries, err := h.service.GetLedgerEntries(r.Context(), vehicleNumber)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"balance": balance,
+		"entries": entries,
+	})
+}
+
+// CreateSettlement serves POST /api/v1/vehicles/{vehiclenumber}/settlements
+// and answers with the balance left.
+func (h *Handlers) CreateSettlement(w http.ResponseWriter, r *http.Request) {
+	var req dto.Settlement
+	if !h.decode(w, r, &req) {
+		return
+	}
+	vehicleNumber := mux.Vars(r)["vehiclenumber"]
+	remaining, err := h.service.SettleBalance(r.Context(), vehicleNumber, req.Amount, req.PaymentRequest())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"vehiclenumber": vehicleNumber,
+		"settled":       req.Amount,
+		"balance":       remaining,
+	})
+}
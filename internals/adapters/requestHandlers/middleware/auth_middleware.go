@@ -1,35 +1,97 @@
 package middleware
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
 	"parkingSlotManagement/internals/core/services/auth"
 	"strings"
 )
 
-func AuthMiddleware(next http.HandlerFunc, authService auth.AuthService) http.HandlerFunc {
+// APIKeyHeader carries an API key, the alternative to a bearer token for
+// machine clients.
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware lets the request through only if it carries a valid token
+// or API key for a principal granted permission.
+func AuthMiddleware(next http.HandlerFunc, authService auth.AuthService, permission domain.Permission) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Missing token", http.StatusUnauthorized)
-			return
+		var user *domain.User
+		var err error
+		if key := r.Header.Get(APIKeyHeader); key != "" {
+			if user, err = authService.ValidateAPIKey(r.Context(), key); err != nil {
+				problem.Write(w, r, err)
+				return
+			}
+		} else {
+			if r.Header.Get("Authorization") == "" {
+				problem.Write(w, r, fmt.Errorf("%w: missing token", problem.ErrUnauthorized))
+				return
+			}
+			tokenStr, ok := BearerToken(r)
+			if !ok {
+				problem.Write(w, r, fmt.Errorf("%w: Authorization header must be \"Bearer <token>\"", problem.ErrUnauthorized))
+				return
+			}
+			if user, err = authService.ValidateToken(r.Context(), tokenStr); err != nil {
+				problem.Write(w, r, err)
+				return
+			}
 		}
-
-		// Split "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+		if !user.Can(permission) {
+			problem.Write(w, r, fmt.Errorf("%w: requires %s", problem.ErrForbidden, permission))
 			return
 		}
 
-		tokenStr := strings.TrimSpace(parts[1])
-		// fmt.Println("Clean token received:", tokenStr)
+		ctx := domain.WithClientAddress(domain.WithUser(r.Context(), user), ClientAddress(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// StreamTokenParam is the query parameter a browser puts a stream token in,
+// since EventSource and WebSocket can't set request headers.
+const StreamTokenParam = "token"
 
-		_, err := authService.ValidateToken(tokenStr)
+// StreamAuthMiddleware is AuthMiddleware for the availability streams: a
+// request with a token query parameter is let through if it holds a valid
+// stream token, any other request must authenticate the usual way.
+func StreamAuthMiddleware(next http.HandlerFunc, authService auth.AuthService) http.HandlerFunc {
+	headerAuth := AuthMiddleware(next, authService, domain.PermAvailabilityRead)
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenStr := r.URL.Query().Get(StreamTokenParam)
+		if tokenStr == "" {
+			headerAuth(w, r)
+			return
+		}
+		user, err := authService.ValidateStreamToken(r.Context(), tokenStr)
 		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			problem.Write(w, r, err)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		ctx := domain.WithClientAddress(domain.WithUser(r.Context(), user), ClientAddress(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// BearerToken returns the token from an "Authorization: Bearer <token>"
+// header.
+func BearerToken(r *http.Request) (string, bool) {
+	parts := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", false
+	}
+	return strings.TrimSpace(parts[1]), true
+}
+
+// ClientAddress returns the IP address the request came from. Forwarding
+// headers are ignored, since any client can set them.
+func ClientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
+	return host
 }
@@ -0,0 +1,14 @@
+package middleware
+
+import "net/http"
+
+// Deprecated marks the response of a legacy route as deprecated and points
+// to the /api/v1 resource that replaces it, using the Deprecation header
+// and a successor-version Link.
+func Deprecated(successor string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	}
+}
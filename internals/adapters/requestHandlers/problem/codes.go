@@ -0,0 +1,142 @@
+package problem
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/repositories/mysql"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/audit"
+	"parkingSlotManagement/internals/core/services/auth"
+	"parkingSlotManagement/internals/core/services/currency"
+	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/core/services/plate"
+	"parkingSlotManagement/internals/core/services/webhook"
+	"parkingSlotManagement/internals/ports"
+)
+
+const codeInternal = "internal_error"
+
+type mapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// mappings is searched in order with errors.Is, so an error wrapping
+// several sentinels takes the first listed. The handler's own errors come
+// first: they are used to pin a status on a service error.
+var mappings = []mapping{
+	{ErrInvalidBody, http.StatusBadRequest, "invalid_body"},
+	{ErrInvalidParameter, http.StatusBadRequest, "invalid_parameter"},
+	{ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{ErrForbidden, http.StatusForbidden, "forbidden"},
+	{ErrNotFound, http.StatusNotFound, "not_found"},
+	{ErrMethodNotAllowed, http.StatusMethodNotAllowed, "method_not_allowed"},
+
+	{dto.ErrInvalidRequest, http.StatusUnprocessableEntity, "validation_failed"},
+
+	{plate.ErrEmptyPlate, http.StatusUnprocessableEntity, "vehicle_number_required"},
+	{plate.ErrInvalidPlate, http.StatusUnprocessableEntity, "invalid_vehicle_number"},
+
+	{parking.ErrTicketNotFound, http.StatusNotFound, "ticket_not_found"},
+	{parking.ErrAdjustmentNotFound, http.StatusNotFound, "adjustment_not_found"},
+	{parking.ErrVehicleAlreadyParked, http.StatusConflict, "vehicle_already_parked"},
+	{parking.ErrSlotFetchByType, http.StatusConflict, "no_free_slot"},
+	{parking.ErrTicketClosed, http.StatusConflict, "ticket_closed"},
+	{parking.ErrTicketNotClosed, http.StatusConflict, "ticket_not_closed"},
+	{parking.ErrReceiptNotReady, http.StatusConflict, "receipt_not_ready"},
+	{parking.ErrInvalidAdjustmentState, http.StatusConflict, "invalid_adjustment_state"},
+	{parking.ErrVehicleBlocklisted, http.StatusForbidden, "vehicle_blocklisted"},
+	{parking.ErrAdjustmentApprovalDenied, http.StatusForbidden, "adjustment_approval_denied"},
+	{parking.ErrSelfReview, http.StatusForbidden, "adjustment_self_review"},
+	{parking.ErrUnpaidBalanceExceeded, http.StatusPaymentRequired, "unpaid_balance_exceeded"},
+	{parking.ErrPaymentFailed, http.StatusPaymentRequired, "payment_failed"},
+	{parking.ErrRefundFailed, http.StatusBadGateway, "refund_failed"},
+	{parking.ErrInvalidVehicleType, http.StatusUnprocessableEntity, "invalid_vehicle_type"},
+	{parking.ErrInvalidListType, http.StatusUnprocessableEntity, "invalid_list_type"},
+	{parking.ErrInvalidSettlementAmount, http.StatusUnprocessableEntity, "invalid_settlement_amount"},
+	{parking.ErrSettlementExceedsBalance, http.StatusUnprocessableEntity, "settlement_exceeds_balance"},
+	{parking.ErrUnsupportedPaymentMethod, http.StatusUnprocessableEntity, "unsupported_payment_method"},
+	{parking.ErrAdjustmentReasonRequired, http.StatusUnprocessableEntity, "adjustment_reason_required"},
+	{parking.ErrInvalidAdjustmentAmount, http.StatusUnprocessableEntity, "invalid_adjustment_amount"},
+	{parking.ErrAdjustmentExceedsFee, http.StatusUnprocessableEntity, "adjustment_exceeds_fee"},
+	{parking.ErrInvalidReportRange, http.StatusUnprocessableEntity, "invalid_report_range"},
+	{parking.ErrWrongCurrency, http.StatusUnprocessableEntity, "wrong_currency"},
+	{parking.ErrVehicleListUnavailable, http.StatusServiceUnavailable, "vehicle_lists_unavailable"},
+	{parking.ErrLedgerUnavailable, http.StatusServiceUnavailable, "ledger_unavailable"},
+	{parking.ErrAdjustmentsUnavailable, http.StatusServiceUnavailable, "adjustments_unavailable"},
+	{parking.ErrExchangeRateMissing, http.StatusServiceUnavailable, "exchange_rate_missing"},
+
+	{domain.ErrInvalidMoney, http.StatusUnprocessableEntity, "invalid_amount"},
+	{domain.ErrCurrencyMismatch, http.StatusConflict, "currency_mismatch"},
+	{currency.ErrInvalidCurrency, http.StatusUnprocessableEntity, "invalid_currency"},
+
+	{auth.ErrUsernameRequired, http.StatusUnprocessableEntity, "username_required"},
+	{auth.ErrPasswordRequired, http.StatusUnprocessableEntity, "password_required"},
+	{auth.ErrPasswordTooShort, http.StatusUnprocessableEntity, "password_too_short"},
+	{auth.ErrPasswordTooLong, http.StatusUnprocessableEntity, "password_too_long"},
+	{auth.ErrInvalidRole, http.StatusUnprocessableEntity, "invalid_role"},
+	{auth.ErrInvalidScope, http.StatusUnprocessableEntity, "invalid_scope"},
+	{auth.ErrAPIKeyNameRequired, http.StatusUnprocessableEntity, "api_key_name_required"},
+	{auth.ErrInvalidCredentials, http.StatusForbidden, "invalid_credentials"},
+	{auth.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+	{auth.ErrNotLocked, http.StatusNotFound, "not_locked"},
+	{auth.ErrAPIKeyNotFound, http.StatusNotFound, "api_key_not_found"},
+	{auth.ErrUsernameTaken, http.StatusConflict, "username_taken"},
+	{auth.ErrLastAdmin, http.StatusConflict, "last_admin"},
+	{auth.ErrAPIKeyRevoked, http.StatusConflict, "api_key_revoked"},
+	{auth.ErrAlreadyBootstrapped, http.StatusConflict, "already_bootstrapped"},
+	{auth.ErrTooManyAttempts, http.StatusTooManyRequests, "too_many_attempts"},
+	{auth.ErrInvalidToken, http.StatusUnauthorized, "invalid_token"},
+	{auth.ErrTokenRevoked, http.StatusUnauthorized, "token_revoked"},
+	{auth.ErrInvalidAPIKey, http.StatusUnauthorized, "invalid_api_key"},
+	{auth.ErrAPIKeysUnavailable, http.StatusServiceUnavailable, "api_keys_unavailable"},
+
+	{audit.ErrInvalidRange, http.StatusUnprocessableEntity, "invalid_audit_range"},
+	{audit.ErrInvalidLimit, http.StatusUnprocessableEntity, "invalid_limit"},
+
+	{webhook.ErrInvalidURL, http.StatusUnprocessableEntity, "invalid_webhook_url"},
+	{webhook.ErrForbiddenTarget, http.StatusUnprocessableEntity, "webhook_target_forbidden"},
+	{webhook.ErrUnresolvableHost, http.StatusUnprocessableEntity, "webhook_host_unresolvable"},
+	{webhook.ErrEventTypesRequired, http.StatusUnprocessableEntity, "event_types_required"},
+	{webhook.ErrUnknownEventType, http.StatusUnprocessableEntity, "unknown_event_type"},
+	{webhook.ErrSecretTooShort, http.StatusUnprocessableEntity, "webhook_secret_too_short"},
+	{webhook.ErrInvalidLimit, http.StatusUnprocessableEntity, "invalid_limit"},
+	{webhook.ErrInvalidStatus, http.StatusUnprocessableEntity, "invalid_delivery_status"},
+	{webhook.ErrSubscriptionNotFound, http.StatusNotFound, "webhook_not_found"},
+	{webhook.ErrDeliveryNotFound, http.StatusNotFound, "webhook_delivery_not_found"},
+	{webhook.ErrDeliveryNotDead, http.StatusConflict, "webhook_delivery_not_dead"},
+
+	{mysql.ErrSlotNotFound, http.StatusNotFound, "slot_not_found"},
+	{mysql.ErrSlotNotFoundByID, http.StatusNotFound, "slot_not_found"},
+	{ports.ErrSlotExists, http.StatusConflict, "slot_exists"},
+	{mysql.ErrTicketNotFound, http.StatusNotFound, "ticket_not_found"},
+	{ports.ErrVehicleListEntryNotFound, http.StatusNotFound, "vehicle_list_entry_not_found"},
+	{mysql.ErrAdjustmentNotFound, http.StatusNotFound, "adjustment_not_found"},
+	{mysql.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+	{mysql.ErrAPIKeyNotFound, http.StatusNotFound, "api_key_not_found"},
+	{mysql.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found"},
+	{mysql.ErrDeliveryNotFound, http.StatusNotFound, "webhook_delivery_not_found"},
+	{mysql.ErrInvalidSlotType, http.StatusUnprocessableEntity, "invalid_vehicle_type"},
+	{driver.ErrBadConn, http.StatusServiceUnavailable, "database_unavailable"},
+	{sql.ErrConnDone, http.StatusServiceUnavailable, "database_unavailable"},
+}
+
+func lookup(err error) mapping {
+	for _, m := range mappings {
+		if errors.Is(err, m.err) {
+			return m
+		}
+	}
+	return mapping{status: http.StatusInternalServerError, code: codeInternal}
+}
+
+// Classify returns the HTTP status and stable code err maps to, for
+// adapters that report errors over another protocol.
+func Classify(err error) (status int, code string) {
+	m := lookup(err)
+	return m.status, m.code
+}
@@ -0,0 +1,96 @@
+// Package problem writes error responses as RFC 9457 problem details, with
+// the status and a stable code looked up from the error.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// ContentType is the media type of every error response.
+const ContentType = "application/problem+json"
+
+// Errors raised by the handlers themselves rather than the services.
+var (
+	ErrInvalidBody      = errors.New("request body is not valid JSON")
+	ErrInvalidParameter = errors.New("invalid parameter")
+	ErrUnauthorized     = errors.New("authentication required")
+	ErrForbidden        = errors.New("permission denied")
+	ErrNotFound         = errors.New("no such resource")
+	ErrMethodNotAllowed = errors.New("method not allowed")
+)
+
+// Problem is the body of an error response. Code is stable and meant for
+// programs; Title and Detail are for people and may change.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Code      string `json:"code"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"requestid,omitempty"`
+	// Errors lists the fields that failed validation, if any.
+	Errors dto.Errors `json:"errors,omitempty"`
+}
+
+// Invalid reports a bad path or query parameter.
+func Invalid(name string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidParameter, name)
+}
+
+// As pins err to the status and code of kind, e.g. to answer a failed
+// login with 401 whatever the cause. The detail still comes from err.
+func As(kind, err error) error {
+	return fmt.Errorf("%w: %w", kind, err)
+}
+
+// New returns the problem for err, as Write would send it.
+func New(r *http.Request, err error) Problem {
+	m := lookup(err)
+	detail := err.Error()
+	if m.code == codeInternal {
+		// Don't leak what went wrong inside; it's in the server log.
+		detail = ""
+	}
+	var fields dto.Errors
+	errors.As(err, &fields)
+	return Problem{
+		Type:      "/problems/" + m.code,
+		Title:     http.StatusText(m.status),
+		Status:    m.status,
+		Code:      m.code,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: domain.RequestID(r.Context()),
+		Errors:    fields,
+	}
+}
+
+// Write answers the request with the problem for err. Errors that map to
+// no known status are logged and sent as a bare 500.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := New(r, err)
+	if p.Code == codeInternal {
+		log.Printf("%s %s [%s]: %v", r.Method, r.URL.Path, p.RequestID, err)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// NotFound and MethodNotAllowed answer requests that match no route.
+var (
+	NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, ErrNotFound)
+	})
+	MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, ErrMethodNotAllowed)
+	})
+)
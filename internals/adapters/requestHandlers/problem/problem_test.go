@@ -0,0 +1,95 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"parkingSlotManagement/internals/adapters/repositories/mysql"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/auth"
+	"parkingSlotManagement/internals/core/services/parking"
+	"parkingSlotManagement/internals/core/services/plate"
+	"parkingSlotManagement/internals/ports"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func write(err error) (*httptest.ResponseRecorder, Problem) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tickets/7", nil)
+	req = req.WithContext(domain.WithRequestID(req.Context(), "req-1"))
+	resp := httptest.NewRecorder()
+	Write(resp, req, err)
+	var p Problem
+	json.NewDecoder(resp.Body).Decode(&p)
+	return resp, p
+}
+
+func TestWriteMapsErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{parking.Wrap("failed to fetch ticket", parking.ErrTicketNotFound), http.StatusNotFound, "ticket_not_found"},
+		{parking.ErrVehicleAlreadyParked, http.StatusConflict, "vehicle_already_parked"},
+		{fmt.Errorf("%w %q: unknown state code", plate.ErrInvalidPlate, "ZZ01AB1234"), http.StatusUnprocessableEntity, "invalid_vehicle_number"},
+		{parking.ErrLedgerUnavailable, http.StatusServiceUnavailable, "ledger_unavailable"},
+		{auth.ErrUsernameTaken, http.StatusConflict, "username_taken"},
+		{auth.ErrTooManyAttempts, http.StatusTooManyRequests, "too_many_attempts"},
+		{mysql.Wrap("failed to fetch slot", mysql.ErrSlotNotFoundByID), http.StatusNotFound, "slot_not_found"},
+		{ports.ErrSlotExists, http.StatusConflict, "slot_exists"},
+		{Invalid("id"), http.StatusBadRequest, "invalid_parameter"},
+		{As(ErrUnauthorized, auth.ErrInvalidCredentials), http.StatusUnauthorized, "unauthorized"},
+	}
+	for _, test := range tests {
+		resp, p := write(test.err)
+		if resp.Code != test.status || p.Status != test.status || p.Code != test.code {
+			t.Errorf("%v: expected %d %s, got %d %+v", test.err, test.status, test.code, resp.Code, p)
+		}
+		if p.Detail != test.err.Error() {
+			t.Errorf("%v: expected the error as detail, got %q", test.err, p.Detail)
+		}
+	}
+}
+
+func TestWriteBody(t *testing.T) {
+	resp, p := write(parking.ErrTicketClosed)
+	if ct := resp.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected Content-Type %s, got %q", ContentType, ct)
+	}
+	want := Problem{
+		Type:      "/problems/ticket_closed",
+		Title:     "Conflict",
+		Status:    http.StatusConflict,
+		Code:      "ticket_closed",
+		Detail:    parking.ErrTicketClosed.Error(),
+		Instance:  "/api/v1/tickets/7",
+		RequestID: "req-1",
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("Expected %+v, got %+v", want, p)
+	}
+}
+
+func TestWriteHidesUnknownErrors(t *testing.T) {
+	resp, p := write(errors.New("dial tcp 10.0.0.5:3306: connection refused"))
+	if resp.Code != http.StatusInternalServerError || p.Code != "internal_error" {
+		t.Errorf("Expected 500 internal_error, got %d %+v", resp.Code, p)
+	}
+	if strings.Contains(resp.Body.String(), "10.0.0.5") || p.Detail != "" {
+		t.Errorf("Expected the cause to stay in the log, got %q", resp.Body.String())
+	}
+}
+
+func TestEachCodeHasOneStatus(t *testing.T) {
+	seen := map[string]int{}
+	for _, m := range mappings {
+		if status, ok := seen[m.code]; ok && status != m.status {
+			t.Errorf("Code %s is used with both %d and %d", m.code, status, m.status)
+		}
+		seen[m.code] = m.status
+	}
+}
@@ -0,0 +1,148 @@
+package requestHandlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// The handlers in this file serve the /api/v1 slot and ticket resources.
+// They take IDs from the path and answer with JSON.
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// pathInt64 reads a numeric path variable such as the {id} in
+// /api/v1/tickets/{id}.
+func pathInt64(r *http.Request, name string) (int64, error) {
+	return strconv.ParseInt(mux.Vars(r)[name], 10, 64)
+}
+
+// ticketID reads the {id} path variable, answering 400 if it isn't a number.
+func ticketID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := pathInt64(r, "id")
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// ListSlots lists every slot, or only the free ones with ?free=true.
+func (h *Handlers) ListSlots(w http.ResponseWriter, r *http.Request) {
+	free, err := strconv.ParseBool(r.URL.Query().Get("free"))
+	if err != nil && r.URL.Query().Has("free") {
+		problem.Write(w, r, problem.Invalid("free"))
+		return
+	}
+	var slots []domain.Slot
+	if free {
+		slots, err = h.service.GetAvailableSlots(r.Context())
+	} else {
+		slots, err = h.service.GetSlots(r.Context())
+	}
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if slots == nil {
+		slots = []domain.Slot{}
+	}
+	writeJSON(w, http.StatusOK, slots)
+}
+
+func (h *Handlers) CreateSlot(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateSlot
+	if !h.decode(w, r, &req) {
+		return
+	}
+	slot := req.Slot()
+	if err := h.service.AddSlot(r.Context(), slot); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, slot)
+}
+
+// CreateTicket parks a vehicle. The new ticket's URL is in the Location
+// header.
+func (h *Handlers) CreateTicket(w http.ResponseWriter, r *http.Request) {
+	var req dto.ParkVehicle
+	if !h.decode(w, r, &req) {
+		return
+	}
+	ticket, err := h.service.ParkVehicle(r.Context(), req.Vehicle())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/tickets/%d", ticket.TicketId))
+	writeJSON(w, http.StatusCreated, ticket)
+}
+
+func (h *Handlers) GetTicket(w http.ResponseWriter, r *http.Request) {
+	id, ok := ticketID(w, r)
+	if !ok {
+		return
+	}
+	ticket, err := h.service.GetTicket(r.Context(), id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ticket)
+}
+
+// GetTicketQuote shows the fee due if the vehicle left now.
+func (h *Handlers) GetTicketQuote(w http.ResponseWriter, r *http.Request) {
+	id, ok := ticketID(w, r)
+	if !ok {
+		return
+	}
+	quote, err := h.service.QuoteTicketExit(r.Context(), id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, quote)
+}
+
+// ExitTicket collects the fee and closes the ticket, returning it.
+func (h *Handlers) ExitTicket(w http.ResponseWriter, r *http.Request) {
+	id, ok := ticketID(w, r)
+	if !ok {
+		return
+	}
+	var req dto.Payment
+	if !h.decode(w, r, &req) {
+		return
+	}
+	ticket, err := h.service.ExitTicket(r.Context(), id, req.PaymentRequest())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ticket)
+}
+
+func (h *Handlers) GetTicketReceipt(w http.ResponseWriter, r *http.Request) {
+	id, ok := ticketID(w, r)
+	if !ok {
+		return
+	}
+	receipt, err := h.service.GetReceipt(r.Context(), id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, receipt)
+}
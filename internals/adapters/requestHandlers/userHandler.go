@@ -0,0 +1,274 @@
+package requestHandlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/auth"
+
+	"github.com/gorilla/mux"
+)
+
+type UserHandlers struct {
+	authService auth.AuthService
+}
+
+func NewUserHandlers(authService auth.AuthService) *UserHandlers {
+	return &UserHandlers{authService: authService}
+}
+
+func writeUser(w http.ResponseWriter, status int, user *domain.User) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *UserHandlers) AddUser(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Role     string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	user, err := h.authService.CreateUser(r.Context(), domain.User{Username: req.Username, Role: req.Role}, req.Password)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeUser(w, http.StatusCreated, user)
+}
+
+func (h *UserHandlers) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID       string `json:"id"`
+		Role     string `json:"role"`
+		Disabled bool   `json:"disabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	user, err := h.authService.UpdateUser(r.Context(), req.ID, req.Role, req.Disabled)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeUser(w, http.StatusOK, user)
+}
+
+func (h *UserHandlers) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	if err := h.authService.DeleteUser(r.Context(), req.ID); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("User deleted successfully"))
+}
+
+func (h *UserHandlers) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.authService.ListUsers(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(users)
+}
+
+// ChangePassword changes the caller's own password.
+func (h *UserHandlers) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CurrentPassword string `json:"currentpassword"`
+		NewPassword     string `json:"newpassword"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	user, ok := domain.UserFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.ErrUnauthorized)
+		return
+	}
+	if err := h.authService.ChangePassword(r.Context(), user.ID, req.CurrentPassword, req.NewPassword); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Password changed successfully"))
+}
+
+// ResetPassword sets another user's password.
+func (h *UserHandlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID          string `json:"id"`
+		NewPassword string `json:"newpassword"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	if err := h.authService.ResetPassword(r.Context(), req.ID, req.NewPassword); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Password reset successfully"))
+}
+
+// GetLockouts lists the usernames and addresses locked out after failed
+// logins.
+func (h *UserHandlers) GetLockouts(w http.ResponseWriter, r *http.Request) {
+	lockouts, err := h.authService.ListLockouts(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if lockouts == nil {
+		lockouts = []domain.LoginAttempts{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lockouts)
+}
+
+// UnlockAccount lets a username, or a client address, log in again straight
+// away.
+func (h *UserHandlers) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username string `json:"username"`
+		Address  string `json:"address"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	var key string
+	switch {
+	case req.Username != "" && req.Address == "":
+		key = domain.LoginUserKey(req.Username)
+	case req.Address != "" && req.Username == "":
+		key = domain.LoginAddressKey(req.Address)
+	default:
+		problem.Write(w, r, fmt.Errorf("%w: give either username or address", problem.ErrInvalidBody))
+		return
+	}
+	if err := h.authService.Unlock(r.Context(), key); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Unlocked successfully"))
+}
+
+// The handlers below serve the /api/v1 user, password and lockout
+// resources, taking IDs from the path.
+
+func (h *UserHandlers) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.authService.GetUser(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeUser(w, http.StatusOK, user)
+}
+
+// PatchUser changes the role, the disabled flag or both; fields left out
+// keep their value.
+func (h *UserHandlers) PatchUser(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Role     *string `json:"role"`
+		Disabled *bool   `json:"disabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	user, err := h.authService.GetUser(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	role, disabled := user.Role, user.Disabled
+	if req.Role != nil {
+		role = *req.Role
+	}
+	if req.Disabled != nil {
+		disabled = *req.Disabled
+	}
+	user, err = h.authService.UpdateUser(r.Context(), user.ID, role, disabled)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeUser(w, http.StatusOK, user)
+}
+
+func (h *UserHandlers) RemoveUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.authService.DeleteUser(r.Context(), mux.Vars(r)["id"]); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetUserPassword serves PUT /api/v1/users/{id}/password, an admin reset.
+func (h *UserHandlers) SetUserPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		NewPassword string `json:"newpassword"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	if err := h.authService.ResetPassword(r.Context(), mux.Vars(r)["id"], req.NewPassword); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetOwnPassword serves PUT /api/v1/me/password.
+func (h *UserHandlers) SetOwnPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CurrentPassword string `json:"currentpassword"`
+		NewPassword     string `json:"newpassword"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	user, ok := domain.UserFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.ErrUnauthorized)
+		return
+	}
+	if err := h.authService.ChangePassword(r.Context(), user.ID, req.CurrentPassword, req.NewPassword); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteLockout serves DELETE /api/v1/lockouts/{key}, where key is as
+// listed by GetLockouts, e.g. "user:ravi" or "ip:10.0.0.7".
+func (h *UserHandlers) DeleteLockout(w http.ResponseWriter, r *http.Request) {
+	if err := h.authService.Unlock(r.Context(), mux.Vars(r)["key"]); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
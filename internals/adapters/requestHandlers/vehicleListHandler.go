@@ -0,0 +1,70 @@
+package requestHandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/dto"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *Handlers) AddVehicleListEntry(w http.ResponseWriter, r *http.Request) {
+	var req dto.VehicleListEntry
+	if !h.decode(w, r, &req) {
+		return
+	}
+	saved, err := h.service.AddVehicleListEntry(r.Context(), req.Entry())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+func (h *Handlers) RemoveVehicleListEntry(w http.ResponseWriter, r *http.Request) {
+	var req dto.Vehicle
+	if !h.decode(w, r, &req) {
+		return
+	}
+	if err := h.service.RemoveVehicleListEntry(r.Context(), req.VehicleNumber); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Vehicle list entry removed successfully"))
+}
+
+func (h *Handlers) GetVehicleList(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.service.GetVehicleListEntries(r.Context(), r.URL.Query().Get("listtype"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entries)
+}
+
+func (h *Handlers) GetEntryRejections(w http.ResponseWriter, r *http.Request) {
+	rejections, err := h.service.GetEntryRejections(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rejections)
+}
+
+// DeleteVehicleListEntry serves DELETE
+// /api/v1/vehicle-list-entries/{vehiclenumber}.
+func (h *Handlers) DeleteVehicleListEntry(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemoveVehicleListEntry(r.Context(), mux.Vars(r)["vehiclenumber"]); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
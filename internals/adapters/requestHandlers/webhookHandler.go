@@ -0,0 +1,120 @@
+package requestHandlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"parkingSlotManagement/internals/adapters/requestHandlers/problem"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/webhook"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type WebhookHandlers struct {
+	service *webhook.Service
+}
+
+func NewWebhookHandlers(service *webhook.Service) *WebhookHandlers {
+	return &WebhookHandlers{service: service}
+}
+
+// webhookWithSecret is returned when a subscription is created; it is the
+// only response that carries the signing secret.
+type webhookWithSecret struct {
+	*domain.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// AddWebhook subscribes a URL to event types. The secret is optional and
+// generated if left out.
+func (h *WebhookHandlers) AddWebhook(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL        string   `json:"url"`
+		EventTypes []string `json:"eventtypes"`
+		Secret     string   `json:"secret"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Write(w, r, problem.ErrInvalidBody)
+		return
+	}
+	sub, secret, err := h.service.Subscribe(r.Context(), req.URL, req.EventTypes, req.Secret)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, webhookWithSecret{WebhookSubscription: sub, Secret: secret})
+}
+
+func (h *WebhookHandlers) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.ListSubscriptions(r.Context())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if subs == nil {
+		subs = []domain.WebhookSubscription{}
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+func (h *WebhookHandlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Unsubscribe(r.Context(), mux.Vars(r)["id"]); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries is the delivery log, newest first, filtered by the
+// subscription, status and limit query parameters.
+func (h *WebhookHandlers) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := domain.WebhookDeliveryFilter{SubscriptionID: query.Get("subscription"), Status: query.Get("status")}
+	var err error
+	if filter.Limit, err = optionalLimit(query.Get("limit")); err != nil {
+		problem.Write(w, r, problem.Invalid("limit"))
+		return
+	}
+	deliveries, err := h.service.ListDeliveries(r.Context(), filter)
+	h.writeDeliveries(w, r, deliveries, err)
+}
+
+// GetDeadLetters lists the deliveries that ran out of attempts.
+func (h *WebhookHandlers) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit, err := optionalLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		problem.Write(w, r, problem.Invalid("limit"))
+		return
+	}
+	deliveries, err := h.service.DeadLetters(r.Context(), limit)
+	h.writeDeliveries(w, r, deliveries, err)
+}
+
+// RetryDelivery queues a dead delivery again.
+func (h *WebhookHandlers) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.Redeliver(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *WebhookHandlers) writeDeliveries(w http.ResponseWriter, r *http.Request, deliveries []domain.WebhookDelivery, err error) {
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []domain.WebhookDelivery{}
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func optionalLimit(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
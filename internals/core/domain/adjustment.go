@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+const (
+	AdjustmentRequested = "requested"
+	AdjustmentApproved  = "approved"
+	AdjustmentRejected  = "rejected"
+	AdjustmentApplying  = "applying"
+	AdjustmentApplied   = "applied"
+)
+
+// FeeAdjustment reduces the fee of a closed ticket by Amount. It goes
+// requested -> approved (by a supervisor) -> applied, at which point the
+// money is refunded through the payment gateway. It is applying while the
+// refund is in flight; one left applying needs to be checked against the
+// gateway by hand.
+type FeeAdjustment struct {
+	AdjustmentId    int64      `json:"adjustmentid"`
+	TicketId        int64      `json:"ticketid"`
+	VehicleNumber   string     `json:"vehiclenumber"`
+	Amount          Money      `json:"amount"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"`
+	RequestedBy     string     `json:"requestedby"`
+	RequestedAt     time.Time  `json:"requestedat"`
+	ReviewedBy      string     `json:"reviewedby,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewedat,omitempty"`
+	AppliedAt       *time.Time `json:"appliedat,omitempty"`
+	RefundReference string     `json:"refundreference,omitempty"`
+}
@@ -1,7 +0,0 @@
-package domain
-
-type Admin struct {
-	ID       string
-	Username string
-	Password string
-}
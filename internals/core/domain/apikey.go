@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// APIKey lets a machine client such as a barrier or kiosk call the API
+// without logging in. The secret is shown once when the key is created or
+// rotated; only its hash is kept.
+type APIKey struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	SecretHash string       `json:"-"`
+	Scopes     []Permission `json:"scopes"`
+	CreatedBy  string       `json:"createdby"`
+	CreatedAt  time.Time    `json:"createdat"`
+	RotatedAt  *time.Time   `json:"rotatedat,omitempty"`
+	RevokedAt  *time.Time   `json:"revokedat,omitempty"`
+}
+
+// APIKeyScopes are named sets of permissions that can be granted to a key
+// instead of listing the permissions one by one.
+var APIKeyScopes = map[string][]Permission{
+	"park-only": {PermParkingOperate},
+	"read-only": {PermReceiptsRead, PermVehicleListsRead, PermReportsRead, PermAvailabilityRead},
+	"display":   {PermAvailabilityRead},
+}
+
+// ValidAPIKeyScope reports whether p may be granted to an API key. Keys
+// can't manage users or keys, and have no account of their own.
+func ValidAPIKeyScope(p Permission) bool {
+	if p == PermUsersManage || p == PermAPIKeysManage || p == PermOwnAccount {
+		return false
+	}
+	for _, granted := range rolePermissions[RoleAdmin] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Principal returns the user a request authenticated with the key acts as.
+// It is restricted to the key's scopes and recorded as "apikey:<name>".
+func (k APIKey) Principal() *User {
+	return &User{
+		ID:        k.ID,
+		Username:  "apikey:" + k.Name,
+		APIKey:    true,
+		Disabled:  k.Revoked(),
+		CreatedAt: k.CreatedAt,
+		Scopes:    append([]Permission{}, k.Scopes...),
+	}
+}
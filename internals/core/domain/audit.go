@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Audit actions, named "<what>.<change>". Filtering on "ticket" matches
+// every ticket action.
+const (
+	AuditSlotAdd           = "slot.add"
+	AuditTicketPark        = "ticket.park"
+	AuditTicketUnpark      = "ticket.unpark"
+	AuditTicketForceUnpark = "ticket.forceunpark"
+	AuditLedgerSettle      = "ledger.settle"
+	AuditVehicleListAdd    = "vehiclelist.add"
+	AuditVehicleListRemove = "vehiclelist.remove"
+	AuditAdjustmentRequest = "adjustment.request"
+	AuditAdjustmentApprove = "adjustment.approve"
+	AuditAdjustmentReject  = "adjustment.reject"
+	AuditAdjustmentApply   = "adjustment.apply"
+	AuditUserCreate        = "user.create"
+	AuditUserUpdate        = "user.update"
+	AuditUserDelete        = "user.delete"
+	AuditPasswordChange    = "password.change"
+	AuditPasswordReset     = "password.reset"
+	AuditAPIKeyCreate      = "apikey.create"
+	AuditAPIKeyRotate      = "apikey.rotate"
+	AuditAPIKeyRevoke      = "apikey.revoke"
+	AuditWebhookCreate     = "webhook.create"
+	AuditWebhookDelete     = "webhook.delete"
+	AuditWebhookRedeliver  = "webhook.redeliver"
+	AuditLoginSuccess      = "login.success"
+	AuditLoginFailure      = "login.failure"
+	AuditLoginLockout      = "login.lockout"
+	AuditLoginUnlock       = "login.unlock"
+	AuditTokenRefresh      = "token.refresh"
+	AuditTokenLogout       = "token.logout"
+)
+
+// AuditEntry records one change: who made it, when, to what, and the value
+// before and after as JSON. Entries are only ever appended.
+type AuditEntry struct {
+	EntryId   int64           `json:"entryid"`
+	At        time.Time       `json:"at"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	RequestID string          `json:"requestid,omitempty"`
+	Address   string          `json:"address,omitempty"`
+	Detail    string          `json:"detail,omitempty"`
+}
+
+// NewAuditEntry fills in the time and, from ctx, the actor, request ID and
+// client address. before and after may be nil.
+func NewAuditEntry(ctx context.Context, action, target string, before, after any) AuditEntry {
+	return AuditEntry{
+		At:        time.Now(),
+		Actor:     Actor(ctx),
+		Action:    action,
+		Target:    target,
+		Before:    auditValue(before),
+		After:     auditValue(after),
+		RequestID: RequestID(ctx),
+		Address:   ClientAddress(ctx),
+	}
+}
+
+func auditValue(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil || string(encoded) == "null" {
+		return nil
+	}
+	return encoded
+}
+
+// AuditFilter selects audit entries. Empty fields match everything; From
+// and To bound At, From inclusive and To exclusive.
+type AuditFilter struct {
+	Actor     string
+	Action    string
+	Target    string
+	RequestID string
+	From      time.Time
+	To        time.Time
+	// Limit caps how many entries are returned, newest first.
+	Limit int
+}
+
+// MatchesAction reports whether action is the filter's action or one of
+// its sub-actions.
+func (f AuditFilter) MatchesAction(action string) bool {
+	return f.Action == "" || action == f.Action || strings.HasPrefix(action, f.Action+".")
+}
+
+func (f AuditFilter) Matches(e AuditEntry) bool {
+	return (f.Actor == "" || e.Actor == f.Actor) &&
+		f.MatchesAction(e.Action) &&
+		(f.Target == "" || e.Target == f.Target) &&
+		(f.RequestID == "" || e.RequestID == f.RequestID) &&
+		(f.From.IsZero() || !e.At.Before(f.From)) &&
+		(f.To.IsZero() || e.At.Before(f.To))
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Event types, named "<what>.<change>" like audit actions.
+const (
+	EventSlotAdded    = "slot.added"
+	EventSlotOccupied = "slot.occupied"
+	EventSlotFreed    = "slot.freed"
+	EventTicketOpened = "ticket.opened"
+	EventTicketClosed = "ticket.closed"
+)
+
+// EventTypes lists every event type the parking service publishes.
+var EventTypes = []string{EventSlotAdded, EventSlotOccupied, EventSlotFreed, EventTicketOpened, EventTicketClosed}
+
+// Event reports a change to a slot or ticket. It is published once the
+// change has been stored. Ticket fields are only set on ticket events, and
+// Fee only when a ticket is closed.
+type Event struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	At            time.Time `json:"at"`
+	SlotId        int       `json:"slotid"`
+	SlotType      string    `json:"slottype"`
+	TicketId      int64     `json:"ticketid,omitempty"`
+	VehicleNumber string    `json:"vehiclenumber,omitempty"`
+	Fee           *Money    `json:"fee,omitempty"`
+}
+
+// NewSlotEvent returns an event of eventType for slot.
+func NewSlotEvent(eventType string, slot Slot) Event {
+	return Event{
+		ID:       newEventID(),
+		Type:     eventType,
+		At:       time.Now(),
+		SlotId:   slot.SlotId,
+		SlotType: slot.SlotType,
+	}
+}
+
+// NewTicketEvent returns an event of eventType for ticket, parked in slot.
+func NewTicketEvent(eventType string, ticket Ticket, slot Slot) Event {
+	event := NewSlotEvent(eventType, slot)
+	event.TicketId = ticket.TicketId
+	event.VehicleNumber = ticket.VehicleNumber
+	if ticket.ExitTime != nil {
+		fee := ticket.Fee
+		event.Fee = &fee
+	}
+	return event
+}
+
+// SlotAvailability counts the slots of one type.
+type SlotAvailability struct {
+	Total int `json:"total"`
+	Free  int `json:"free"`
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
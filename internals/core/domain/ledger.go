@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+const (
+	LedgerUnpaid     = "unpaid"
+	LedgerSettlement = "settlement"
+	LedgerAdjustment = "adjustment"
+)
+
+// LedgerEntry is one movement on a vehicle's account. Unpaid fees are
+// positive amounts and settlements negative, so the balance is their sum.
+type LedgerEntry struct {
+	EntryId       int64     `json:"entryid"`
+	VehicleNumber string    `json:"vehiclenumber"`
+	TicketId      int64     `json:"ticketid"`
+	Kind          string    `json:"kind"`
+	Amount        Money     `json:"amount"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"createdat"`
+	// PaymentMethod and PaymentReference are set on settlements.
+	PaymentMethod    string `json:"paymentmethod"`
+	PaymentReference string `json:"paymentreference"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// LoginAttempts tracks recent failed logins for one username or client
+// address. Key is LoginUserKey or LoginAddressKey.
+type LoginAttempts struct {
+	Key         string     `json:"key"`
+	Failures    int        `json:"failures"`
+	LastFailure time.Time  `json:"lastfailure"`
+	LockedUntil *time.Time `json:"lockeduntil,omitempty"`
+}
+
+func LoginUserKey(username string) string {
+	return "user:" + username
+}
+
+func LoginAddressKey(address string) string {
+	return "ip:" + address
+}
+
+// Locked reports whether logins for the key are refused at now.
+func (a LoginAttempts) Locked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -0,0 +1,24 @@
+package domain
+
+// Lot is the site a deployment runs. Its tariffs, fees and balances are all
+// in its currency.
+type Lot struct {
+	Name     string   `json:"name"`
+	Currency Currency `json:"currency"`
+	Locale   string   `json:"locale"`
+	// HourlyRates is the tariff per slot type.
+	HourlyRates map[string]Money `json:"hourlyrates"`
+}
+
+// DefaultLot is an Indian lot charging ₹60 an hour for cars and ₹30 for
+// bikes.
+func DefaultLot() Lot {
+	return Lot{
+		Currency: CurrencyINR,
+		Locale:   "en-IN",
+		HourlyRates: map[string]Money{
+			"car":  NewMoney(6000, CurrencyINR),
+			"bike": NewMoney(3000, CurrencyINR),
+		},
+	}
+}
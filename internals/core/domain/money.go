@@ -0,0 +1,277 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidMoney     = errors.New("invalid money amount")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Currency is an ISO 4217 code.
+type Currency string
+
+const CurrencyINR Currency = "INR"
+
+// DefaultCurrency is used wherever an amount arrives without a currency. It
+// is set once at startup to the lot's currency.
+var DefaultCurrency = CurrencyINR
+
+// zeroDecimalCurrencies have no minor unit.
+var zeroDecimalCurrencies = map[Currency]bool{"JPY": true, "KRW": true, "VND": true}
+
+// Exponent is the number of decimal places in the currency's minor unit.
+func (c Currency) Exponent() int {
+	if zeroDecimalCurrencies[c] {
+		return 0
+	}
+	return 2
+}
+
+func (c Currency) scale() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.Exponent())), nil)
+}
+
+// Rounding says how an amount that falls between two minor units is
+// settled.
+type Rounding string
+
+const (
+	RoundHalfUp   Rounding = "half-up"
+	RoundHalfEven Rounding = "half-even"
+	RoundUp       Rounding = "up"
+	RoundDown     Rounding = "down"
+)
+
+func (mode Rounding) Valid() bool {
+	switch mode {
+	case RoundHalfUp, RoundHalfEven, RoundUp, RoundDown:
+		return true
+	}
+	return false
+}
+
+// round returns r as an integer. Half-up rounds ties away from zero, up and
+// down round towards positive and negative infinity.
+func (mode Rounding) round(r *big.Rat) int64 {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo.Int64()
+	}
+	away := big.NewInt(int64(rem.Sign()))
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	half := twice.Cmp(r.Denom())
+
+	switch mode {
+	case RoundUp:
+		if rem.Sign() > 0 {
+			quo.Add(quo, away)
+		}
+	case RoundDown:
+		if rem.Sign() < 0 {
+			quo.Add(quo, away)
+		}
+	case RoundHalfEven:
+		if half > 0 || (half == 0 && quo.Bit(0) == 1) {
+			quo.Add(quo, away)
+		}
+	default:
+		if half >= 0 {
+			quo.Add(quo, away)
+		}
+	}
+	return quo.Int64()
+}
+
+// Money is an amount in the minor unit of its currency, e.g. paise for INR.
+// The zero value has no currency and takes on the currency of whatever it
+// is added to.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func NewMoney(minor int64, currency Currency) Money {
+	return Money{Amount: minor, Currency: currency}
+}
+
+// ParseMoney reads a decimal amount such as "120.50". More decimal places
+// than the currency has is an error rather than being rounded away.
+func ParseMoney(value string, currency Currency) (Money, error) {
+	value = strings.TrimSpace(value)
+	r, ok := new(big.Rat).SetString(value)
+	if !ok || strings.ContainsAny(value, "eE/") {
+		return Money{}, ErrInvalidMoney
+	}
+	r.Mul(r, new(big.Rat).SetInt(currency.scale()))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return Money{}, ErrInvalidMoney
+	}
+	return Money{Amount: r.Num().Int64(), Currency: currency}, nil
+}
+
+// FromMajor converts a float such as 120.5 into money using mode.
+func FromMajor(value float64, currency Currency, mode Rounding) Money {
+	r := floatRat(value)
+	return Money{Currency: currency}.withRat(r.Mul(r, new(big.Rat).SetInt(currency.scale())), mode)
+}
+
+func (m Money) withRat(r *big.Rat, mode Rounding) Money {
+	return Money{Amount: mode.round(r), Currency: m.Currency}
+}
+
+// floatRat converts f via its shortest decimal form, so 0.09 is exactly
+// 9/100 rather than the nearest binary fraction.
+func floatRat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// CheckCurrency returns ErrCurrencyMismatch unless m and o can be added or
+// compared. An amount without a currency goes with any other.
+func (m Money) CheckCurrency(o Money) error {
+	if m.Currency != "" && o.Currency != "" && m.Currency != o.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return nil
+}
+
+func (m Money) currencyWith(o Money) Currency {
+	if err := m.CheckCurrency(o); err != nil {
+		panic(err)
+	}
+	if m.Currency == "" {
+		return o.Currency
+	}
+	return m.Currency
+}
+
+// Add returns m+o. Adding two different currencies is a programming error
+// and panics; convert first.
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: m.currencyWith(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: m.currencyWith(o)}
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Cmp returns -1, 0 or 1 as m is less than, equal to or greater than o.
+// Like Add it panics on different currencies, so amounts read from storage
+// or a request must go through CheckCurrency first.
+func (m Money) Cmp(o Money) int {
+	m.currencyWith(o)
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+// Mul scales m by factor, rounding the result to a minor unit with mode.
+func (m Money) Mul(factor float64, mode Rounding) Money {
+	return m.withRat(new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), floatRat(factor)), mode)
+}
+
+// Div divides m by divisor, rounding the result to a minor unit with mode.
+func (m Money) Div(divisor float64, mode Rounding) Money {
+	return m.withRat(new(big.Rat).Quo(new(big.Rat).SetInt64(m.Amount), floatRat(divisor)), mode)
+}
+
+// MulRat scales m by num/den without going through a float.
+func (m Money) MulRat(num, den int64, mode Rounding) Money {
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num)), big.NewInt(den))
+	return m.withRat(r, mode)
+}
+
+// Convert returns m in currency to, where rate is the number of units of to
+// for one unit of m's currency.
+func (m Money) Convert(to Currency, rate float64, mode Rounding) Money {
+	r := new(big.Rat).SetFrac(big.NewInt(m.Amount), m.Currency.scale())
+	r.Mul(r, floatRat(rate))
+	r.Mul(r, new(big.Rat).SetInt(to.scale()))
+	return Money{Amount: mode.round(r), Currency: to}
+}
+
+// Decimal formats m in major units, e.g. "120.50".
+func (m Money) Decimal() string {
+	exp := m.Currency.Exponent()
+	if m.Currency == "" {
+		exp = DefaultCurrency.Exponent()
+	}
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)).FloatString(exp)
+}
+
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+type moneyJSON struct {
+	Amount   json.Number `json:"amount"`
+	Currency Currency    `json:"currency"`
+}
+
+// MarshalJSON writes {"amount": "120.50", "currency": "INR"}. The amount is
+// a string so clients never see a binary float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return json.Marshal(struct {
+		Amount   string   `json:"amount"`
+		Currency Currency `json:"currency"`
+	}{m.Decimal(), currency})
+}
+
+// UnmarshalJSON accepts the object form written by MarshalJSON as well as a
+// bare number or string, which is taken to be in DefaultCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var body moneyJSON
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&body); err != nil {
+			return ErrInvalidMoney
+		}
+	} else if err := json.Unmarshal(data, &body.Amount); err != nil {
+		var text string
+		if json.Unmarshal(data, &text) != nil {
+			return ErrInvalidMoney
+		}
+		body.Amount = json.Number(text)
+	}
+	if body.Currency == "" {
+		body.Currency = DefaultCurrency
+	}
+	parsed, err := ParseMoney(body.Amount.String(), body.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	m, err := ParseMoney("120.5", CurrencyINR)
+	assert.NoError(t, err)
+	assert.Equal(t, NewMoney(12050, CurrencyINR), m)
+
+	m, err = ParseMoney("500", "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), m.Amount)
+
+	for _, bad := range []string{"", "abc", "1.005", "1e3", "1/2"} {
+		_, err = ParseMoney(bad, CurrencyINR)
+		assert.ErrorIs(t, err, ErrInvalidMoney, bad)
+	}
+}
+
+func TestMoneyRounding(t *testing.T) {
+	tests := []struct {
+		mode     Rounding
+		value    float64
+		expected int64
+	}{
+		{RoundHalfUp, 0.125, 13},
+		{RoundHalfUp, -0.125, -13},
+		{RoundHalfEven, 0.125, 12},
+		{RoundHalfEven, 0.135, 14},
+		{RoundUp, 0.121, 13},
+		{RoundDown, 0.129, 12},
+		{RoundDown, -0.121, -13},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			assert.Equal(t, tt.expected, FromMajor(tt.value, CurrencyINR, tt.mode).Amount)
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := NewMoney(10, CurrencyINR)
+	// Summing 0.1 ten times drifts with float64 but not in paise.
+	var total Money
+	for i := 0; i < 10; i++ {
+		total = total.Add(a)
+	}
+	assert.Equal(t, NewMoney(100, CurrencyINR), total)
+	assert.Equal(t, "1.00", total.Decimal())
+	assert.Equal(t, "-0.10", a.Neg().Decimal())
+	assert.Equal(t, 1, total.Cmp(a))
+
+	assert.Equal(t, NewMoney(1800, CurrencyINR), NewMoney(10000, CurrencyINR).Mul(0.18, RoundHalfUp))
+	assert.Equal(t, NewMoney(10000, CurrencyINR), NewMoney(11800, CurrencyINR).Div(1.18, RoundHalfUp))
+	assert.Equal(t, NewMoney(9000, CurrencyINR), NewMoney(6000, CurrencyINR).MulRat(3, 2, RoundHalfUp))
+
+	assert.PanicsWithError(t, "currency mismatch: INR and USD", func() {
+		a.Add(NewMoney(10, "USD"))
+	})
+	assert.NoError(t, a.CheckCurrency(NewMoney(10, CurrencyINR)))
+	assert.NoError(t, a.CheckCurrency(Money{}))
+	assert.ErrorIs(t, a.CheckCurrency(NewMoney(10, "USD")), ErrCurrencyMismatch)
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(NewMoney(14160, CurrencyINR))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"141.60","currency":"INR"}`, string(data))
+
+	var m Money
+	assert.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, NewMoney(14160, CurrencyINR), m)
+
+	assert.NoError(t, json.Unmarshal([]byte(`49.5`), &m))
+	assert.Equal(t, NewMoney(4950, CurrencyINR), m)
+
+	assert.NoError(t, json.Unmarshal([]byte(`"20"`), &m))
+	assert.Equal(t, NewMoney(2000, CurrencyINR), m)
+
+	assert.Error(t, json.Unmarshal([]byte(`0.001`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &m))
+}
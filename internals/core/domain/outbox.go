@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// OutboxMessage is an event stored in the same transaction as the change
+// it reports, waiting to be relayed. ID orders messages in the order they
+// were stored; the event keeps its own ID for consumers to drop repeats.
+type OutboxMessage struct {
+	ID          int64      `json:"id"`
+	Event       Event      `json:"event"`
+	CreatedAt   time.Time  `json:"createdat"`
+	PublishedAt *time.Time `json:"publishedat,omitempty"`
+}
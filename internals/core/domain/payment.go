@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+const (
+	PaymentCash = "cash"
+	PaymentCard = "card"
+)
+
+const (
+	PaymentAuthorised = "authorised"
+	PaymentCaptured   = "captured"
+	PaymentDeclined   = "declined"
+	PaymentRefunded   = "refunded"
+)
+
+// PaymentRequest is what the payer hands over at exit. CardToken is only
+// used by card gateways.
+type PaymentRequest struct {
+	TicketId  int64  `json:"ticketid"`
+	Amount    Money  `json:"amount"`
+	Method    string `json:"method"`
+	CardToken string `json:"cardtoken,omitempty"`
+}
+
+type Payment struct {
+	Reference      string    `json:"reference"`
+	TicketId       int64     `json:"ticketid"`
+	Method         string    `json:"method"`
+	Amount         Money     `json:"amount"`
+	RefundedAmount Money     `json:"refundedamount"`
+	Status         string    `json:"status"`
+	UpdatedAt      time.Time `json:"updatedat"`
+}
+
+// ExitQuote is the fee a vehicle would pay if it left now.
+type ExitQuote struct {
+	TicketId      int64     `json:"ticketid"`
+	VehicleNumber string    `json:"vehiclenumber"`
+	SlotId        int       `json:"slotid"`
+	EntryTime     time.Time `json:"entrytime"`
+	QuotedAt      time.Time `json:"quotedat"`
+	Fee           Money     `json:"fee"`
+	// Breakdown shows the tax included in Fee.
+	Breakdown FeeBreakdown `json:"breakdown"`
+}
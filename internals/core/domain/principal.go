@@ -0,0 +1,57 @@
+package domain
+
+import "context"
+
+// SystemActor is recorded for changes made without an authenticated user,
+// e.g. from start-up code.
+const SystemActor = "system"
+
+type userContextKey struct{}
+
+type clientAddressContextKey struct{}
+
+type requestIDContextKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user authenticated for ctx, if any.
+func UserFromContext(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(*User)
+	return user, ok && user != nil
+}
+
+// Actor names who is acting in ctx, for recording on changed records.
+func Actor(ctx context.Context) string {
+	if user, ok := UserFromContext(ctx); ok {
+		return user.Username
+	}
+	return SystemActor
+}
+
+// WithClientAddress returns a copy of ctx carrying the address the request
+// came from.
+func WithClientAddress(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, clientAddressContextKey{}, address)
+}
+
+// ClientAddress returns the address the request in ctx came from, or "" if
+// unknown.
+func ClientAddress(ctx context.Context) string {
+	address, _ := ctx.Value(clientAddressContextKey{}).(string)
+	return address
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being
+// served, for correlating audit entries and logs.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// RequestID returns the request ID in ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
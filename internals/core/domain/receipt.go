@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Receipt is the customer copy for a closed ticket.
+type Receipt struct {
+	LotName          string    `json:"lotname,omitempty"`
+	TicketId         int64     `json:"ticketid"`
+	VehicleNumber    string    `json:"vehiclenumber"`
+	SlotId           int       `json:"slotid"`
+	EntryTime        time.Time `json:"entrytime"`
+	ExitTime         time.Time `json:"exittime"`
+	NetFee           Money     `json:"netfee"`
+	TaxLines         []TaxLine `json:"taxlines"`
+	Tax              Money     `json:"tax"`
+	Total            Money     `json:"total"`
+	PaymentMethod    string    `json:"paymentmethod"`
+	PaymentReference string    `json:"paymentreference"`
+	// Formatted holds the amounts written for the lot's locale, keyed by
+	// netfee, tax, total and each tax line name.
+	Formatted map[string]string `json:"formatted"`
+}
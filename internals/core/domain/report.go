@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// RevenueReport summarises closed tickets and applied adjustments in
+// [From, To). GrossFees include tax; NetFees and TaxTotal split them.
+type RevenueReport struct {
+	From            time.Time        `json:"from"`
+	To              time.Time        `json:"to"`
+	TicketCount     int              `json:"ticketcount"`
+	Currency        Currency         `json:"currency"`
+	GrossFees       Money            `json:"grossfees"`
+	NetFees         Money            `json:"netfees"`
+	TaxTotal        Money            `json:"taxtotal"`
+	TaxSummary      map[string]Money `json:"taxsummary"`
+	Adjustments     Money            `json:"adjustments"`
+	NetRevenue      Money            `json:"netrevenue"`
+	ByPaymentMethod map[string]Money `json:"bypaymentmethod"`
+	// Base repeats the totals in the base currency of the exchange rate
+	// table, when one is configured.
+	Base *RevenueReport `json:"base,omitempty"`
+}
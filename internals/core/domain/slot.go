@@ -4,4 +4,6 @@ type Slot struct {
 	SlotId   int    `json:"slotid"`
 	SlotType string `json:"slottype"`
 	IsFree   bool   `json:"isfree"`
+	// UpdatedBy is the user who last added, occupied or freed the slot.
+	UpdatedBy string `json:"updatedby,omitempty"`
 }
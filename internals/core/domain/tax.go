@@ -0,0 +1,18 @@
+package domain
+
+// TaxLine is one tax charged on a fee. Rate is a fraction, e.g. 0.09.
+type TaxLine struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount Money   `json:"amount"`
+}
+
+// FeeBreakdown splits a fee into its pre-tax amount and tax lines. Total is
+// what the customer pays.
+type FeeBreakdown struct {
+	Net          Money     `json:"net"`
+	TaxLines     []TaxLine `json:"taxlines"`
+	Tax          Money     `json:"tax"`
+	Total        Money     `json:"total"`
+	TaxInclusive bool      `json:"taxinclusive"`
+}
@@ -7,4 +7,19 @@ type Ticket struct {
 	VehicleNumber string    `json:"vehiclenumber"`
 	SlotId        int       `json:"slotid"`
 	EntryTime     time.Time `json:"entrytime"`
+	FeeExempt     bool      `json:"feeexempt"`
+	ParkedBy      string    `json:"parkedby,omitempty"`
+	// OutstandingBalance is the vehicle's unpaid balance at entry. It is
+	// reported to the attendant but not stored with the ticket.
+	OutstandingBalance Money `json:"outstandingbalance"`
+
+	// Set when the ticket is closed at exit.
+	ExitTime         *time.Time `json:"exittime,omitempty"`
+	ClosedBy         string     `json:"closedby,omitempty"`
+	Fee              Money      `json:"fee"`
+	NetFee           Money      `json:"netfee"`
+	Tax              Money      `json:"tax"`
+	TaxLines         []TaxLine  `json:"taxlines,omitempty"`
+	PaymentMethod    string     `json:"paymentmethod,omitempty"`
+	PaymentReference string     `json:"paymentreference,omitempty"`
 }
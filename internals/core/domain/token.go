@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// TokenPair is what a successful login or refresh returns. The access token
+// authenticates requests until AccessExpiresAt; the refresh token is
+// exchanged for a new pair and can be used only once.
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	AccessExpiresAt  time.Time `json:"expiresat"`
+	RefreshToken     string    `json:"refreshtoken"`
+	RefreshExpiresAt time.Time `json:"refreshexpiresat"`
+}
+
+// StreamToken opens a live availability stream from a browser, whose
+// EventSource and WebSocket can't send an Authorization or X-API-Key
+// header. It goes in the token query parameter, expires within minutes and
+// grants nothing but availability:read.
+type StreamToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresat"`
+}
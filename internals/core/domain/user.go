@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+const (
+	RoleAdmin      = "admin"
+	RoleSupervisor = "supervisor"
+	RoleAttendant  = "attendant"
+	RoleAuditor    = "auditor"
+)
+
+// Permission names something a route lets a caller do. Routes ask for one
+// permission and a user is allowed through if their role grants it.
+type Permission string
+
+const (
+	PermParkingOperate     Permission = "parking:operate"
+	PermReceiptsRead       Permission = "receipts:read"
+	PermSlotsManage        Permission = "slots:manage"
+	PermVehicleListsRead   Permission = "vehiclelists:read"
+	PermVehicleListsManage Permission = "vehiclelists:manage"
+	PermLedgerManage       Permission = "ledger:manage"
+	PermAdjustmentsRequest Permission = "adjustments:request"
+	PermAdjustmentsReview  Permission = "adjustments:review"
+	PermReportsRead        Permission = "reports:read"
+	PermUsersManage        Permission = "users:manage"
+	PermAPIKeysManage      Permission = "apikeys:manage"
+	PermAuditRead          Permission = "audit:read"
+	PermOwnAccount         Permission = "account:own"
+	PermAvailabilityRead   Permission = "availability:read"
+	PermWebhooksManage     Permission = "webhooks:manage"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermParkingOperate, PermReceiptsRead, PermSlotsManage, PermVehicleListsRead, PermVehicleListsManage,
+		PermLedgerManage, PermAdjustmentsRequest, PermAdjustmentsReview, PermReportsRead, PermUsersManage,
+		PermAPIKeysManage, PermAuditRead, PermOwnAccount, PermAvailabilityRead, PermWebhooksManage,
+	},
+	RoleSupervisor: {
+		PermParkingOperate, PermReceiptsRead, PermSlotsManage, PermVehicleListsRead, PermVehicleListsManage,
+		PermLedgerManage, PermAdjustmentsRequest, PermAdjustmentsReview, PermReportsRead, PermOwnAccount,
+		PermAvailabilityRead,
+	},
+	RoleAttendant: {
+		PermParkingOperate, PermReceiptsRead, PermVehicleListsRead, PermAdjustmentsRequest, PermOwnAccount,
+		PermAvailabilityRead,
+	},
+	RoleAuditor: {
+		PermReceiptsRead, PermVehicleListsRead, PermReportsRead, PermAuditRead, PermOwnAccount,
+		PermAvailabilityRead,
+	},
+}
+
+// ValidRole reports whether role is one of the known roles.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RolePermissions returns the permissions granted to role.
+func RolePermissions(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
+
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	// PasswordHash is a bcrypt hash; the password itself is never stored.
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	Disabled     bool      `json:"disabled"`
+	CreatedAt    time.Time `json:"createdat"`
+	// TokenVersion is carried in the user's tokens. Changing or resetting
+	// the password bumps it, which invalidates every token issued before.
+	TokenVersion int `json:"-"`
+	// APIKey is set when the user is an API key's principal; ID is then
+	// the key's.
+	APIKey bool `json:"-"`
+	// Scopes, when set, replaces the role's permissions. Requests made with
+	// an API key act as a user scoped to the key.
+	Scopes []Permission `json:"-"`
+}
+
+// Can reports whether the user's role, or their scopes if they have any,
+// grant p. Disabled users can do nothing.
+func (u User) Can(p Permission) bool {
+	if u.Disabled {
+		return false
+	}
+	granted := rolePermissions[u.Role]
+	if u.Scopes != nil {
+		granted = u.Scopes
+	}
+	for _, granted := range granted {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
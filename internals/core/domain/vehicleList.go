@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+const (
+	BlockList = "block"
+	AllowList = "allow"
+)
+
+// VehicleListEntry puts a vehicle on the blocklist (entry refused) or the
+// allowlist (parks without a fee). A vehicle is on at most one list.
+type VehicleListEntry struct {
+	VehicleNumber string    `json:"vehiclenumber"`
+	ListType      string    `json:"listtype"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"createdat"`
+}
+
+// EntryRejection records a vehicle that was turned away at entry.
+type EntryRejection struct {
+	VehicleNumber string    `json:"vehiclenumber"`
+	Reason        string    `json:"reason"`
+	RejectedAt    time.Time `json:"rejectedat"`
+}
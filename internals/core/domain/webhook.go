@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Webhook delivery states. A delivery stays pending, retried with backoff,
+// until the receiver accepts it or it runs out of attempts and is dead.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// WebhookSubscription asks for events of the given types to be POSTed to
+// URL, signed with Secret.
+type WebhookSubscription struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventtypes"`
+	Secret     string    `json:"-"`
+	CreatedBy  string    `json:"createdby"`
+	CreatedAt  time.Time `json:"createdat"`
+}
+
+// Wants reports whether the subscription is for events of eventType.
+func (s WebhookSubscription) Wants(eventType string) bool {
+	return slices.Contains(s.EventTypes, eventType)
+}
+
+// WebhookDelivery is one event on its way to one subscription, with the
+// outcome of its last attempt. Payload is fixed when the delivery is
+// queued, so every attempt sends the same bytes.
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionid"`
+	EventID        string          `json:"eventid"`
+	EventType      string          `json:"eventtype"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextattemptat"`
+	LastAttemptAt  *time.Time      `json:"lastattemptat,omitempty"`
+	LastStatusCode int             `json:"laststatuscode,omitempty"`
+	LastError      string          `json:"lasterror,omitempty"`
+	CreatedAt      time.Time       `json:"createdat"`
+	DeliveredAt    *time.Time      `json:"deliveredat,omitempty"`
+}
+
+// WebhookDeliveryFilter selects deliveries. Empty fields match everything.
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	EventID        string
+	Status         string
+	// Limit caps how many deliveries are returned, newest first.
+	Limit int
+}
+
+func (f WebhookDeliveryFilter) Matches(d WebhookDelivery) bool {
+	return (f.SubscriptionID == "" || d.SubscriptionID == f.SubscriptionID) &&
+		(f.EventID == "" || d.EventID == f.EventID) &&
+		(f.Status == "" || d.Status == f.Status)
+}
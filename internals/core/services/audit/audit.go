@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Record appends an entry for a change that has already been made. A nil
+// log records nothing, and a failure to record is logged rather than
+// returned, since the change itself can't be undone.
+func Record(ctx context.Context, auditLog ports.AuditLog, action, target string, before, after any) {
+	RecordEntry(ctx, auditLog, domain.NewAuditEntry(ctx, action, target, before, after))
+}
+
+// RecordEntry is Record for an entry that has been filled in by hand, e.g.
+// with a Detail.
+func RecordEntry(ctx context.Context, auditLog ports.AuditLog, entry domain.AuditEntry) {
+	if auditLog == nil {
+		return
+	}
+	if err := auditLog.Record(ctx, entry); err != nil {
+		log.Printf("cannot record audit entry %s %s: %v", entry.Action, entry.Target, err)
+	}
+}
+
+// Service reads the audit log.
+type Service struct {
+	log ports.AuditLog
+}
+
+func NewService(auditLog ports.AuditLog) *Service {
+	return &Service{log: auditLog}
+}
+
+// Query returns the entries matching filter, newest first. Limit defaults
+// to DefaultLimit.
+func (s *Service) Query(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit < 0 || filter.Limit > MaxLimit {
+		return nil, ErrInvalidLimit
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return nil, ErrInvalidRange
+	}
+	entries, err := s.log.ListAuditEntries(ctx, filter)
+	if err != nil {
+		return nil, Wrap("failed to list audit entries", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidRange = errors.New("audit range must end after it starts")
+	ErrInvalidLimit = fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+)
+
+func Wrap(content string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", content, err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so keys are easy to recognise in
+// configuration and logs.
+const APIKeyPrefix = "pk_"
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", Wrap("failed to generate api key", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashAPIKeySecret hashes with SHA-256 rather than bcrypt: the secret is
+// random and long, and keys are checked on every request.
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIKeySecret sets a fresh secret on key and returns the full key to
+// hand to the client, "pk_<id>_<secret>".
+func newAPIKeySecret(key *domain.APIKey) (string, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+	key.SecretHash = hashAPIKeySecret(secret)
+	return APIKeyPrefix + key.ID + "_" + secret, nil
+}
+
+// expandScopes resolves named scopes such as "park-only" and checks that
+// every permission may be granted to a key.
+func expandScopes(scopes []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	seen := make(map[domain.Permission]bool)
+	for _, scope := range scopes {
+		expanded, ok := domain.APIKeyScopes[scope]
+		if !ok {
+			expanded = []domain.Permission{domain.Permission(scope)}
+		}
+		for _, p := range expanded {
+			if !domain.ValidAPIKeyScope(p) {
+				return nil, ErrInvalidScope
+			}
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+	if len(permissions) == 0 {
+		return nil, ErrInvalidScope
+	}
+	return permissions, nil
+}
+
+// CreateAPIKey creates a key with the given scopes. The returned key string
+// is the only time the secret is available.
+func (a *AuthServiceImpl) CreateAPIKey(ctx context.Context, name string, scopes []string) (*domain.APIKey, string, error) {
+	if a.APIKeys == nil {
+		return nil, "", ErrAPIKeysUnavailable
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", ErrAPIKeyNameRequired
+	}
+	permissions, err := expandScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := randomHex(6)
+	if err != nil {
+		return nil, "", err
+	}
+	key := domain.APIKey{
+		ID:        id,
+		Name:      name,
+		Scopes:    permissions,
+		CreatedBy: domain.Actor(ctx),
+		CreatedAt: time.Now(),
+	}
+	raw, err := newAPIKeySecret(&key)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := a.APIKeys.SaveAPIKey(ctx, key); err != nil {
+		return nil, "", Wrap("failed to save api key", err)
+	}
+	a.audit(ctx, domain.AuditAPIKeyCreate, apiKeyTarget(key.ID), nil, key)
+	return &key, raw, nil
+}
+
+func (a *AuthServiceImpl) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	if a.APIKeys == nil {
+		return nil, ErrAPIKeysUnavailable
+	}
+	keys, err := a.APIKeys.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, Wrap("failed to list api keys", err)
+	}
+	return keys, nil
+}
+
+// RotateAPIKey replaces a key's secret. The old secret stops working at
+// once; the key keeps its ID, name and scopes.
+func (a *AuthServiceImpl) RotateAPIKey(ctx context.Context, id string) (*domain.APIKey, string, error) {
+	key, err := a.findAPIKey(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.Revoked() {
+		return nil, "", ErrAPIKeyRevoked
+	}
+	before := *key
+	raw, err := newAPIKeySecret(key)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	key.RotatedAt = &now
+	if err := a.APIKeys.UpdateAPIKey(ctx, *key); err != nil {
+		return nil, "", Wrap("failed to update api key", err)
+	}
+	a.audit(ctx, domain.AuditAPIKeyRotate, apiKeyTarget(key.ID), before, key)
+	return key, raw, nil
+}
+
+// RevokeAPIKey disables a key for good. Revoked keys stay listed so their
+// history is kept.
+func (a *AuthServiceImpl) RevokeAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := a.findAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.Revoked() {
+		return key, nil
+	}
+	before := *key
+	now := time.Now()
+	key.RevokedAt = &now
+	if err := a.APIKeys.UpdateAPIKey(ctx, *key); err != nil {
+		return nil, Wrap("failed to update api key", err)
+	}
+	a.audit(ctx, domain.AuditAPIKeyRevoke, apiKeyTarget(key.ID), before, key)
+	return key, nil
+}
+
+// ValidateAPIKey returns the principal for a key presented by a client,
+// restricted to the key's scopes.
+func (a *AuthServiceImpl) ValidateAPIKey(ctx context.Context, raw string) (*domain.User, error) {
+	if a.APIKeys == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	id, secret, ok := strings.Cut(strings.TrimPrefix(raw, APIKeyPrefix), "_")
+	if !ok || !strings.HasPrefix(raw, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := a.APIKeys.FindAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, Wrap("failed to find api key", err)
+	}
+	if key == nil || key.Revoked() {
+		return nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKeySecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	return key.Principal(), nil
+}
+
+func (a *AuthServiceImpl) findAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	if a.APIKeys == nil {
+		return nil, ErrAPIKeysUnavailable
+	}
+	key, err := a.APIKeys.FindAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, Wrap("failed to find api key", err)
+	}
+	if key == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
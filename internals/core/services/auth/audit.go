@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/audit"
+)
+
+// audit records a change in Audit, if one is set.
+func (a *AuthServiceImpl) audit(ctx context.Context, action, target string, before, after any) {
+	audit.Record(ctx, a.Audit, action, target, before, after)
+}
+
+// auditDetail records an event that has no before or after value.
+func (a *AuthServiceImpl) auditDetail(ctx context.Context, action, target, detail string) {
+	entry := domain.NewAuditEntry(ctx, action, target, nil, nil)
+	entry.Detail = detail
+	audit.RecordEntry(ctx, a.Audit, entry)
+}
+
+// auditAs records an event for a caller who isn't signed in yet, naming
+// them as the actor.
+func (a *AuthServiceImpl) auditAs(ctx context.Context, actor, action, target string) {
+	entry := domain.NewAuditEntry(ctx, action, target, nil, nil)
+	entry.Actor = actor
+	audit.RecordEntry(ctx, a.Audit, entry)
+}
+
+func userTarget(id string) string {
+	return "user:" + id
+}
+
+func apiKeyTarget(id string) string {
+	return "apikey:" + id
+}
@@ -1,66 +1,289 @@
 package auth
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"os"
 	"parkingSlotManagement/internals/core/domain"
-	"strings"
+	"parkingSlotManagement/internals/ports"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	DefaultIssuer          = "parkingSlotManagement"
+	DefaultAudience        = "parking-api"
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	DefaultStreamTokenTTL  = time.Minute
 )
 
 type AuthService interface {
-	Login(username, password string) (string, error)
-	ValidateToken(token string) (*domain.Admin, error)
+	Login(ctx context.Context, username, password string) (domain.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (domain.TokenPair, error)
+	Logout(ctx context.Context, accessToken, refreshToken string) error
+	ValidateToken(ctx context.Context, token string) (*domain.User, error)
+	IssueStreamToken(ctx context.Context, principal domain.User) (domain.StreamToken, error)
+	ValidateStreamToken(ctx context.Context, token string) (*domain.User, error)
+
+	CreateUser(ctx context.Context, user domain.User, password string) (*domain.User, error)
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+	UpdateUser(ctx context.Context, id, role string, disabled bool) (*domain.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	ListUsers(ctx context.Context) ([]domain.User, error)
+	ChangePassword(ctx context.Context, id, current, next string) error
+	ResetPassword(ctx context.Context, id, next string) error
+
+	CreateAPIKey(ctx context.Context, name string, scopes []string) (*domain.APIKey, string, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RotateAPIKey(ctx context.Context, id string) (*domain.APIKey, string, error)
+	RevokeAPIKey(ctx context.Context, id string) (*domain.APIKey, error)
+	ValidateAPIKey(ctx context.Context, key string) (*domain.User, error)
+
+	JWKS() JWKS
+
+	ListLockouts(ctx context.Context) ([]domain.LoginAttempts, error)
+	Unlock(ctx context.Context, key string) error
 }
 
 type AuthServiceImpl struct {
-	admin     domain.Admin
-	secretKey string
+	users       ports.UserRepository
+	revocations ports.TokenRevocationStore
+
+	// Keys signs and verifies tokens.
+	Keys *KeySet
+
+	// APIKeys stores API keys for machine clients. Without it no API key
+	// is accepted.
+	APIKeys ports.APIKeyRepository
+	// LoginAttempts tracks failed logins. Without it logins aren't
+	// throttled.
+	LoginAttempts ports.LoginAttemptStore
+	// Audit records logins, lockouts and every change to users, passwords
+	// and API keys, if set.
+	Audit ports.AuditLog
+	// HashCost is the bcrypt cost for new password hashes.
+	HashCost int
+	// Issuer and Audience are written into every token and required of
+	// every token presented.
+	Issuer   string
+	Audience string
+	// AccessTokenTTL and RefreshTokenTTL are how long each kind of token is
+	// accepted for.
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// StreamTokenTTL is how long a stream token can be used to open an
+	// availability stream. A stream already open stays open.
+	StreamTokenTTL time.Duration
+	// After MaxLoginFailures failures for a username, or MaxAddressFailures
+	// from one client address, logins are locked for LockoutDuration.
+	// Before that each failure doubles the wait, starting at LoginBackoff.
+	MaxLoginFailures   int
+	MaxAddressFailures int
+	LoginBackoff       time.Duration
+	LockoutDuration    time.Duration
 }
 
-func NewAuthService() *AuthServiceImpl {
-	return &AuthServiceImpl{
-		admin: domain.Admin{
-			ID:       "admin-1",
-			Username: os.Getenv("ADMIN_USERNAME"),
-			Password: os.Getenv("ADMIN_PASSWORD"),
-		},
-		secretKey: os.Getenv("JWT_SECRET"),
+// NewAuthService signs tokens with HS256 using JWT_SECRET; set Keys to use
+// other keys. If JWT_SECRET is missing or too short it signs nothing until
+// Keys is set. JWT_ISSUER and JWT_AUDIENCE override the default issuer and
+// audience.
+func NewAuthService(users ports.UserRepository, revocations ports.TokenRevocationStore) *AuthServiceImpl {
+	keys, err := NewKeySet(NewHMACKey([]byte(os.Getenv("JWT_SECRET"))))
+	if err != nil {
+		keys = &KeySet{}
+	}
+	a := &AuthServiceImpl{
+		users:           users,
+		revocations:     revocations,
+		Keys:            keys,
+		HashCost:        bcrypt.DefaultCost,
+		Issuer:          DefaultIssuer,
+		Audience:        DefaultAudience,
+		AccessTokenTTL:  DefaultAccessTokenTTL,
+		RefreshTokenTTL: DefaultRefreshTokenTTL,
+		StreamTokenTTL:  DefaultStreamTokenTTL,
+
+		MaxLoginFailures:   DefaultMaxLoginFailures,
+		MaxAddressFailures: DefaultMaxAddressFailures,
+		LoginBackoff:       DefaultLoginBackoff,
+		LockoutDuration:    DefaultLockoutDuration,
 	}
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		a.Issuer = issuer
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		a.Audience = audience
+	}
+	return a
 }
 
-func (a *AuthServiceImpl) Login(username, password string) (string, error) {
-	if username != a.admin.Username || password != a.admin.Password {
-		return "", errors.New("invalid credentials")
+// Login checks a username and password. Repeated failures for a username
+// or from a client address are throttled and then locked out; see
+// ThrottledError.
+func (a *AuthServiceImpl) Login(ctx context.Context, username, password string) (domain.TokenPair, error) {
+	if err := a.checkThrottle(ctx, username); err != nil {
+		return domain.TokenPair{}, err
+	}
+	user, err := a.users.FindUserByUsername(ctx, username)
+	if err != nil {
+		return domain.TokenPair{}, Wrap("failed to find user", err)
+	}
+	hash := ""
+	if user != nil {
+		hash = user.PasswordHash
+	}
+	if !checkPassword(hash, password) || user.Disabled {
+		if err := a.recordLoginFailure(ctx, username); err != nil {
+			return domain.TokenPair{}, err
+		}
+		a.auditAs(ctx, username, domain.AuditLoginFailure, domain.LoginUserKey(username))
+		return domain.TokenPair{}, ErrInvalidCredentials
+	}
+	if err := a.clearLoginFailures(ctx, username); err != nil {
+		return domain.TokenPair{}, err
 	}
+	pair, err := a.issueTokens(*user)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	a.auditAs(ctx, user.Username, domain.AuditLoginSuccess, userTarget(user.ID))
+	return pair, nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"admin_id": a.admin.ID,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(),
-	})
+// Refresh exchanges a refresh token for a new pair. The old refresh token
+// is revoked, so each one works exactly once, even if it is sent twice at
+// the same time.
+func (a *AuthServiceImpl) Refresh(ctx context.Context, refreshToken string) (domain.TokenPair, error) {
+	claims, err := a.parseToken(ctx, refreshToken, refreshTokenType)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	user, err := a.activeUser(ctx, claims)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	if err := a.revoke(ctx, claims); err != nil {
+		return domain.TokenPair{}, err
+	}
+	pair, err := a.issueTokens(*user)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	a.auditAs(ctx, user.Username, domain.AuditTokenRefresh, userTarget(user.ID))
+	return pair, nil
+}
 
-	return token.SignedString([]byte(a.secretKey))
+// Logout revokes the access token and, if given, the refresh token issued
+// with it.
+func (a *AuthServiceImpl) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	claims, err := a.parseToken(ctx, accessToken, accessTokenType)
+	if err != nil {
+		return err
+	}
+	if err := a.revoke(ctx, claims); err != nil {
+		return err
+	}
+	if refreshToken != "" {
+		refresh, err := a.parseToken(ctx, refreshToken, refreshTokenType)
+		if err != nil {
+			return err
+		}
+		if refresh.Subject != claims.Subject {
+			return ErrInvalidToken
+		}
+		if err := a.revoke(ctx, refresh); err != nil {
+			return err
+		}
+	}
+	a.audit(ctx, domain.AuditTokenLogout, userTarget(claims.Subject), nil, nil)
+	return nil
 }
 
-func (a *AuthServiceImpl) ValidateToken(tokenStr string) (*domain.Admin, error) {
-	tokenStr = strings.TrimSpace(tokenStr)
+// ValidateToken returns the user an access token was issued to. The role is
+// read from the user record rather than the token, so role changes and
+// disabled accounts take effect immediately, and a token issued before a
+// password change is refused.
+func (a *AuthServiceImpl) ValidateToken(ctx context.Context, tokenStr string) (*domain.User, error) {
+	claims, err := a.parseToken(ctx, tokenStr, accessTokenType)
+	if err != nil {
+		return nil, err
+	}
+	return a.activeUser(ctx, claims)
+}
 
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		return []byte(a.secretKey), nil
-	})
+// IssueStreamToken returns a short-lived token for principal to open an
+// availability stream with.
+func (a *AuthServiceImpl) IssueStreamToken(ctx context.Context, principal domain.User) (domain.StreamToken, error) {
+	if !principal.Can(domain.PermAvailabilityRead) {
+		return domain.StreamToken{}, ErrInvalidToken
+	}
+	token, expiresAt, err := a.signToken(principal, streamTokenType, time.Now(), a.StreamTokenTTL)
+	if err != nil {
+		return domain.StreamToken{}, err
+	}
+	return domain.StreamToken{Token: token, ExpiresAt: expiresAt}, nil
+}
 
+// ValidateStreamToken returns the principal a stream token was issued to,
+// scoped to availability:read whatever it could do when it asked. The user
+// or API key is looked up as for an access token, so one that has since
+// been deleted, disabled or revoked, or lost availability:read, is refused.
+func (a *AuthServiceImpl) ValidateStreamToken(ctx context.Context, tokenStr string) (*domain.User, error) {
+	claims, err := a.parseToken(ctx, tokenStr, streamTokenType)
 	if err != nil {
-		fmt.Println("Token parse error:", err)
-		return nil, errors.New("invalid token")
+		return nil, err
+	}
+	var principal *domain.User
+	if claims.APIKey {
+		principal, err = a.activeAPIKey(ctx, claims.Subject)
+	} else {
+		principal, err = a.activeUser(ctx, claims)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if !principal.Can(domain.PermAvailabilityRead) {
+		return nil, ErrInvalidToken
+	}
+	principal.Scopes = []domain.Permission{domain.PermAvailabilityRead}
+	return principal, nil
+}
 
-	if !token.Valid {
-		fmt.Println("Token is not valid")
-		return nil, errors.New("invalid token")
+// activeUser returns the user a token was issued to, if they still exist,
+// are enabled and haven't changed their password since.
+func (a *AuthServiceImpl) activeUser(ctx context.Context, claims *tokenClaims) (*domain.User, error) {
+	user, err := a.users.FindUserByID(ctx, claims.Subject)
+	if err != nil {
+		return nil, Wrap("failed to find user", err)
 	}
+	if user == nil || user.Disabled {
+		return nil, ErrInvalidToken
+	}
+	if user.TokenVersion != claims.Version {
+		return nil, ErrTokenRevoked
+	}
+	return user, nil
+}
+
+// activeAPIKey returns the principal of the API key a stream token was
+// issued to, if the key still exists and hasn't been revoked.
+func (a *AuthServiceImpl) activeAPIKey(ctx context.Context, id string) (*domain.User, error) {
+	if a.APIKeys == nil {
+		return nil, ErrInvalidToken
+	}
+	key, err := a.APIKeys.FindAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, Wrap("failed to find api key", err)
+	}
+	if key == nil || key.Revoked() {
+		return nil, ErrInvalidToken
+	}
+	return key.Principal(), nil
+}
 
-	return &a.admin, nil
+// JWKS returns the public keys tokens may be signed with, for other
+// services to verify them.
+func (a *AuthServiceImpl) JWKS() JWKS {
+	return a.Keys.JWKS()
 }
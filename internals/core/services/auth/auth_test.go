@@ -1,44 +1,71 @@
 package auth
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"os"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
 )
 
+var ctx = context.Background()
+
 func setupEnv() {
-	os.Setenv("ADMIN_USERNAME", "admin")
-	os.Setenv("ADMIN_PASSWORD", "password")
-	os.Setenv("JWT_SECRET", "mysecretkey")
+	os.Setenv("JWT_SECRET", "mysecretkey-mysecretkey-mysecretkey")
 }
-func TestLogin_Success(t *testing.T) {
+
+// newTestService returns a service whose only user is the admin "admin"
+// with password "password".
+func newTestService(t *testing.T) *AuthServiceImpl {
 	setupEnv()
-	authService := NewAuthService()
+	authService := NewAuthService(inmemmory.NewUserInMemmory(), inmemmory.NewRevocationInMemmory())
+	authService.HashCost = bcrypt.MinCost
+	if _, err := authService.Bootstrap(ctx, "admin", "password"); err != nil {
+		t.Fatalf("Bootstrap failed: %v", err)
+	}
+	return authService
+}
+func TestLogin_Success(t *testing.T) {
+	authService := newTestService(t)
 
-	token, err := authService.Login("admin", "password")
+	pair, err := authService.Login(ctx, "admin", "password")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
-	if token == "" {
-		t.Error("Expected a token, got empty string")
+	if pair.AccessToken == "" || pair.RefreshToken == "" {
+		t.Error("Expected access and refresh tokens, got empty string")
+	}
+	if !pair.AccessExpiresAt.Before(pair.RefreshExpiresAt) {
+		t.Errorf("Expected the access token to expire before the refresh token, got %v and %v", pair.AccessExpiresAt, pair.RefreshExpiresAt)
 	}
 }
 
 func TestLogin_Failure(t *testing.T) {
-	setupEnv()
-	authService := NewAuthService()
+	authService := newTestService(t)
 
-	_, err := authService.Login("wronguser", "wrongpass")
+	_, err := authService.Login(ctx, "wronguser", "wrongpass")
 	if err == nil {
 		t.Error("Expected error for invalid credentials, got nil")
 	}
 }
 
 func TestValidateToken_Success(t *testing.T) {
-	setupEnv()
-	authService := NewAuthService()
+	authService := newTestService(t)
 
-	token, _ := authService.Login("admin", "password")
-	admin, err := authService.ValidateToken(token)
+	pair, _ := authService.Login(ctx, "admin", "password")
+	admin, err := authService.ValidateToken(ctx, pair.AccessToken)
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -49,13 +76,658 @@ func TestValidateToken_Success(t *testing.T) {
 }
 
 func TestValidateToken_Failure(t *testing.T) {
-	setupEnv()
-	authService := NewAuthService()
+	authService := newTestService(t)
 
 	invalidToken := "invalid.token.string"
-	_, err := authService.ValidateToken(invalidToken)
+	_, err := authService.ValidateToken(ctx, invalidToken)
 
 	if err == nil {
 		t.Error("Expected error for invalid token, got nil")
 	}
 }
+
+func TestValidateToken_ReflectsUserChanges(t *testing.T) {
+	authService := newTestService(t)
+
+	attendant, err := authService.CreateUser(ctx, domain.User{Username: "ravi", Role: domain.RoleAttendant}, "ravi-pass")
+	if err != nil {
+		t.Fatalf("Expected no error creating user, got %v", err)
+	}
+	pair, err := authService.Login(ctx, "ravi", "ravi-pass")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	token := pair.AccessToken
+	user, err := authService.ValidateToken(ctx, token)
+	if err != nil || user.Role != domain.RoleAttendant {
+		t.Fatalf("Expected attendant, got %+v, %v", user, err)
+	}
+	if user.Can(domain.PermUsersManage) || !user.Can(domain.PermParkingOperate) {
+		t.Errorf("Expected attendant to park but not manage users")
+	}
+
+	if _, err := authService.UpdateUser(ctx, attendant.ID, domain.RoleSupervisor, false); err != nil {
+		t.Fatalf("Expected no error updating user, got %v", err)
+	}
+	user, _ = authService.ValidateToken(ctx, token)
+	if user == nil || user.Role != domain.RoleSupervisor {
+		t.Errorf("Expected the new role to apply to an existing token, got %+v", user)
+	}
+
+	if _, err := authService.UpdateUser(ctx, attendant.ID, domain.RoleSupervisor, true); err != nil {
+		t.Fatalf("Expected no error disabling user, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a disabled user, got %v", err)
+	}
+	if _, err := authService.Login(ctx, "ravi", "ravi-pass"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected disabled user to be refused login, got %v", err)
+	}
+}
+
+func TestCreateUser_Validation(t *testing.T) {
+	authService := newTestService(t)
+
+	tests := []struct {
+		name     string
+		user     domain.User
+		password string
+		want     error
+	}{
+		{"missing username", domain.User{Role: domain.RoleAuditor}, "password1", ErrUsernameRequired},
+		{"missing password", domain.User{Username: "ravi", Role: domain.RoleAuditor}, "", ErrPasswordRequired},
+		{"short password", domain.User{Username: "ravi", Role: domain.RoleAuditor}, "short", ErrPasswordTooShort},
+		{"unknown role", domain.User{Username: "ravi", Role: "owner"}, "password1", ErrInvalidRole},
+		{"duplicate username", domain.User{Username: "admin", Role: domain.RoleAuditor}, "password1", ErrUsernameTaken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := authService.CreateUser(ctx, tt.user, tt.password); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLastAdminIsProtected(t *testing.T) {
+	authService := newTestService(t)
+
+	users, _ := authService.ListUsers(ctx)
+	if len(users) != 1 || users[0].Role != domain.RoleAdmin {
+		t.Fatalf("Expected the bootstrap admin, got %+v", users)
+	}
+	admin := users[0]
+
+	if err := authService.DeleteUser(ctx, admin.ID); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("Expected ErrLastAdmin deleting the only admin, got %v", err)
+	}
+	if _, err := authService.UpdateUser(ctx, admin.ID, domain.RoleAuditor, false); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("Expected ErrLastAdmin demoting the only admin, got %v", err)
+	}
+
+	if _, err := authService.CreateUser(ctx, domain.User{Username: "second", Role: domain.RoleAdmin}, "second-pass"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := authService.DeleteUser(ctx, admin.ID); err != nil {
+		t.Errorf("Expected an admin to be removable once another exists, got %v", err)
+	}
+	if err := authService.DeleteUser(ctx, admin.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestPasswordsAreHashed(t *testing.T) {
+	authService := newTestService(t)
+
+	users, _ := authService.ListUsers(ctx)
+	if users[0].PasswordHash == "password" || bcrypt.CompareHashAndPassword([]byte(users[0].PasswordHash), []byte("password")) != nil {
+		t.Errorf("Expected a bcrypt hash of the password, got %q", users[0].PasswordHash)
+	}
+}
+
+func TestBootstrap_OnlyOnce(t *testing.T) {
+	authService := newTestService(t)
+
+	if _, err := authService.Bootstrap(ctx, "another", "password"); !errors.Is(err, ErrAlreadyBootstrapped) {
+		t.Errorf("Expected ErrAlreadyBootstrapped, got %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	authService := newTestService(t)
+	users, _ := authService.ListUsers(ctx)
+	id := users[0].ID
+	before, _ := authService.Login(ctx, "admin", "password")
+
+	if err := authService.ChangePassword(ctx, id, "wrong-password", "new-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for a wrong current password, got %v", err)
+	}
+	if err := authService.ChangePassword(ctx, id, "password", "short"); !errors.Is(err, ErrPasswordTooShort) {
+		t.Errorf("Expected ErrPasswordTooShort, got %v", err)
+	}
+	if err := authService.ChangePassword(ctx, id, "password", "new-password"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.Login(ctx, "admin", "password"); err == nil {
+		t.Error("Expected the old password to stop working")
+	}
+	after, err := authService.Login(ctx, "admin", "new-password")
+	if err != nil {
+		t.Fatalf("Expected the new password to work, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, before.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected an access token from before the change to be revoked, got %v", err)
+	}
+	if _, err := authService.Refresh(ctx, before.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected a refresh token from before the change to be revoked, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, after.AccessToken); err != nil {
+		t.Errorf("Expected a token issued after the change to work, got %v", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	authService := newTestService(t)
+	user, _ := authService.CreateUser(ctx, domain.User{Username: "ravi", Role: domain.RoleAttendant}, "ravi-pass")
+
+	if err := authService.ResetPassword(ctx, "missing", "new-password"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+	before, _ := authService.Login(ctx, "ravi", "ravi-pass")
+	if err := authService.ResetPassword(ctx, user.ID, "new-password"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, before.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected the user's tokens to be revoked by a reset, got %v", err)
+	}
+	if _, err := authService.Login(ctx, "ravi", "new-password"); err != nil {
+		t.Errorf("Expected the reset password to work, got %v", err)
+	}
+}
+
+func TestRefresh_RotatesRefreshToken(t *testing.T) {
+	authService := newTestService(t)
+	pair, _ := authService.Login(ctx, "admin", "password")
+
+	if _, err := authService.Refresh(ctx, pair.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected an access token to be refused as a refresh token, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, pair.RefreshToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a refresh token to be refused as an access token, got %v", err)
+	}
+
+	next, err := authService.Refresh(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, next.AccessToken); err != nil {
+		t.Errorf("Expected the refreshed access token to be valid, got %v", err)
+	}
+	if _, err := authService.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected a used refresh token to be refused, got %v", err)
+	}
+}
+
+func TestRefresh_ConcurrentReuse(t *testing.T) {
+	authService := newTestService(t)
+	pair, _ := authService.Login(ctx, "admin", "password")
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var succeeded int
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := authService.Refresh(ctx, pair.RefreshToken)
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrTokenRevoked) {
+				t.Errorf("Expected a reused refresh token to be refused as revoked, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if succeeded != 1 {
+		t.Errorf("Expected exactly one refresh to succeed, got %d", succeeded)
+	}
+}
+
+func TestLogout_RevokesTokens(t *testing.T) {
+	authService := newTestService(t)
+	pair, _ := authService.Login(ctx, "admin", "password")
+	other, _ := authService.Login(ctx, "admin", "password")
+
+	if err := authService.Logout(ctx, pair.AccessToken, pair.RefreshToken); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, pair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected the access token to be revoked, got %v", err)
+	}
+	if _, err := authService.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected the refresh token to be revoked, got %v", err)
+	}
+	if _, err := authService.ValidateToken(ctx, other.AccessToken); err != nil {
+		t.Errorf("Expected other sessions to stay logged in, got %v", err)
+	}
+}
+
+func TestStreamToken(t *testing.T) {
+	authService := newTestService(t)
+	pair, _ := authService.Login(ctx, "admin", "password")
+	admin, _ := authService.ValidateToken(ctx, pair.AccessToken)
+
+	stream, err := authService.IssueStreamToken(ctx, *admin)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !stream.ExpiresAt.Before(pair.AccessExpiresAt) {
+		t.Errorf("Expected the stream token to expire before the access token, got %v and %v", stream.ExpiresAt, pair.AccessExpiresAt)
+	}
+	principal, err := authService.ValidateStreamToken(ctx, stream.Token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if principal.Username != "admin" || !principal.Can(domain.PermAvailabilityRead) || principal.Can(domain.PermParkingOperate) {
+		t.Errorf("Expected admin scoped to availability:read, got %+v", principal)
+	}
+
+	if _, err := authService.ValidateToken(ctx, stream.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a stream token to be refused as an access token, got %v", err)
+	}
+	if _, err := authService.ValidateStreamToken(ctx, pair.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected an access token to be refused as a stream token, got %v", err)
+	}
+	if _, err := authService.IssueStreamToken(ctx, domain.User{ID: "k1", Username: "apikey:till", Scopes: []domain.Permission{domain.PermParkingOperate}}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected no stream token without availability:read, got %v", err)
+	}
+}
+
+func TestStreamToken_ChecksPrincipal(t *testing.T) {
+	authService := newTestService(t)
+	authService.APIKeys = inmemmory.NewAPIKeyInMemmory()
+	adminCtx := domain.WithUser(ctx, &domain.User{Username: "admin", Role: domain.RoleAdmin})
+	user, _ := authService.CreateUser(adminCtx, domain.User{Username: "ravi", Role: domain.RoleAttendant}, "ravi-pass")
+
+	issue := func(principal *domain.User) string {
+		stream, err := authService.IssueStreamToken(ctx, *principal)
+		if err != nil {
+			t.Fatalf("IssueStreamToken failed: %v", err)
+		}
+		return stream.Token
+	}
+
+	disabled := issue(user)
+	authService.UpdateUser(adminCtx, user.ID, domain.RoleAttendant, true)
+	if _, err := authService.ValidateStreamToken(ctx, disabled); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a disabled user's stream token to be refused, got %v", err)
+	}
+	authService.UpdateUser(adminCtx, user.ID, domain.RoleAttendant, false)
+	promoted := issue(user)
+	authService.UpdateUser(adminCtx, user.ID, domain.RoleSupervisor, false)
+	if principal, err := authService.ValidateStreamToken(ctx, promoted); err != nil || principal.Username != "ravi" {
+		t.Errorf("Expected ravi's stream token to work after a role change that keeps availability:read, got %+v, %v", principal, err)
+	}
+	deleted := issue(user)
+	authService.DeleteUser(adminCtx, user.ID)
+	if _, err := authService.ValidateStreamToken(ctx, deleted); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a deleted user's stream token to be refused, got %v", err)
+	}
+
+	key, raw, _ := authService.CreateAPIKey(adminCtx, "display-1", []string{"display"})
+	device, _ := authService.ValidateAPIKey(ctx, raw)
+	revoked := issue(device)
+	if principal, err := authService.ValidateStreamToken(ctx, revoked); err != nil || principal.Username != "apikey:display-1" {
+		t.Errorf("Expected the key's stream token to work, got %+v, %v", principal, err)
+	}
+	authService.RevokeAPIKey(adminCtx, key.ID)
+	if _, err := authService.ValidateStreamToken(ctx, revoked); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a revoked key's stream token to be refused, got %v", err)
+	}
+}
+
+func TestValidateToken_ChecksClaims(t *testing.T) {
+	authService := newTestService(t)
+	users, _ := authService.ListUsers(ctx)
+	admin := users[0]
+
+	sign := func(claims tokenClaims, method jwt.SigningMethod, key any) string {
+		signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return signed
+	}
+	valid := func() tokenClaims {
+		return tokenClaims{Type: accessTokenType, RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Subject:   admin.ID,
+			Issuer:    authService.Issuer,
+			Audience:  jwt.ClaimStrings{authService.Audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}}
+	}
+	secret := []byte("mysecretkey-mysecretkey-mysecretkey")
+
+	if _, err := authService.ValidateToken(ctx, sign(valid(), jwt.SigningMethodHS256, secret)); err != nil {
+		t.Fatalf("Expected a well-formed token to be valid, got %v", err)
+	}
+
+	wrongIssuer := valid()
+	wrongIssuer.Issuer = "someone-else"
+	wrongAudience := valid()
+	wrongAudience.Audience = jwt.ClaimStrings{"another-api"}
+	expired := valid()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	noExpiry := valid()
+	noExpiry.ExpiresAt = nil
+	unknownUser := valid()
+	unknownUser.Subject = "user-unknown"
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong issuer", sign(wrongIssuer, jwt.SigningMethodHS256, secret)},
+		{"wrong audience", sign(wrongAudience, jwt.SigningMethodHS256, secret)},
+		{"expired", sign(expired, jwt.SigningMethodHS256, secret)},
+		{"no expiry", sign(noExpiry, jwt.SigningMethodHS256, secret)},
+		{"unknown user", sign(unknownUser, jwt.SigningMethodHS256, secret)},
+		{"wrong algorithm", sign(valid(), jwt.SigningMethodHS512, secret)},
+		{"wrong key", sign(valid(), jwt.SigningMethodHS256, []byte("other-key"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := authService.ValidateToken(ctx, tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	authService := newTestService(t)
+	authService.APIKeys = inmemmory.NewAPIKeyInMemmory()
+	adminCtx := domain.WithUser(ctx, &domain.User{Username: "admin", Role: domain.RoleAdmin})
+
+	if _, _, err := authService.CreateAPIKey(adminCtx, "gate-1", []string{"users:manage"}); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("Expected ErrInvalidScope for users:manage, got %v", err)
+	}
+	if _, _, err := authService.CreateAPIKey(adminCtx, "gate-1", nil); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("Expected ErrInvalidScope without scopes, got %v", err)
+	}
+	if _, _, err := authService.CreateAPIKey(adminCtx, " ", []string{"park-only"}); !errors.Is(err, ErrAPIKeyNameRequired) {
+		t.Errorf("Expected ErrAPIKeyNameRequired, got %v", err)
+	}
+
+	key, raw, err := authService.CreateAPIKey(adminCtx, "gate-1", []string{"park-only"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if key.CreatedBy != "admin" || key.SecretHash == "" || strings.Contains(raw, key.SecretHash) {
+		t.Errorf("Expected a hashed key created by admin, got %+v", key)
+	}
+
+	principal, err := authService.ValidateAPIKey(ctx, raw)
+	if err != nil {
+		t.Fatalf("Expected the key to be valid, got %v", err)
+	}
+	if !principal.Can(domain.PermParkingOperate) || principal.Can(domain.PermReportsRead) || principal.Username != "apikey:gate-1" {
+		t.Errorf("Expected a park-only principal, got %+v", principal)
+	}
+	if _, err := authService.ValidateAPIKey(ctx, raw+"0"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey for a wrong secret, got %v", err)
+	}
+
+	rotated, newRaw, err := authService.RotateAPIKey(ctx, key.ID)
+	if err != nil || rotated.RotatedAt == nil {
+		t.Fatalf("RotateAPIKey failed: %v", err)
+	}
+	if _, err := authService.ValidateAPIKey(ctx, raw); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected the old secret to stop working, got %v", err)
+	}
+	if _, err := authService.ValidateAPIKey(ctx, newRaw); err != nil {
+		t.Errorf("Expected the rotated key to be valid, got %v", err)
+	}
+
+	if _, err := authService.RevokeAPIKey(ctx, key.ID); err != nil {
+		t.Fatalf("RevokeAPIKey failed: %v", err)
+	}
+	if _, err := authService.ValidateAPIKey(ctx, newRaw); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected a revoked key to be rejected, got %v", err)
+	}
+	if _, _, err := authService.RotateAPIKey(ctx, key.ID); !errors.Is(err, ErrAPIKeyRevoked) {
+		t.Errorf("Expected ErrAPIKeyRevoked rotating a revoked key, got %v", err)
+	}
+	if _, err := authService.RevokeAPIKey(ctx, "missing"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+	}
+	keys, _ := authService.ListAPIKeys(ctx)
+	if len(keys) != 1 || !keys[0].Revoked() {
+		t.Errorf("Expected the revoked key to stay listed, got %+v", keys)
+	}
+}
+
+func TestLogin_Throttling(t *testing.T) {
+	authService := newTestService(t)
+	authService.LoginAttempts = inmemmory.NewLoginAttemptInMemmory()
+	audit := inmemmory.NewAuditInMemmory()
+	authService.Audit = audit
+	authService.LoginBackoff = time.Hour
+	fromGate := domain.WithClientAddress(ctx, "10.0.0.7")
+
+	if _, err := authService.Login(fromGate, "admin", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	_, err := authService.Login(fromGate, "admin", "password")
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) || !errors.Is(err, ErrTooManyAttempts) || throttled.RetryAfter <= 0 {
+		t.Fatalf("Expected to be told to back off, got %v", err)
+	}
+
+	authService.LoginBackoff = 0
+	for i := 1; i < authService.MaxLoginFailures; i++ {
+		authService.Login(fromGate, "admin", "wrong")
+	}
+	if _, err := authService.Login(fromGate, "admin", "password"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Expected the account to be locked even with the right password, got %v", err)
+	}
+	if _, err := authService.Login(domain.WithClientAddress(ctx, "10.0.0.8"), "admin", "password"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Expected the lockout to apply from any address, got %v", err)
+	}
+	entries, _ := audit.ListAuditEntries(ctx, domain.AuditFilter{Action: domain.AuditLoginLockout})
+	if len(entries) != 1 || entries[0].Action != domain.AuditLoginLockout || entries[0].Target != "user:admin" || entries[0].Address != "10.0.0.7" {
+		t.Errorf("Expected one lockout audit entry, got %+v", entries)
+	}
+
+	lockouts, _ := authService.ListLockouts(ctx)
+	if len(lockouts) != 1 || lockouts[0].Key != "user:admin" {
+		t.Fatalf("Expected the admin to be listed as locked, got %+v", lockouts)
+	}
+	adminCtx := domain.WithUser(ctx, &domain.User{Username: "root", Role: domain.RoleAdmin})
+	if err := authService.Unlock(adminCtx, "user:admin"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if err := authService.Unlock(adminCtx, "user:admin"); !errors.Is(err, ErrNotLocked) {
+		t.Errorf("Expected ErrNotLocked unlocking twice, got %v", err)
+	}
+	if entries := audit.Entries(); entries[len(entries)-1].Action != domain.AuditLoginUnlock || entries[len(entries)-1].Actor != "root" {
+		t.Errorf("Expected the unlock to be audited, got %+v", entries)
+	}
+	if _, err := authService.Login(fromGate, "admin", "password"); err != nil {
+		t.Errorf("Expected login to work after unlocking, got %v", err)
+	}
+}
+
+func TestLogin_AddressLockout(t *testing.T) {
+	authService := newTestService(t)
+	authService.LoginAttempts = inmemmory.NewLoginAttemptInMemmory()
+	authService.LoginBackoff = 0
+	authService.MaxAddressFailures = 3
+	fromGate := domain.WithClientAddress(ctx, "10.0.0.7")
+
+	for _, username := range []string{"ravi", "meena", "arjun"} {
+		authService.Login(fromGate, username, "guess")
+	}
+	if _, err := authService.Login(fromGate, "admin", "password"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Expected the address to be locked, got %v", err)
+	}
+	if _, err := authService.Login(domain.WithClientAddress(ctx, "10.0.0.8"), "admin", "password"); err != nil {
+		t.Errorf("Expected other addresses to log in, got %v", err)
+	}
+}
+
+func TestAuditTrail(t *testing.T) {
+	authService := newTestService(t)
+	audit := inmemmory.NewAuditInMemmory()
+	authService.Audit = audit
+	authService.APIKeys = inmemmory.NewAPIKeyInMemmory()
+	adminCtx := domain.WithRequestID(domain.WithUser(ctx, &domain.User{Username: "admin", Role: domain.RoleAdmin}), "req-1")
+
+	authService.Login(ctx, "admin", "wrong")
+	authService.Login(ctx, "admin", "password")
+	user, err := authService.CreateUser(adminCtx, domain.User{Username: "bob", Role: domain.RoleAttendant}, "password")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	authService.UpdateUser(adminCtx, user.ID, domain.RoleAuditor, false)
+	authService.ResetPassword(adminCtx, user.ID, "password2")
+	authService.DeleteUser(adminCtx, user.ID)
+	key, _, _ := authService.CreateAPIKey(adminCtx, "gate", []string{"park-only"})
+	authService.RevokeAPIKey(adminCtx, key.ID)
+
+	entries := audit.Entries()
+	want := []string{
+		domain.AuditLoginFailure, domain.AuditLoginSuccess,
+		domain.AuditUserCreate, domain.AuditUserUpdate, domain.AuditPasswordReset, domain.AuditUserDelete,
+		domain.AuditAPIKeyCreate, domain.AuditAPIKeyRevoke,
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d audit entries, got %+v", len(want), entries)
+	}
+	for i, action := range want {
+		if entries[i].Action != action {
+			t.Errorf("Expected entry %d to be %s, got %s", i, action, entries[i].Action)
+		}
+	}
+	if entries[1].Actor != "admin" || !strings.HasPrefix(entries[1].Target, "user:user-") {
+		t.Errorf("Expected the login to name the admin, got %+v", entries[1])
+	}
+	update := entries[3]
+	if update.Actor != "admin" || update.RequestID != "req-1" || update.Target != "user:"+user.ID {
+		t.Errorf("Expected the update to record actor, request and target, got %+v", update)
+	}
+	if !strings.Contains(string(update.Before), `"role":"attendant"`) || !strings.Contains(string(update.After), `"role":"auditor"`) {
+		t.Errorf("Expected before and after roles, got %s and %s", update.Before, update.After)
+	}
+	for _, entry := range entries {
+		if strings.Contains(string(entry.Before)+string(entry.After), "$2a$") || strings.Contains(string(entry.Before)+string(entry.After), key.SecretHash) {
+			t.Errorf("Expected no secrets in the audit log, got %+v", entry)
+		}
+	}
+}
+
+func TestHMACSecretMustBeLongEnough(t *testing.T) {
+	defer setupEnv()
+	for secret, want := range map[string]error{"": ErrNoSigningKey, "short-secret": ErrWeakSecret} {
+		os.Setenv("JWT_SECRET", secret)
+		if _, err := KeySetFromEnv(); !errors.Is(err, want) {
+			t.Errorf("Expected %v for JWT_SECRET %q, got %v", want, secret, err)
+		}
+
+		authService := NewAuthService(inmemmory.NewUserInMemmory(), inmemmory.NewRevocationInMemmory())
+		authService.HashCost = bcrypt.MinCost
+		authService.Bootstrap(ctx, "admin", "password")
+		if _, err := authService.Login(ctx, "admin", "password"); !errors.Is(err, ErrNoSigningKey) {
+			t.Errorf("Expected no tokens to be signed with JWT_SECRET %q, got %v", secret, err)
+		}
+	}
+
+	os.Setenv("JWT_SECRET", strings.Repeat("k", MinHMACSecretBytes))
+	if _, err := KeySetFromEnv(); err != nil {
+		t.Errorf("Expected a %d byte secret to be accepted, got %v", MinHMACSecretBytes, err)
+	}
+}
+
+func TestAsymmetricKeysAndRotation(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	pemKey := func(key any) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("Failed to marshal key: %v", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+	oldKey, err := ParsePrivateKeyPEM(pemKey(rsaKey))
+	if err != nil || oldKey.Algorithm != AlgRS256 {
+		t.Fatalf("Expected an RS256 key, got %+v, %v", oldKey, err)
+	}
+	newKey, err := ParsePrivateKeyPEM(pemKey(edPrivate))
+	if err != nil || newKey.Algorithm != AlgEdDSA {
+		t.Fatalf("Expected an EdDSA key, got %+v, %v", newKey, err)
+	}
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	if _, err := ParsePrivateKeyPEM(pemKey(weak)); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("Expected ErrWeakKey for a 1024-bit key, got %v", err)
+	}
+
+	authService := newTestService(t)
+	authService.Keys, _ = NewKeySet(oldKey)
+	oldPair, _ := authService.Login(ctx, "admin", "password")
+	header := func(token string) map[string]any {
+		parsed, _, _ := jwt.NewParser().ParseUnverified(token, &tokenClaims{})
+		return parsed.Header
+	}
+	if h := header(oldPair.AccessToken); h["alg"] != AlgRS256 || h["kid"] != oldKey.ID {
+		t.Errorf("Expected an RS256 token with kid %s, got %v", oldKey.ID, h)
+	}
+
+	// Rotate: sign with the new key, keep verifying with the old one.
+	publicDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	oldPublic, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	if err != nil || oldPublic.ID != oldKey.ID {
+		t.Fatalf("Expected the public key to have the same ID, got %+v, %v", oldPublic, err)
+	}
+	authService.Keys, _ = NewKeySet(newKey, oldPublic)
+	newPair, _ := authService.Login(ctx, "admin", "password")
+	if h := header(newPair.AccessToken); h["alg"] != AlgEdDSA || h["kid"] != newKey.ID {
+		t.Errorf("Expected an EdDSA token with kid %s, got %v", newKey.ID, h)
+	}
+	for _, token := range []string{oldPair.AccessToken, newPair.AccessToken} {
+		if _, err := authService.ValidateToken(ctx, token); err != nil {
+			t.Errorf("Expected tokens from both keys to be valid, got %v", err)
+		}
+	}
+	jwks := authService.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Kty != "OKP" || jwks.Keys[1].Kty != "RSA" || jwks.Keys[1].E != "AQAB" {
+		t.Errorf("Expected both public keys in the JWKS, got %+v", jwks)
+	}
+
+	// Retire the old key.
+	authService.Keys, _ = NewKeySet(newKey)
+	if _, err := authService.ValidateToken(ctx, oldPair.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a token from a retired key to be rejected, got %v", err)
+	}
+
+	claims := tokenClaims{Type: accessTokenType, RegisteredClaims: jwt.RegisteredClaims{
+		ID: "jti", Subject: "user-1", Issuer: authService.Issuer, Audience: jwt.ClaimStrings{authService.Audience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}}
+	// An HS256 token keyed with the published public key must not pass.
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	confused.Header["kid"] = newKey.ID
+	confusedToken, _ := confused.SignedString([]byte(newKey.verifyKey.(ed25519.PublicKey)))
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	unsigned.Header["kid"] = newKey.ID
+	unsignedToken, _ := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	noKid := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	noKidToken, _ := noKid.SignedString(edPrivate)
+	for name, token := range map[string]string{"alg confusion": confusedToken, "alg none": unsignedToken, "missing kid": noKidToken} {
+		if _, err := authService.ValidateToken(ctx, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
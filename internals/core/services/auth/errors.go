@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUsernameTaken       = errors.New("username already taken")
+	ErrUsernameRequired    = errors.New("username is required")
+	ErrPasswordRequired    = errors.New("password is required")
+	ErrPasswordTooShort    = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrPasswordTooLong     = errors.New("password must be at most 72 bytes")
+	ErrInvalidRole         = errors.New("invalid role")
+	ErrLastAdmin           = errors.New("cannot remove the last active admin")
+	ErrAlreadyBootstrapped = errors.New("users already exist; log in as an admin to add more")
+	ErrInvalidAPIKey       = errors.New("invalid api key")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrAPIKeyRevoked       = errors.New("api key has been revoked")
+	ErrAPIKeyNameRequired  = errors.New("api key name is required")
+	ErrInvalidScope        = errors.New("invalid api key scope")
+	ErrAPIKeysUnavailable  = errors.New("api keys are not configured")
+	ErrTooManyAttempts     = errors.New("too many failed logins")
+	ErrNotLocked           = errors.New("no failed logins recorded")
+	ErrInvalidKey          = errors.New("invalid signing key")
+	ErrUnsupportedKey      = errors.New("unsupported key type; use RSA or Ed25519")
+	ErrWeakKey             = fmt.Errorf("RSA keys must be at least %d bits", MinRSAKeyBits)
+	ErrNoSigningKey        = errors.New("no private key to sign tokens with")
+	ErrWeakSecret          = fmt.Errorf("JWT_SECRET must be at least %d bytes", MinHMACSecretBytes)
+)
+
+func Wrap(content string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", content, err)
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// MinRSAKeyBits is the smallest RSA key accepted for signing or
+// verification.
+const MinRSAKeyBits = 2048
+
+// MinHMACSecretBytes is the shortest JWT_SECRET tokens are signed with.
+const MinHMACSecretBytes = 32
+
+// Key is one token signing or verification key. Asymmetric keys are
+// identified by their RFC 7638 thumbprint, which is written into the "kid"
+// header of every token they sign.
+type Key struct {
+	ID        string
+	Algorithm string
+	signKey   any
+	verifyKey any
+}
+
+// NewHMACKey returns an HS256 key. HS256 tokens carry no key ID, and the
+// key is never published.
+func NewHMACKey(secret []byte) *Key {
+	return &Key{Algorithm: AlgHS256, signKey: secret, verifyKey: secret}
+}
+
+// ParsePrivateKeyPEM reads an RSA (PKCS #1 or #8) or Ed25519 (PKCS #8)
+// private key.
+func ParsePrivateKeyPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	var parsed any
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key, err := newPublicKey(&private.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		key.signKey = private
+		return key, nil
+	case ed25519.PrivateKey:
+		key, err := newPublicKey(private.Public())
+		if err != nil {
+			return nil, err
+		}
+		key.signKey = private
+		return key, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// ParsePublicKeyPEM reads an RSA or Ed25519 public key in PKIX form, for
+// verifying tokens signed by a key that is no longer used for signing.
+func ParsePublicKeyPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return newPublicKey(parsed)
+}
+
+func newPublicKey(public any) (*Key, error) {
+	var key *Key
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < MinRSAKeyBits {
+			return nil, ErrWeakKey
+		}
+		key = &Key{Algorithm: AlgRS256, verifyKey: public}
+	case ed25519.PublicKey:
+		key = &Key{Algorithm: AlgEdDSA, verifyKey: public}
+	default:
+		return nil, ErrUnsupportedKey
+	}
+	key.ID = key.thumbprint()
+	return key, nil
+}
+
+// JWK is a public key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served to other services so they can verify our
+// tokens.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// jwk returns the public half of the key, or false for HMAC keys, which
+// must stay secret.
+func (k *Key) jwk() (JWK, bool) {
+	switch public := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", Kid: k.ID, Use: "sig", Alg: k.Algorithm,
+			N: b64(public.N.Bytes()), E: b64(big.NewInt(int64(public.E)).Bytes())}, true
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Kid: k.ID, Use: "sig", Alg: k.Algorithm, Crv: "Ed25519", X: b64(public)}, true
+	}
+	return JWK{}, false
+}
+
+// thumbprint is the RFC 7638 SHA-256 thumbprint of the public key: the
+// hash of its required JWK members, in lexical order, without whitespace.
+func (k *Key) thumbprint() string {
+	jwk, _ := k.jwk()
+	var members any
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+	encoded, _ := json.Marshal(members)
+	sum := sha256.Sum256(encoded)
+	return b64(sum[:])
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// KeySet is the key tokens are signed with plus every key they are
+// verified against. Keeping the previous key in the set after a rotation
+// lets tokens it signed stay valid until they expire.
+type KeySet struct {
+	signing *Key
+	keys    []*Key
+}
+
+// NewKeySet signs with signing and also accepts tokens signed by any of
+// previous. Only public keys are needed for previous, and listing the
+// signing key again is harmless. An HMAC secret must be at least
+// MinHMACSecretBytes long.
+func NewKeySet(signing *Key, previous ...*Key) (*KeySet, error) {
+	if signing == nil || signing.signKey == nil {
+		return nil, ErrNoSigningKey
+	}
+	if secret, ok := signing.signKey.([]byte); ok {
+		if len(secret) == 0 {
+			return nil, ErrNoSigningKey
+		}
+		if len(secret) < MinHMACSecretBytes {
+			return nil, ErrWeakSecret
+		}
+	}
+	set := &KeySet{signing: signing, keys: []*Key{signing}}
+	for _, key := range previous {
+		if key.Algorithm == AlgHS256 {
+			return nil, ErrUnsupportedKey
+		}
+		if set.find(key.ID) == nil {
+			set.keys = append(set.keys, key)
+		}
+	}
+	return set, nil
+}
+
+func (s *KeySet) find(id string) *Key {
+	for _, key := range s.keys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
+
+func (s *KeySet) algorithms() []string {
+	var algs []string
+	for _, key := range s.keys {
+		algs = append(algs, key.Algorithm)
+	}
+	return algs
+}
+
+// keyFunc picks the verification key named by the token's "kid" header
+// and insists that the token uses that key's algorithm, so a public key
+// can never be used as an HMAC secret.
+func (s *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key := s.find(kid)
+	if key == nil {
+		return nil, ErrInvalidToken
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, ErrInvalidToken
+	}
+	return key.verifyKey, nil
+}
+
+// JWKS returns the public keys in the set. HMAC keys are left out.
+func (s *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range s.keys {
+		if jwk, ok := key.jwk(); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+// KeySetFromEnv signs with the private key in the PEM file
+// JWT_SIGNING_KEY_FILE and also accepts the public keys in the files listed
+// in JWT_VERIFY_KEY_FILES (comma separated). Without JWT_SIGNING_KEY_FILE
+// tokens are signed with HS256 using JWT_SECRET, which must then be set and
+// at least MinHMACSecretBytes long.
+func KeySetFromEnv() (*KeySet, error) {
+	path := os.Getenv("JWT_SIGNING_KEY_FILE")
+	if path == "" {
+		return NewKeySet(NewHMACKey([]byte(os.Getenv("JWT_SECRET"))))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, Wrap("failed to read JWT_SIGNING_KEY_FILE", err)
+	}
+	signing, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, Wrap("invalid JWT_SIGNING_KEY_FILE", err)
+	}
+	var previous []*Key
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFY_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, Wrap("failed to read verification key "+path, err)
+		}
+		key, err := ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, Wrap("invalid verification key "+path, err)
+		}
+		previous = append(previous, key)
+	}
+	return NewKeySet(signing, previous...)
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+)
+
+const (
+	DefaultMaxLoginFailures   = 5
+	DefaultMaxAddressFailures = 20
+	DefaultLoginBackoff       = time.Second
+	DefaultLockoutDuration    = 15 * time.Minute
+)
+
+// ThrottledError is returned by Login while the username or client address
+// must wait before trying again. It matches ErrTooManyAttempts.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%v; retry in %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottledError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// loginKeys returns the attempt keys a login is counted against, with the
+// number of failures that locks each one.
+func (a *AuthServiceImpl) loginKeys(ctx context.Context, username string) map[string]int {
+	keys := map[string]int{domain.LoginUserKey(username): a.MaxLoginFailures}
+	if address := domain.ClientAddress(ctx); address != "" {
+		keys[domain.LoginAddressKey(address)] = a.MaxAddressFailures
+	}
+	return keys
+}
+
+// stale reports whether attempts are old enough to be forgotten: failures
+// older than LockoutDuration, or a lockout that has run out.
+func (a *AuthServiceImpl) stale(attempts domain.LoginAttempts, now time.Time) bool {
+	if attempts.LockedUntil != nil {
+		return !attempts.Locked(now)
+	}
+	return now.Sub(attempts.LastFailure) > a.LockoutDuration
+}
+
+// retryAfter is how long the key must wait before the next attempt. Each
+// failure doubles the wait, starting from LoginBackoff.
+func (a *AuthServiceImpl) retryAfter(attempts domain.LoginAttempts, now time.Time) time.Duration {
+	if a.stale(attempts, now) {
+		return 0
+	}
+	if attempts.Locked(now) {
+		return attempts.LockedUntil.Sub(now)
+	}
+	backoff := a.LoginBackoff
+	for i := 1; i < attempts.Failures && backoff < a.LockoutDuration; i++ {
+		backoff *= 2
+	}
+	if backoff > a.LockoutDuration {
+		backoff = a.LockoutDuration
+	}
+	if wait := attempts.LastFailure.Add(backoff).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func (a *AuthServiceImpl) checkThrottle(ctx context.Context, username string) error {
+	if a.LoginAttempts == nil {
+		return nil
+	}
+	now := time.Now()
+	for key := range a.loginKeys(ctx, username) {
+		attempts, err := a.LoginAttempts.FindLoginAttempts(ctx, key)
+		if err != nil {
+			return Wrap("failed to find login attempts", err)
+		}
+		if attempts == nil {
+			continue
+		}
+		if wait := a.retryAfter(*attempts, now); wait > 0 {
+			return &ThrottledError{RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login against the username and the
+// client address, locking whichever reaches its limit.
+func (a *AuthServiceImpl) recordLoginFailure(ctx context.Context, username string) error {
+	if a.LoginAttempts == nil {
+		return nil
+	}
+	now := time.Now()
+	for key, max := range a.loginKeys(ctx, username) {
+		attempts, err := a.LoginAttempts.FindLoginAttempts(ctx, key)
+		if err != nil {
+			return Wrap("failed to find login attempts", err)
+		}
+		if attempts == nil || a.stale(*attempts, now) {
+			attempts = &domain.LoginAttempts{Key: key}
+		}
+		attempts.Failures++
+		attempts.LastFailure = now
+		if attempts.Failures >= max {
+			until := now.Add(a.LockoutDuration)
+			attempts.LockedUntil = &until
+		}
+		if err := a.LoginAttempts.SaveLoginAttempts(ctx, *attempts); err != nil {
+			return Wrap("failed to save login attempts", err)
+		}
+		if attempts.LockedUntil != nil {
+			detail := fmt.Sprintf("%d failed logins; locked until %s", attempts.Failures, attempts.LockedUntil.Format(time.RFC3339))
+			a.auditDetail(ctx, domain.AuditLoginLockout, key, detail)
+		}
+	}
+	return nil
+}
+
+// clearLoginFailures forgets the username's failures after a successful
+// login. The address keeps its count, so one known account can't be used
+// to reset guessing at others.
+func (a *AuthServiceImpl) clearLoginFailures(ctx context.Context, username string) error {
+	if a.LoginAttempts == nil {
+		return nil
+	}
+	if err := a.LoginAttempts.DeleteLoginAttempts(ctx, domain.LoginUserKey(username)); err != nil {
+		return Wrap("failed to clear login attempts", err)
+	}
+	return nil
+}
+
+// ListLockouts returns the usernames and addresses currently locked out.
+func (a *AuthServiceImpl) ListLockouts(ctx context.Context) ([]domain.LoginAttempts, error) {
+	if a.LoginAttempts == nil {
+		return nil, nil
+	}
+	all, err := a.LoginAttempts.ListLoginAttempts(ctx)
+	if err != nil {
+		return nil, Wrap("failed to list login attempts", err)
+	}
+	now := time.Now()
+	var locked []domain.LoginAttempts
+	for _, attempts := range all {
+		if attempts.Locked(now) {
+			locked = append(locked, attempts)
+		}
+	}
+	return locked, nil
+}
+
+// Unlock clears the failures recorded against key, as returned by
+// ListLockouts, so logins are allowed again at once.
+func (a *AuthServiceImpl) Unlock(ctx context.Context, key string) error {
+	if a.LoginAttempts == nil {
+		return ErrNotLocked
+	}
+	attempts, err := a.LoginAttempts.FindLoginAttempts(ctx, key)
+	if err != nil {
+		return Wrap("failed to find login attempts", err)
+	}
+	if attempts == nil {
+		return ErrNotLocked
+	}
+	if err := a.LoginAttempts.DeleteLoginAttempts(ctx, key); err != nil {
+		return Wrap("failed to clear login attempts", err)
+	}
+	a.auditDetail(ctx, domain.AuditLoginUnlock, key, fmt.Sprintf("%d failed logins cleared", attempts.Failures))
+	return nil
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+	streamTokenType  = "stream"
+)
+
+type tokenClaims struct {
+	Type string `json:"typ"`
+	// APIKey marks a stream token issued to an API key, whose ID is the
+	// subject.
+	APIKey bool `json:"apikey,omitempty"`
+	// Version is the user's TokenVersion when the token was issued.
+	Version int `json:"ver"`
+	jwt.RegisteredClaims
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", Wrap("failed to generate token id", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (a *AuthServiceImpl) signToken(user domain.User, tokenType string, now time.Time, ttl time.Duration) (string, time.Time, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := now.Add(ttl)
+	key := a.Keys.signing
+	if key == nil {
+		return "", time.Time{}, ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(key.method(), tokenClaims{
+		Type:    tokenType,
+		APIKey:  user.APIKey,
+		Version: user.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   user.ID,
+			Issuer:    a.Issuer,
+			Audience:  jwt.ClaimStrings{a.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	signed, err := token.SignedString(key.signKey)
+	if err != nil {
+		return "", time.Time{}, Wrap("failed to sign token", err)
+	}
+	return signed, expiresAt, nil
+}
+
+func (a *AuthServiceImpl) issueTokens(user domain.User) (domain.TokenPair, error) {
+	var pair domain.TokenPair
+	var err error
+	now := time.Now()
+	if pair.AccessToken, pair.AccessExpiresAt, err = a.signToken(user, accessTokenType, now, a.AccessTokenTTL); err != nil {
+		return domain.TokenPair{}, err
+	}
+	if pair.RefreshToken, pair.RefreshExpiresAt, err = a.signToken(user, refreshTokenType, now, a.RefreshTokenTTL); err != nil {
+		return domain.TokenPair{}, err
+	}
+	return pair, nil
+}
+
+// parseToken checks the signature, key ID, algorithm, issuer, audience, expiry and
+// token type, and that the token has not been revoked.
+func (a *AuthServiceImpl) parseToken(ctx context.Context, tokenStr, tokenType string) (*tokenClaims, error) {
+	tokenStr = strings.TrimSpace(tokenStr)
+
+	claims := &tokenClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, a.Keys.keyFunc,
+		jwt.WithValidMethods(a.Keys.algorithms()),
+		jwt.WithIssuer(a.Issuer),
+		jwt.WithAudience(a.Audience),
+		jwt.WithExpirationRequired(),
+	)
+
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !token.Valid || claims.Type != tokenType || claims.ID == "" || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	revoked, err := a.revocations.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, Wrap("failed to check token revocation", err)
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// revoke returns ErrTokenRevoked if the token was revoked since it was
+// parsed, e.g. by a concurrent refresh with the same token.
+func (a *AuthServiceImpl) revoke(ctx context.Context, claims *tokenClaims) error {
+	revoked, err := a.revocations.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return Wrap("failed to revoke token", err)
+	}
+	if !revoked {
+		return ErrTokenRevoked
+	}
+	return nil
+}
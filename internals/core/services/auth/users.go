@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+	"time"
+)
+
+func (a *AuthServiceImpl) CreateUser(ctx context.Context, user domain.User, password string) (*domain.User, error) {
+	user.Username = strings.TrimSpace(user.Username)
+	if user.Username == "" {
+		return nil, ErrUsernameRequired
+	}
+	if !domain.ValidRole(user.Role) {
+		return nil, ErrInvalidRole
+	}
+	existing, err := a.users.FindUserByUsername(ctx, user.Username)
+	if err != nil {
+		return nil, Wrap("failed to find user", err)
+	}
+	if existing != nil {
+		return nil, ErrUsernameTaken
+	}
+	if user.PasswordHash, err = a.hashPassword(password); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user.ID = fmt.Sprintf("user-%d", now.UnixNano())
+	user.CreatedAt = now
+	if err := a.users.SaveUser(ctx, user); err != nil {
+		return nil, Wrap("failed to save user", err)
+	}
+	a.audit(ctx, domain.AuditUserCreate, userTarget(user.ID), nil, user)
+	return &user, nil
+}
+
+// Bootstrap creates the first admin of a fresh install. It refuses once any
+// user exists; from then on admins add users through CreateUser.
+func (a *AuthServiceImpl) Bootstrap(ctx context.Context, username, password string) (*domain.User, error) {
+	users, err := a.users.ListUsers(ctx)
+	if err != nil {
+		return nil, Wrap("failed to list users", err)
+	}
+	if len(users) > 0 {
+		return nil, ErrAlreadyBootstrapped
+	}
+	return a.CreateUser(ctx, domain.User{Username: username, Role: domain.RoleAdmin}, password)
+}
+
+// UpdateUser changes a user's role and whether the account is disabled.
+func (a *AuthServiceImpl) UpdateUser(ctx context.Context, id, role string, disabled bool) (*domain.User, error) {
+	if !domain.ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	user, err := a.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if (role != domain.RoleAdmin || disabled) && isActiveAdmin(*user) {
+		if err := a.checkOtherAdmin(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	before := *user
+	user.Role = role
+	user.Disabled = disabled
+	if err := a.users.UpdateUser(ctx, *user); err != nil {
+		return nil, Wrap("failed to update user", err)
+	}
+	a.audit(ctx, domain.AuditUserUpdate, userTarget(user.ID), before, user)
+	return user, nil
+}
+
+func (a *AuthServiceImpl) DeleteUser(ctx context.Context, id string) error {
+	user, err := a.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if isActiveAdmin(*user) {
+		if err := a.checkOtherAdmin(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+	if err := a.users.DeleteUser(ctx, id); err != nil {
+		return Wrap("failed to delete user", err)
+	}
+	a.audit(ctx, domain.AuditUserDelete, userTarget(user.ID), user, nil)
+	return nil
+}
+
+func (a *AuthServiceImpl) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	return a.findUser(ctx, id)
+}
+
+func (a *AuthServiceImpl) ListUsers(ctx context.Context) ([]domain.User, error) {
+	users, err := a.users.ListUsers(ctx)
+	if err != nil {
+		return nil, Wrap("failed to list users", err)
+	}
+	return users, nil
+}
+
+func (a *AuthServiceImpl) findUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := a.users.FindUserByID(ctx, id)
+	if err != nil {
+		return nil, Wrap("failed to find user", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func isActiveAdmin(user domain.User) bool {
+	return user.Role == domain.RoleAdmin && !user.Disabled
+}
+
+// checkOtherAdmin makes sure an active admin other than id remains, so the
+// lot can never be left without anyone able to manage users.
+func (a *AuthServiceImpl) checkOtherAdmin(ctx context.Context, id string) error {
+	users, err := a.users.ListUsers(ctx)
+	if err != nil {
+		return Wrap("failed to list users", err)
+	}
+	for _, user := range users {
+		if user.ID != id && isActiveAdmin(user) {
+			return nil
+		}
+	}
+	return ErrLastAdmin
+}
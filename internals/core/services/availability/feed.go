@@ -0,0 +1,140 @@
+// Package availability keeps the live slot counts for display boards. A
+// Feed counts the slots once per event and hands the same update to every
+// subscriber, so connecting more boards doesn't add queries.
+package availability
+
+import (
+	"context"
+	"log"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/events"
+	"sync"
+	"time"
+)
+
+// DefaultBuffer is how many updates a subscriber may fall behind before it
+// starts missing them.
+const DefaultBuffer = 16
+
+// Counter counts the slots per type; the parking service is one.
+type Counter interface {
+	GetAvailability(ctx context.Context) (map[string]domain.SlotAvailability, error)
+}
+
+// Update is the slot counts per type after Event, or as they stood when a
+// subscriber joined if Event is nil.
+type Update struct {
+	Event *domain.Event
+	Slots map[string]domain.SlotAvailability
+	At    time.Time
+}
+
+// Feed listens on the bus only while someone is subscribed.
+type Feed struct {
+	counter Counter
+	bus     *events.Bus
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	events *events.Subscription
+	latest Update
+}
+
+func NewFeed(counter Counter, bus *events.Bus) *Feed {
+	return &Feed{counter: counter, bus: bus, subs: map[*Subscription]struct{}{}}
+}
+
+// Subscription receives updates on C until it is closed.
+type Subscription struct {
+	C <-chan Update
+
+	c    chan Update
+	feed *Feed
+	once sync.Once
+}
+
+// Subscribe returns the current counts and a subscription to the updates
+// that follow them. Close the subscription when done.
+func (f *Feed) Subscribe(ctx context.Context, buffer int) (*Subscription, Update, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.events == nil {
+		// Listen before counting, so that no change between the two is lost.
+		sub := f.bus.Subscribe(events.DefaultBuffer)
+		slots, err := f.counter.GetAvailability(ctx)
+		if err != nil {
+			sub.Close()
+			return nil, Update{}, err
+		}
+		f.events = sub
+		f.latest = Update{Slots: slots, At: time.Now()}
+		go f.run(sub)
+	}
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	c := make(chan Update, buffer)
+	sub := &Subscription{C: c, c: c, feed: f}
+	f.subs[sub] = struct{}{}
+	return sub, Update{Slots: f.latest.Slots, At: f.latest.At}, nil
+}
+
+// Latest returns the counts after the last event, for a subscriber to
+// check whether it missed an update.
+func (f *Feed) Latest() Update {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.latest
+}
+
+// Subscribers returns how many subscriptions are open.
+func (f *Feed) Subscribers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subs)
+}
+
+// run counts the slots after each event on sub until sub is closed.
+func (f *Feed) run(sub *events.Subscription) {
+	for event := range sub.C {
+		slots, err := f.counter.GetAvailability(context.Background())
+		if err != nil {
+			log.Printf("cannot count slots after event %s: %v", event.ID, err)
+			continue
+		}
+		f.broadcast(sub, Update{Event: &event, Slots: slots, At: time.Now()})
+	}
+}
+
+// broadcast sends update to every subscriber without waiting: one whose
+// buffer is full misses it.
+func (f *Feed) broadcast(from *events.Subscription, update Update) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.events != from {
+		// Everyone left while the slots were being counted.
+		return
+	}
+	f.latest = update
+	for sub := range f.subs {
+		select {
+		case sub.c <- update:
+		default:
+		}
+	}
+}
+
+// Close stops delivery and closes C. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		f := s.feed
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.subs, s)
+		close(s.c)
+		if len(f.subs) == 0 && f.events != nil {
+			f.events.Close()
+			f.events = nil
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package availability
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/events"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+// countingCounter reports free car slots and counts how often it is asked.
+type countingCounter struct {
+	mu    sync.Mutex
+	free  int
+	calls int
+}
+
+func (c *countingCounter) GetAvailability(ctx context.Context) (map[string]domain.SlotAvailability, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return map[string]domain.SlotAvailability{"car": {Total: 10, Free: c.free}}, nil
+}
+
+func (c *countingCounter) set(free int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.free = free
+}
+
+func (c *countingCounter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func receive(t *testing.T, sub *Subscription) Update {
+	select {
+	case update := <-sub.C:
+		return update
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update arrived")
+		return Update{}
+	}
+}
+
+func TestSubscribersShareOneCountPerEvent(t *testing.T) {
+	counter := &countingCounter{free: 10}
+	bus := events.NewBus()
+	feed := NewFeed(counter, bus)
+
+	var subs []*Subscription
+	for i := 0; i < 5; i++ {
+		sub, first, err := feed.Subscribe(ctx, 0)
+		assert.NoError(t, err)
+		assert.Nil(t, first.Event)
+		assert.Equal(t, 10, first.Slots["car"].Free)
+		subs = append(subs, sub)
+	}
+	assert.Equal(t, 1, counter.count(), "later subscribers get the counts already taken")
+
+	counter.set(9)
+	event := domain.NewSlotEvent(domain.EventSlotOccupied, domain.Slot{SlotId: 1, SlotType: "car"})
+	bus.Publish(ctx, event)
+	for _, sub := range subs {
+		update := receive(t, sub)
+		assert.Equal(t, event.ID, update.Event.ID)
+		assert.Equal(t, 9, update.Slots["car"].Free)
+	}
+	assert.Equal(t, 2, counter.count(), "one count for the event, however many subscribers")
+	assert.Equal(t, 9, feed.Latest().Slots["car"].Free)
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+	sub := subs[0]
+	sub.Close()
+	assert.Zero(t, feed.Subscribers())
+	assert.Zero(t, bus.Subscribers(), "the feed stops listening when everyone has left")
+}
+
+func TestResubscribingCountsAgain(t *testing.T) {
+	counter := &countingCounter{free: 3}
+	feed := NewFeed(counter, events.NewBus())
+	sub, _, err := feed.Subscribe(ctx, 0)
+	assert.NoError(t, err)
+	sub.Close()
+
+	counter.set(2)
+	sub, first, err := feed.Subscribe(ctx, 0)
+	assert.NoError(t, err)
+	defer sub.Close()
+	assert.Equal(t, 2, first.Slots["car"].Free, "changes made while no one listened are picked up")
+}
@@ -0,0 +1,95 @@
+package currency
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   domain.Money
+		locale   string
+		expected string
+	}{
+		{domain.NewMoney(14160, "INR"), "en-IN", "₹141.60"},
+		{domain.NewMoney(1234567890, "INR"), "en-IN", "₹1,23,45,678.90"},
+		{domain.NewMoney(123456789, "USD"), "en-US", "$1,234,567.89"},
+		{domain.NewMoney(123450, "EUR"), "de-DE", "1.234,50 €"},
+		{domain.NewMoney(1500, "JPY"), "ja-JP", "¥1,500"},
+		{domain.NewMoney(-2050, "GBP"), "en-GB", "-£20.50"},
+		{domain.NewMoney(5000, "AED"), "en-AE", "AED 50.00"},
+		{domain.NewMoney(5000, "USD"), "xx-XX", "$50.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Format(tt.amount, tt.locale))
+		})
+	}
+}
+
+func TestRateTable_Convert(t *testing.T) {
+	table := &RateTable{Base: "INR", Rates: map[domain.Currency]float64{"USD": 83.25, "JPY": 0.56}}
+
+	inr, err := table.Convert(domain.NewMoney(1000, "USD"), "INR")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(83250, "INR"), inr)
+
+	usd, err := table.Convert(domain.NewMoney(83250, "INR"), "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(1000, "USD"), usd)
+
+	// JPY has no minor unit: ¥1000 is ₹560.00.
+	inr, err = table.Convert(domain.NewMoney(1000, "JPY"), "INR")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(56000, "INR"), inr)
+
+	_, err = table.Convert(domain.NewMoney(1000, "EUR"), "INR")
+	assert.ErrorIs(t, err, ErrNoRate)
+}
+
+func TestLotFromEnv(t *testing.T) {
+	lot, err := LotFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DefaultLot(), lot)
+
+	t.Setenv("LOT_NAME", "Dubai Mall")
+	t.Setenv("LOT_CURRENCY", "aed")
+	t.Setenv("LOT_LOCALE", "en-AE")
+	t.Setenv("LOT_TARIFFS", "car=10, bike=5.50")
+	lot, err = LotFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "Dubai Mall", lot.Name)
+	assert.Equal(t, domain.Currency("AED"), lot.Currency)
+	assert.Equal(t, domain.NewMoney(550, "AED"), lot.HourlyRates["bike"])
+
+	t.Setenv("LOT_TARIFFS", "")
+	_, err = LotFromEnv()
+	assert.ErrorIs(t, err, ErrInvalidTariff)
+
+	t.Setenv("LOT_CURRENCY", "dirham")
+	_, err = LotFromEnv()
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+}
+
+func TestRatesFromEnv(t *testing.T) {
+	table, err := RatesFromEnv("INR")
+	assert.NoError(t, err)
+	assert.Nil(t, table)
+
+	t.Setenv("EXCHANGE_RATES", "INR=0.012, EUR=1.08")
+	t.Setenv("BASE_CURRENCY", "usd")
+	table, err = RatesFromEnv("INR")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Currency("USD"), table.Base)
+	assert.Equal(t, 0.012, table.Rates["INR"])
+
+	t.Setenv("EXCHANGE_RATES", "EUR=-1")
+	_, err = RatesFromEnv("INR")
+	assert.ErrorIs(t, err, ErrInvalidRate)
+
+	t.Setenv("EXCHANGE_RATES", "EUR")
+	_, err = RatesFromEnv("INR")
+	assert.ErrorIs(t, err, ErrInvalidRateSpec)
+}
@@ -0,0 +1,98 @@
+package currency
+
+import (
+	"os"
+	"parkingSlotManagement/internals/core/domain"
+	"strconv"
+	"strings"
+)
+
+// LotFromEnv builds the lot from:
+//
+//	LOT_NAME      shown on receipts
+//	LOT_CURRENCY  ISO 4217 code, INR by default
+//	LOT_LOCALE    e.g. en-IN (default), en-US, de-DE
+//	LOT_TARIFFS   hourly rate per slot type in LOT_CURRENCY, e.g. car=60,bike=30
+//
+// Without LOT_TARIFFS the default ₹60/₹30 tariffs are used, which only
+// makes sense for an INR lot.
+func LotFromEnv() (domain.Lot, error) {
+	lot := domain.DefaultLot()
+	lot.Name = os.Getenv("LOT_NAME")
+	if value := os.Getenv("LOT_CURRENCY"); value != "" {
+		currency, err := parseCurrency(value)
+		if err != nil {
+			return domain.Lot{}, err
+		}
+		lot.Currency = currency
+	}
+	if value := os.Getenv("LOT_LOCALE"); value != "" {
+		lot.Locale = value
+	}
+	value := os.Getenv("LOT_TARIFFS")
+	if value == "" {
+		if lot.Currency != domain.CurrencyINR {
+			return domain.Lot{}, ErrInvalidTariff
+		}
+		return lot, nil
+	}
+	lot.HourlyRates = make(map[string]domain.Money)
+	for _, spec := range strings.Split(value, ",") {
+		slotType, amount, ok := strings.Cut(strings.TrimSpace(spec), "=")
+		if !ok || slotType == "" {
+			return domain.Lot{}, ErrInvalidTariff
+		}
+		rate, err := domain.ParseMoney(amount, lot.Currency)
+		if err != nil || rate.IsNegative() {
+			return domain.Lot{}, ErrInvalidTariff
+		}
+		lot.HourlyRates[strings.TrimSpace(slotType)] = rate
+	}
+	return lot, nil
+}
+
+// RatesFromEnv builds a rate table from EXCHANGE_RATES, e.g.
+// USD=83.2,EUR=90.1, giving the value of each currency in BASE_CURRENCY
+// (base when unset). It returns nil when no rates are configured.
+func RatesFromEnv(base domain.Currency) (*RateTable, error) {
+	value := os.Getenv("EXCHANGE_RATES")
+	if value == "" {
+		return nil, nil
+	}
+	if code := os.Getenv("BASE_CURRENCY"); code != "" {
+		var err error
+		if base, err = parseCurrency(code); err != nil {
+			return nil, err
+		}
+	}
+	table := &RateTable{Base: base, Rates: make(map[domain.Currency]float64), Rounding: domain.RoundHalfUp}
+	for _, spec := range strings.Split(value, ",") {
+		code, rateStr, ok := strings.Cut(strings.TrimSpace(spec), "=")
+		if !ok {
+			return nil, ErrInvalidRateSpec
+		}
+		currency, err := parseCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+		if err != nil || rate <= 0 {
+			return nil, ErrInvalidRate
+		}
+		table.Rates[currency] = rate
+	}
+	return table, nil
+}
+
+func parseCurrency(value string) (domain.Currency, error) {
+	code := strings.ToUpper(strings.TrimSpace(value))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return domain.Currency(code), nil
+}
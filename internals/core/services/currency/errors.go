@@ -0,0 +1,11 @@
+package currency
+
+import "errors"
+
+var (
+	ErrNoRate          = errors.New("no exchange rate for currency")
+	ErrInvalidRate     = errors.New("exchange rates must be positive")
+	ErrInvalidRateSpec = errors.New("exchange rates must look like USD=83.2,EUR=90.1")
+	ErrInvalidCurrency = errors.New("currency must be a three letter ISO 4217 code")
+	ErrInvalidTariff   = errors.New("tariffs must look like car=60,bike=30")
+)
@@ -0,0 +1,88 @@
+package currency
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"strings"
+)
+
+type localeFormat struct {
+	group, decimal string
+	// indian groups thousands then every two digits: 12,34,567.
+	indian bool
+	// suffix puts the symbol after the number.
+	suffix bool
+}
+
+var locales = map[string]localeFormat{
+	"en-IN": {group: ",", decimal: ".", indian: true},
+	"hi-IN": {group: ",", decimal: ".", indian: true},
+	"en-US": {group: ",", decimal: "."},
+	"en-GB": {group: ",", decimal: "."},
+	"en-AE": {group: ",", decimal: "."},
+	"ja-JP": {group: ",", decimal: "."},
+	"de-DE": {group: ".", decimal: ",", suffix: true},
+	"fr-FR": {group: " ", decimal: ",", suffix: true},
+}
+
+var symbols = map[domain.Currency]string{
+	"INR": "₹",
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+}
+
+// Format writes m the way the locale expects, e.g. ₹1,41,160.00 for en-IN
+// or 1.234,50 € for de-DE. Unknown locales are formatted like en-US and
+// currencies without a symbol use their code.
+func Format(m domain.Money, locale string) string {
+	format, ok := locales[locale]
+	if !ok {
+		format = locales["en-US"]
+	}
+	currency := m.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	symbol, ok := symbols[currency]
+	if !ok {
+		symbol = string(currency) + " "
+	}
+
+	digits := m.Decimal()
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	number := group(whole, format)
+	if fraction != "" {
+		number += format.decimal + fraction
+	}
+	if format.suffix {
+		number += " " + strings.TrimSpace(symbol)
+	} else {
+		number = symbol + number
+	}
+	if negative {
+		number = "-" + number
+	}
+	return number
+}
+
+func group(whole string, format localeFormat) string {
+	if len(whole) <= 3 {
+		return whole
+	}
+	head, tail := whole[:len(whole)-3], whole[len(whole)-3:]
+	size := 3
+	if format.indian {
+		size = 2
+	}
+	var parts []string
+	for len(head) > size {
+		parts = append([]string{head[len(head)-size:]}, parts...)
+		head = head[:len(head)-size]
+	}
+	parts = append([]string{head}, parts...)
+	return strings.Join(append(parts, tail), format.group)
+}
@@ -0,0 +1,38 @@
+package currency
+
+import "parkingSlotManagement/internals/core/domain"
+
+// RateTable converts money through a base currency. Rates holds how many
+// units of Base one unit of each currency is worth.
+type RateTable struct {
+	Base     domain.Currency
+	Rates    map[domain.Currency]float64
+	Rounding domain.Rounding
+}
+
+func (t *RateTable) rate(c domain.Currency) (float64, error) {
+	if c == t.Base {
+		return 1, nil
+	}
+	rate, ok := t.Rates[c]
+	if !ok || rate <= 0 {
+		return 0, ErrNoRate
+	}
+	return rate, nil
+}
+
+// Convert returns m in currency to.
+func (t *RateTable) Convert(m domain.Money, to domain.Currency) (domain.Money, error) {
+	if m.Currency == to || m.Currency == "" {
+		return domain.Money{Amount: m.Amount, Currency: to}, nil
+	}
+	from, err := t.rate(m.Currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	target, err := t.rate(to)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return m.Convert(to, from/target, t.Rounding), nil
+}
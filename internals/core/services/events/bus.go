@@ -0,0 +1,76 @@
+// Package events fans the parking service's events out to in-process
+// subscribers such as the availability streams.
+package events
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"sync"
+)
+
+// DefaultBuffer is how many events a subscriber may fall behind before it
+// starts missing them.
+const DefaultBuffer = 64
+
+// Bus is a ports.EventPublisher that hands every event to each of its
+// subscribers. Publishing never waits: a subscriber whose buffer is full
+// misses the event.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: map[*Subscription]struct{}{}}
+}
+
+// Subscription receives events on C until it is closed.
+type Subscription struct {
+	C <-chan domain.Event
+
+	c    chan domain.Event
+	bus  *Bus
+	once sync.Once
+}
+
+// Subscribe returns a subscription with room for buffer unread events.
+// Close it when done.
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	c := make(chan domain.Event, buffer)
+	sub := &Subscription{C: c, c: c, bus: b}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+func (b *Bus) Publish(ctx context.Context, event domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub.c <- event:
+		default:
+		}
+	}
+}
+
+// Subscribers returns how many subscriptions are open.
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Close stops delivery and closes C. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.c)
+	})
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func TestPublishReachesEverySubscriber(t *testing.T) {
+	bus := NewBus()
+	first := bus.Subscribe(1)
+	second := bus.Subscribe(1)
+	defer first.Close()
+	defer second.Close()
+
+	event := domain.NewSlotEvent(domain.EventSlotFreed, domain.Slot{SlotId: 1, SlotType: "car"})
+	bus.Publish(ctx, event)
+
+	assert.Equal(t, event, <-first.C)
+	assert.Equal(t, event, <-second.C)
+}
+
+func TestPublishDoesNotWaitForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1)
+	defer sub.Close()
+
+	slot := domain.Slot{SlotId: 1, SlotType: "car"}
+	bus.Publish(ctx, domain.NewSlotEvent(domain.EventSlotOccupied, slot))
+	bus.Publish(ctx, domain.NewSlotEvent(domain.EventSlotFreed, slot))
+
+	assert.Equal(t, domain.EventSlotOccupied, (<-sub.C).Type)
+	assert.Empty(t, sub.C, "the second event is dropped rather than waited on")
+}
+
+func TestClose(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(0)
+	assert.Equal(t, 1, bus.Subscribers())
+
+	sub.Close()
+	sub.Close()
+	bus.Publish(ctx, domain.NewSlotEvent(domain.EventSlotAdded, domain.Slot{SlotId: 1}))
+
+	assert.Equal(t, 0, bus.Subscribers())
+	_, open := <-sub.C
+	assert.False(t, open)
+}
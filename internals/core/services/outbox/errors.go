@@ -0,0 +1,10 @@
+package outbox
+
+import "fmt"
+
+func Wrap(content string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", content, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"sync"
+)
+
+// Publishers sends each event to every publisher, even when one fails. If
+// any fails the event is sent to all of them again later.
+type Publishers []ports.OutboxPublisher
+
+func (p Publishers) Send(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Send(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogPublisher writes each event to a log as a line of JSON.
+type LogPublisher struct {
+	Logger *log.Logger
+}
+
+func NewLogPublisher(logger *log.Logger) *LogPublisher {
+	return &LogPublisher{Logger: logger}
+}
+
+func (p *LogPublisher) Send(ctx context.Context, event domain.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return Wrap("failed to encode event", err)
+	}
+	p.Logger.Printf("event %s", line)
+	return nil
+}
+
+// Broker sends events to an in-process ports.EventPublisher such as the
+// events.Bus, which can't fail.
+type Broker struct {
+	ports.EventPublisher
+}
+
+func (b Broker) Send(ctx context.Context, event domain.Event) error {
+	b.Publish(ctx, event)
+	return nil
+}
+
+// Dedupe passes each event on to a publisher once, remembering the IDs of
+// the last size events it passed on. Put it in front of a publisher whose
+// consumers can't drop repeats themselves.
+type Dedupe struct {
+	publisher ports.OutboxPublisher
+	mu        sync.Mutex
+	seen      map[string]bool
+	order     []string
+	next      int
+}
+
+func NewDedupe(publisher ports.OutboxPublisher, size int) *Dedupe {
+	return &Dedupe{publisher: publisher, seen: make(map[string]bool, size), order: make([]string, size)}
+}
+
+func (d *Dedupe) Send(ctx context.Context, event domain.Event) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.seen[event.ID] {
+		return nil
+	}
+	if err := d.publisher.Send(ctx, event); err != nil {
+		return err
+	}
+	delete(d.seen, d.order[d.next])
+	d.order[d.next] = event.ID
+	d.next = (d.next + 1) % len(d.order)
+	d.seen[event.ID] = true
+	return nil
+}
@@ -0,0 +1,180 @@
+// Package outbox relays events from the outbox, where the parking service
+// stores them in the same transaction as the change they report, to
+// publishers. A relay claims a batch before sending it, so no transaction
+// is held open across the network, and marks a message published only
+// after its publisher has accepted it. Nothing is lost if the server stops
+// in between: the claim runs out and the message is sent again. Every event keeps the ID it was stored with, and
+// consumers drop repeats by that ID, so each change reaches them once.
+package outbox
+
+import (
+	"context"
+	"log"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"time"
+)
+
+const (
+	DefaultBatchSize    = 100
+	DefaultPollInterval = time.Second
+	DefaultRetention    = 24 * time.Hour
+	DefaultClaimTTL     = time.Minute
+)
+
+// Relay sends outbox messages to a publisher in the order they were
+// stored. It is a ports.EventPublisher: make it the parking service's
+// Events and it wakes as soon as a change is stored instead of waiting for
+// the next poll.
+type Relay struct {
+	repo      ports.OutboxRepository
+	publisher ports.OutboxPublisher
+	wake      chan struct{}
+	lastPurge time.Time
+
+	// Transactor makes listing and claiming a batch one step, so that
+	// relays in several servers don't claim the same rows. Optional with a
+	// single relay.
+	Transactor   ports.Transactor
+	BatchSize    int
+	PollInterval time.Duration
+	// Retention is how long published messages are kept before they are
+	// deleted.
+	Retention time.Duration
+	// ClaimTTL is how long a relay has to send a batch it claimed. After
+	// that the batch is taken to be lost with the relay and is sent again.
+	ClaimTTL time.Duration
+}
+
+func NewRelay(repo ports.OutboxRepository, publisher ports.OutboxPublisher) *Relay {
+	return &Relay{
+		repo:         repo,
+		publisher:    publisher,
+		wake:         make(chan struct{}, 1),
+		BatchSize:    DefaultBatchSize,
+		PollInterval: DefaultPollInterval,
+		Retention:    DefaultRetention,
+		ClaimTTL:     DefaultClaimTTL,
+	}
+}
+
+// Publish wakes the relay. The event itself is read back from the outbox.
+func (r *Relay) Publish(ctx context.Context, event domain.Event) {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays messages until ctx is done: when woken, and every
+// PollInterval to retry messages a publisher refused. Published messages
+// older than Retention are deleted once an hour.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.RelayPending(ctx); err != nil {
+			log.Printf("outbox relay failed: %v", err)
+		}
+		r.purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// RelayPending sends every unpublished message, oldest first, and returns
+// how many were published. It stops at the first message the publisher
+// refuses, so that later events aren't delivered ahead of it.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		sent, full, err := r.relayBatch(ctx)
+		published += sent
+		if err != nil || !full || ctx.Err() != nil {
+			return published, err
+		}
+	}
+}
+
+// relayBatch claims one batch and commits the claim, sends it with no
+// transaction open and then marks what was sent as published. Messages
+// from a refused one on are released to be tried again. full reports
+// whether the batch was full, so there may be more.
+func (r *Relay) relayBatch(ctx context.Context) (sent int, full bool, err error) {
+	messages, err := r.claimBatch(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	full = len(messages) == r.BatchSize
+	var ids []int64
+	var sendErr error
+	for i, message := range messages {
+		if sendErr = r.publisher.Send(ctx, message.Event); sendErr != nil {
+			sendErr = Wrap("failed to publish event "+message.Event.ID, sendErr)
+			r.release(ctx, messages[i:])
+			break
+		}
+		ids = append(ids, message.ID)
+	}
+	if err := r.repo.MarkPublished(ctx, ids, time.Now()); err != nil {
+		return 0, false, Wrap("failed to mark outbox messages published", err)
+	}
+	return len(ids), full && sendErr == nil, sendErr
+}
+
+// claimBatch lists the next unclaimed messages and claims them for
+// ClaimTTL in one transaction.
+func (r *Relay) claimBatch(ctx context.Context) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+	claim := func(ctx context.Context) error {
+		now := time.Now()
+		var err error
+		if messages, err = r.repo.ListUnpublished(ctx, r.BatchSize, now); err != nil {
+			return Wrap("failed to list outbox messages", err)
+		}
+		ids := make([]int64, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		if err := r.repo.Claim(ctx, ids, now.Add(r.ClaimTTL)); err != nil {
+			return Wrap("failed to claim outbox messages", err)
+		}
+		return nil
+	}
+	var err error
+	if r.Transactor == nil {
+		err = claim(ctx)
+	} else {
+		err = r.Transactor.WithinTx(ctx, claim)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// release gives up the claim on messages that were not sent, so the next
+// poll retries them rather than waiting for the claim to run out.
+func (r *Relay) release(ctx context.Context, messages []domain.OutboxMessage) {
+	ids := make([]int64, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+	if err := r.repo.Claim(ctx, ids, time.Time{}); err != nil {
+		log.Printf("cannot release outbox messages: %v", err)
+	}
+}
+
+func (r *Relay) purge(ctx context.Context) {
+	if time.Since(r.lastPurge) < time.Hour {
+		return
+	}
+	r.lastPurge = time.Now()
+	if _, err := r.repo.DeletePublished(ctx, time.Now().Add(-r.Retention)); err != nil {
+		log.Printf("cannot delete published outbox messages: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/events"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+// consumer records the events it is sent and refuses those listed in
+// refuse, once each.
+type consumer struct {
+	mu       sync.Mutex
+	received []string
+	refuse   map[string]bool
+}
+
+func (c *consumer) Send(ctx context.Context, event domain.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.refuse[event.ID] {
+		delete(c.refuse, event.ID)
+		return errors.New("receiver unavailable")
+	}
+	c.received = append(c.received, event.ID)
+	return nil
+}
+
+func (c *consumer) ids() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.received...)
+}
+
+// forgetfulOutbox fails to mark messages published the first time, as if
+// the server stopped right after sending them.
+type forgetfulOutbox struct {
+	*inmemmory.OutboxInMemmory
+	failed bool
+}
+
+func (f *forgetfulOutbox) MarkPublished(ctx context.Context, ids []int64, at time.Time) error {
+	if !f.failed {
+		f.failed = true
+		return errors.New("connection lost")
+	}
+	return f.OutboxInMemmory.MarkPublished(ctx, ids, at)
+}
+
+type countingTransactor struct {
+	mu    sync.Mutex
+	calls int
+	open  bool
+}
+
+func (c *countingTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	c.mu.Lock()
+	c.calls++
+	c.open = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.open = false
+		c.mu.Unlock()
+	}()
+	return fn(ctx)
+}
+
+func (c *countingTransactor) isOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.open
+}
+
+// publisherFunc sends events with a function.
+type publisherFunc func(ctx context.Context, event domain.Event) error
+
+func (f publisherFunc) Send(ctx context.Context, event domain.Event) error {
+	return f(ctx, event)
+}
+
+func storeEvents(t *testing.T, repo *inmemmory.OutboxInMemmory, n int) []string {
+	var ids []string
+	for i := 1; i <= n; i++ {
+		event := domain.NewSlotEvent(domain.EventSlotAdded, domain.Slot{SlotId: i, SlotType: "car"})
+		assert.NoError(t, repo.AddEvents(ctx, event))
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	repo := inmemmory.NewOutboxInMemmory()
+	ids := storeEvents(t, repo, 5)
+	publisher := &consumer{}
+	relay := NewRelay(repo, publisher)
+	relay.BatchSize = 2
+	tx := &countingTransactor{}
+	relay.Transactor = tx
+
+	published, err := relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, published)
+	assert.Equal(t, ids, publisher.ids())
+	assert.Equal(t, 3, tx.calls, "each batch is claimed in a transaction")
+
+	published, err = relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, published)
+	assert.Equal(t, ids, publisher.ids(), "published messages aren't sent again")
+}
+
+func TestRelayStopsAtARefusedEvent(t *testing.T) {
+	repo := inmemmory.NewOutboxInMemmory()
+	ids := storeEvents(t, repo, 3)
+	publisher := &consumer{refuse: map[string]bool{ids[1]: true}}
+	relay := NewRelay(repo, publisher)
+
+	published, err := relay.RelayPending(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, ids[:1], publisher.ids(), "later events wait for the refused one")
+
+	published, err = relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, ids, publisher.ids())
+}
+
+func TestRelaySendsWithNoTransactionOpen(t *testing.T) {
+	repo := inmemmory.NewOutboxInMemmory()
+	storeEvents(t, repo, 3)
+	tx := &countingTransactor{}
+	var sentInTx bool
+	relay := NewRelay(repo, publisherFunc(func(ctx context.Context, event domain.Event) error {
+		sentInTx = sentInTx || tx.isOpen()
+		return nil
+	}))
+	relay.Transactor = tx
+
+	published, err := relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.False(t, sentInTx, "the claim is committed before anything is sent")
+}
+
+func TestRelaySkipsClaimedMessages(t *testing.T) {
+	repo := inmemmory.NewOutboxInMemmory()
+	ids := storeEvents(t, repo, 2)
+	first := &consumer{}
+	second := &consumer{}
+	var claimed bool
+	relay := NewRelay(repo, publisherFunc(func(ctx context.Context, event domain.Event) error {
+		if !claimed {
+			claimed = true
+			// Another relay polls while this one is still sending.
+			published, err := NewRelay(repo, second).RelayPending(ctx)
+			assert.NoError(t, err)
+			assert.Zero(t, published)
+		}
+		return first.Send(ctx, event)
+	}))
+
+	published, err := relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, ids, first.ids())
+	assert.Empty(t, second.ids(), "the other relay skips the claimed batch")
+}
+
+func TestConsumersSeeEachEventOnce(t *testing.T) {
+	repo := &forgetfulOutbox{OutboxInMemmory: inmemmory.NewOutboxInMemmory()}
+	ids := storeEvents(t, repo.OutboxInMemmory, 2)
+	publisher := &consumer{}
+	relay := NewRelay(repo, NewDedupe(publisher, 10))
+	// As if the relay had stopped: its claim runs out at once.
+	relay.ClaimTTL = 0
+
+	_, err := relay.RelayPending(ctx)
+	assert.Error(t, err, "the messages were sent but not marked published")
+	published, err := relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published, "so they are sent again")
+	assert.Equal(t, ids, publisher.ids(), "and the consumer drops the repeats")
+}
+
+func TestDedupeForgetsOldEvents(t *testing.T) {
+	publisher := &consumer{}
+	dedupe := NewDedupe(publisher, 2)
+	events := []domain.Event{{ID: "a"}, {ID: "b"}, {ID: "a"}, {ID: "c"}, {ID: "a"}}
+	for _, event := range events {
+		assert.NoError(t, dedupe.Send(ctx, event))
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a"}, publisher.ids())
+}
+
+func TestPublishersSendToEveryPublisher(t *testing.T) {
+	refusing := &consumer{refuse: map[string]bool{"a": true}}
+	accepting := &consumer{}
+	err := Publishers{refusing, accepting}.Send(ctx, domain.Event{ID: "a"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"a"}, accepting.ids())
+}
+
+func TestLogPublisher(t *testing.T) {
+	var out bytes.Buffer
+	publisher := NewLogPublisher(log.New(&out, "", 0))
+	assert.NoError(t, publisher.Send(ctx, domain.Event{ID: "e1", Type: domain.EventSlotFreed}))
+	assert.True(t, strings.HasPrefix(out.String(), `event {"id":"e1","type":"slot.freed"`), out.String())
+}
+
+func TestRunRelaysToTheBusWhenWoken(t *testing.T) {
+	repo := inmemmory.NewOutboxInMemmory()
+	bus := events.NewBus()
+	sub := bus.Subscribe(1)
+	defer sub.Close()
+	relay := NewRelay(repo, Broker{bus})
+	relay.PollInterval = time.Hour
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go relay.Run(runCtx)
+
+	event := domain.NewSlotEvent(domain.EventSlotOccupied, domain.Slot{SlotId: 1, SlotType: "car"})
+	assert.NoError(t, repo.AddEvents(ctx, event))
+	relay.Publish(ctx, event)
+
+	select {
+	case got := <-sub.C:
+		assert.Equal(t, event.ID, got.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the relay didn't publish the event")
+	}
+}
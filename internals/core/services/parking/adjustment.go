@@ -0,0 +1,244 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+)
+
+// RequestAdjustment opens a fee adjustment against a closed ticket on behalf
+// of the user in ctx. It has no effect on money until a supervisor approves
+// it and it is applied.
+func (s *ParkingService) RequestAdjustment(ctx context.Context, adj domain.FeeAdjustment) (*domain.FeeAdjustment, error) {
+	if s.AdjustmentRepo == nil {
+		return nil, ErrAdjustmentsUnavailable
+	}
+	if adj.Reason == "" {
+		return nil, ErrAdjustmentReasonRequired
+	}
+	if !adj.Amount.IsPositive() {
+		return nil, ErrInvalidAdjustmentAmount
+	}
+	if err := s.checkCurrency(adj.Amount); err != nil {
+		return nil, err
+	}
+	ticket, err := s.TicketRepo.FindTicketByID(ctx, adj.TicketId)
+	if err != nil || ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.ExitTime == nil {
+		return nil, ErrTicketNotClosed
+	}
+	if err := adj.Amount.CheckCurrency(ticket.Fee); err != nil {
+		return nil, err
+	}
+	remaining, err := s.adjustableFee(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+	if adj.Amount.Cmp(remaining) > 0 {
+		return nil, ErrAdjustmentExceedsFee
+	}
+
+	adj.AdjustmentId = GenerateTicketID()
+	adj.VehicleNumber = ticket.VehicleNumber
+	adj.Status = domain.AdjustmentRequested
+	adj.RequestedBy = domain.Actor(ctx)
+	adj.RequestedAt = time.Now()
+	adj.ReviewedBy, adj.ReviewedAt, adj.AppliedAt, adj.RefundReference = "", nil, nil, ""
+	if err := s.AdjustmentRepo.SaveAdjustment(ctx, adj); err != nil {
+		return nil, Wrap("failed to save fee adjustment", err)
+	}
+	s.audit(ctx, domain.AuditAdjustmentRequest, adjustmentTarget(adj.AdjustmentId), nil, adj)
+	return &adj, nil
+}
+
+// adjustableFee is the part of the ticket's fee not already claimed by
+// other adjustments that are still open or applied.
+func (s *ParkingService) adjustableFee(ctx context.Context, ticket *domain.Ticket) (domain.Money, error) {
+	existing, err := s.AdjustmentRepo.ListAdjustmentsByTicket(ctx, ticket.TicketId)
+	if err != nil {
+		return domain.Money{}, Wrap("failed to fetch fee adjustments", err)
+	}
+	remaining := ticket.Fee
+	for _, adj := range existing {
+		if adj.Status != domain.AdjustmentRejected {
+			remaining = remaining.Sub(adj.Amount)
+		}
+	}
+	return remaining, nil
+}
+
+// ApproveAdjustment and RejectAdjustment record the user in ctx as the
+// reviewer, who must be allowed to review adjustments and must not be the
+// user who requested it.
+func (s *ParkingService) ApproveAdjustment(ctx context.Context, adjustmentId int64) (*domain.FeeAdjustment, error) {
+	return s.reviewAdjustment(ctx, adjustmentId, domain.AdjustmentApproved)
+}
+
+func (s *ParkingService) RejectAdjustment(ctx context.Context, adjustmentId int64) (*domain.FeeAdjustment, error) {
+	return s.reviewAdjustment(ctx, adjustmentId, domain.AdjustmentRejected)
+}
+
+func (s *ParkingService) reviewAdjustment(ctx context.Context, adjustmentId int64, status string) (*domain.FeeAdjustment, error) {
+	reviewer, ok := domain.UserFromContext(ctx)
+	if !ok || !reviewer.Can(domain.PermAdjustmentsReview) {
+		return nil, ErrAdjustmentApprovalDenied
+	}
+	adj, err := s.findAdjustment(ctx, adjustmentId)
+	if err != nil {
+		return nil, err
+	}
+	if adj.Status != domain.AdjustmentRequested {
+		return nil, ErrInvalidAdjustmentState
+	}
+	if adj.RequestedBy == reviewer.Username {
+		return nil, ErrSelfReview
+	}
+	before := *adj
+	now := time.Now()
+	adj.Status = status
+	adj.ReviewedBy = reviewer.Username
+	adj.ReviewedAt = &now
+	updated, err := s.AdjustmentRepo.UpdateAdjustmentFrom(ctx, *adj, domain.AdjustmentRequested)
+	if err != nil {
+		return nil, Wrap("failed to update fee adjustment", err)
+	}
+	if !updated {
+		return nil, ErrInvalidAdjustmentState
+	}
+	action := domain.AuditAdjustmentApprove
+	if status == domain.AdjustmentRejected {
+		action = domain.AuditAdjustmentReject
+	}
+	s.audit(ctx, action, adjustmentTarget(adj.AdjustmentId), before, adj)
+	return adj, nil
+}
+
+// ApplyAdjustment gives an approved adjustment back to the customer: paid
+// tickets are refunded through the gateway that took the payment, and
+// tickets that were never paid have their unpaid balance reduced instead,
+// which needs the ledger. The adjustment is claimed before any money moves,
+// so it is only ever applied once.
+func (s *ParkingService) ApplyAdjustment(ctx context.Context, adjustmentId int64) (*domain.FeeAdjustment, error) {
+	adj, err := s.findAdjustment(ctx, adjustmentId)
+	if err != nil {
+		return nil, err
+	}
+	if adj.Status != domain.AdjustmentApproved {
+		return nil, ErrInvalidAdjustmentState
+	}
+	before := *adj
+	ticket, err := s.TicketRepo.FindTicketByID(ctx, adj.TicketId)
+	if err != nil || ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+
+	switch {
+	case ticket.PaymentReference != "":
+		err = s.applyRefund(ctx, adj, ticket)
+	case s.LedgerRepo == nil:
+		err = ErrLedgerUnavailable
+	default:
+		err = s.applyToLedger(ctx, adj, ticket)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, domain.AuditAdjustmentApply, adjustmentTarget(adj.AdjustmentId), before, adj)
+	return adj, nil
+}
+
+// applyRefund moves the adjustment to applying, refunds it and records the
+// refund reference as it marks it applied. A failed refund puts it back to
+// approved so that it can be tried again.
+func (s *ParkingService) applyRefund(ctx context.Context, adj *domain.FeeAdjustment, ticket *domain.Ticket) error {
+	gateway, err := s.paymentGateway(ticket.PaymentMethod)
+	if err != nil {
+		return err
+	}
+	if err := s.moveAdjustment(ctx, adj, domain.AdjustmentApproved, domain.AdjustmentApplying); err != nil {
+		return err
+	}
+	refund, err := gateway.Refund(ticket.PaymentReference, adj.Amount)
+	if err != nil {
+		if err := s.moveAdjustment(ctx, adj, domain.AdjustmentApplying, domain.AdjustmentApproved); err != nil {
+			log.Printf("cannot return adjustment %d to approved: %v", adj.AdjustmentId, err)
+		}
+		return fmt.Errorf("%w: %v", ErrRefundFailed, err)
+	}
+	now := time.Now()
+	adj.AppliedAt = &now
+	adj.RefundReference = refund.Reference
+	if err := s.moveAdjustment(ctx, adj, domain.AdjustmentApplying, domain.AdjustmentApplied); err != nil {
+		log.Printf("adjustment %d was refunded as %s but is still applying: %v", adj.AdjustmentId, refund.Reference, err)
+		return err
+	}
+	return nil
+}
+
+// applyToLedger marks the adjustment applied and credits the ledger in one
+// transaction.
+func (s *ParkingService) applyToLedger(ctx context.Context, adj *domain.FeeAdjustment, ticket *domain.Ticket) error {
+	now := time.Now()
+	entry := domain.LedgerEntry{
+		EntryId:       GenerateTicketID(),
+		VehicleNumber: ticket.VehicleNumber,
+		TicketId:      ticket.TicketId,
+		Kind:          domain.LedgerAdjustment,
+		Amount:        adj.Amount.Neg(),
+		Note:          adj.Reason,
+		CreatedAt:     now,
+	}
+	adj.AppliedAt = &now
+	err := s.store(ctx, nil, func(ctx context.Context) error {
+		if err := s.moveAdjustment(ctx, adj, domain.AdjustmentApproved, domain.AdjustmentApplied); err != nil {
+			return err
+		}
+		if err := s.LedgerRepo.SaveLedgerEntry(ctx, entry); err != nil {
+			return Wrap("failed to record adjustment on ledger", err)
+		}
+		return nil
+	})
+	if err != nil {
+		adj.Status, adj.AppliedAt = domain.AdjustmentApproved, nil
+	}
+	return err
+}
+
+// moveAdjustment stores adj with status to, provided that it is still from.
+func (s *ParkingService) moveAdjustment(ctx context.Context, adj *domain.FeeAdjustment, from, to string) error {
+	adj.Status = to
+	updated, err := s.AdjustmentRepo.UpdateAdjustmentFrom(ctx, *adj, from)
+	if err != nil {
+		adj.Status = from
+		return Wrap("failed to update fee adjustment", err)
+	}
+	if !updated {
+		adj.Status = from
+		return ErrInvalidAdjustmentState
+	}
+	return nil
+}
+
+func (s *ParkingService) findAdjustment(ctx context.Context, adjustmentId int64) (*domain.FeeAdjustment, error) {
+	if s.AdjustmentRepo == nil {
+		return nil, ErrAdjustmentsUnavailable
+	}
+	adj, err := s.AdjustmentRepo.FindAdjustmentByID(ctx, adjustmentId)
+	if err != nil || adj == nil {
+		return nil, ErrAdjustmentNotFound
+	}
+	return adj, nil
+}
+
+// GetAdjustments lists adjustments in the given status, or all of them when
+// status is empty.
+func (s *ParkingService) GetAdjustments(ctx context.Context, status string) ([]domain.FeeAdjustment, error) {
+	if s.AdjustmentRepo == nil {
+		return nil, ErrAdjustmentsUnavailable
+	}
+	return s.AdjustmentRepo.ListAdjustments(ctx, status)
+}
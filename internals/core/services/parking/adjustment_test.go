@@ -0,0 +1,239 @@
+package parking
+
+import (
+	"errors"
+	"parkingSlotManagement/internals/adapters/payments"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	asSupervisor = domain.WithUser(ctx, &domain.User{Username: "sup", Role: domain.RoleSupervisor})
+	asAttendant  = domain.WithUser(ctx, &domain.User{Username: "att", Role: domain.RoleAttendant})
+)
+
+func newAdjustmentService() (*ParkingService, *inmemmory.TicketInMemmory, *payments.CashGateway) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: false})
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 2, SlotType: "car", IsFree: false})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{TicketId: 1, VehicleNumber: "UP16AB1234", SlotId: 1, EntryTime: time.Now().Add(-2 * time.Hour)})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{TicketId: 2, VehicleNumber: "DL3CAF0001", SlotId: 2, EntryTime: time.Now().Add(-1 * time.Hour)})
+
+	cash := payments.NewCashGateway()
+	service := NewParkingService(slotRepo, ticketRepo)
+	service.PaymentGateways = map[string]ports.PaymentGateway{domain.PaymentCash: cash}
+	service.LedgerRepo = inmemmory.NewLedgerInMemmory()
+	service.AdjustmentRepo = inmemmory.NewAdjustmentInMemmory()
+	return service, ticketRepo, cash
+}
+
+func TestAdjustmentWorkflow_RefundsPaidTicket(t *testing.T) {
+	service, _, cash := newAdjustmentService()
+	ticket, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+
+	adj, err := service.RequestAdjustment(asAttendant, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(20), Reason: "overcharged"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AdjustmentRequested, adj.Status)
+	assert.Equal(t, "att", adj.RequestedBy)
+
+	_, err = service.ApplyAdjustment(ctx, adj.AdjustmentId)
+	assert.ErrorIs(t, err, ErrInvalidAdjustmentState)
+
+	_, err = service.ApproveAdjustment(asAttendant, adj.AdjustmentId)
+	assert.ErrorIs(t, err, ErrAdjustmentApprovalDenied)
+
+	approved, err := service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	assert.NoError(t, err)
+	assert.Equal(t, "sup", approved.ReviewedBy)
+
+	applied, err := service.ApplyAdjustment(ctx, adj.AdjustmentId)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AdjustmentApplied, applied.Status)
+	assert.Equal(t, ticket.PaymentReference, applied.RefundReference)
+
+	payment, _ := cash.Status(ticket.PaymentReference)
+	assert.Equal(t, inr(20), payment.RefundedAmount)
+
+	_, err = service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	assert.ErrorIs(t, err, ErrInvalidAdjustmentState)
+}
+
+func TestApplyAdjustment_RefundsOnce(t *testing.T) {
+	service, _, cash := newAdjustmentService()
+	ticket, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	adj, err := service.RequestAdjustment(asAttendant, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(20), Reason: "overcharged"})
+	assert.NoError(t, err)
+	_, err = service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = service.ApplyAdjustment(ctx, adj.AdjustmentId)
+		}(i)
+	}
+	wg.Wait()
+
+	applied := 0
+	for _, err := range errs {
+		if err == nil {
+			applied++
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidAdjustmentState)
+		}
+	}
+	assert.Equal(t, 1, applied)
+	payment, _ := cash.Status(ticket.PaymentReference)
+	assert.Equal(t, inr(20), payment.RefundedAmount)
+}
+
+type failingRefunds struct {
+	*payments.CashGateway
+}
+
+func (failingRefunds) Refund(reference string, amount domain.Money) (*domain.Payment, error) {
+	return nil, errors.New("gateway timeout")
+}
+
+func TestApplyAdjustment_FailedRefundStaysApproved(t *testing.T) {
+	service, _, cash := newAdjustmentService()
+	ticket, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	adj, err := service.RequestAdjustment(asAttendant, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(20), Reason: "overcharged"})
+	assert.NoError(t, err)
+	_, err = service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	assert.NoError(t, err)
+
+	service.PaymentGateways[domain.PaymentCash] = failingRefunds{cash}
+	_, err = service.ApplyAdjustment(ctx, adj.AdjustmentId)
+	assert.ErrorIs(t, err, ErrRefundFailed)
+	stored, _ := service.AdjustmentRepo.FindAdjustmentByID(ctx, adj.AdjustmentId)
+	assert.Equal(t, domain.AdjustmentApproved, stored.Status)
+
+	service.PaymentGateways[domain.PaymentCash] = cash
+	applied, err := service.ApplyAdjustment(ctx, adj.AdjustmentId)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AdjustmentApplied, applied.Status)
+}
+
+func TestAdjustmentWorkflow_ReducesUnpaidBalance(t *testing.T) {
+	service, _, _ := newAdjustmentService()
+	fee, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "barrier lifted")
+	assert.NoError(t, err)
+
+	adj, err := service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: 1, Amount: fee, Reason: "barrier fault, waive fee"})
+	assert.NoError(t, err)
+	service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	_, err = service.ApplyAdjustment(ctx, adj.AdjustmentId)
+	assert.NoError(t, err)
+
+	balance, _ := service.GetUnpaidBalance(ctx, "UP16AB1234")
+	assert.True(t, balance.IsZero())
+}
+
+func TestApplyAdjustment_NeedsLedgerForUnpaidTicket(t *testing.T) {
+	service, _, _ := newAdjustmentService()
+	fee, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "barrier lifted")
+	assert.NoError(t, err)
+	adj, err := service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: 1, Amount: fee, Reason: "barrier fault, waive fee"})
+	assert.NoError(t, err)
+	_, err = service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	assert.NoError(t, err)
+
+	service.LedgerRepo = nil
+	_, err = service.ApplyAdjustment(ctx, adj.AdjustmentId)
+	assert.ErrorIs(t, err, ErrLedgerUnavailable)
+
+	stored, err := service.AdjustmentRepo.FindAdjustmentByID(ctx, adj.AdjustmentId)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AdjustmentApproved, stored.Status)
+	assert.Nil(t, stored.AppliedAt)
+}
+
+func TestReviewAdjustment_NotBySelf(t *testing.T) {
+	service, _, _ := newAdjustmentService()
+	ticket, _ := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{})
+
+	adj, err := service.RequestAdjustment(asSupervisor, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(10), Reason: "overcharged"})
+	assert.NoError(t, err)
+
+	_, err = service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	assert.ErrorIs(t, err, ErrSelfReview)
+	_, err = service.RejectAdjustment(asSupervisor, adj.AdjustmentId)
+	assert.ErrorIs(t, err, ErrSelfReview)
+
+	other := domain.WithUser(ctx, &domain.User{Username: "sup2", Role: domain.RoleSupervisor})
+	approved, err := service.ApproveAdjustment(other, adj.AdjustmentId)
+	assert.NoError(t, err)
+	assert.Equal(t, "sup2", approved.ReviewedBy)
+}
+
+func TestRequestAdjustment_Validation(t *testing.T) {
+	service, _, _ := newAdjustmentService()
+
+	_, err := service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: 1, Amount: inr(10), Reason: "open ticket"})
+	assert.ErrorIs(t, err, ErrTicketNotClosed)
+
+	ticket, _ := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{})
+
+	_, err = service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(10)})
+	assert.ErrorIs(t, err, ErrAdjustmentReasonRequired)
+
+	_, err = service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(-5), Reason: "x"})
+	assert.ErrorIs(t, err, ErrInvalidAdjustmentAmount)
+
+	_, err = service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: 99, Amount: inr(5), Reason: "x"})
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+
+	first, err := service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: ticket.Fee.Sub(inr(10)), Reason: "x"})
+	assert.NoError(t, err)
+	_, err = service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(20), Reason: "x"})
+	assert.ErrorIs(t, err, ErrAdjustmentExceedsFee)
+
+	_, err = service.RejectAdjustment(asSupervisor, first.AdjustmentId)
+	assert.NoError(t, err)
+	_, err = service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: ticket.TicketId, Amount: inr(20), Reason: "x"})
+	assert.NoError(t, err)
+
+	requested, err := service.GetAdjustments(ctx, domain.AdjustmentRequested)
+	assert.NoError(t, err)
+	assert.Len(t, requested, 1)
+}
+
+func TestGetRevenueReport(t *testing.T) {
+	service, _, _ := newAdjustmentService()
+	from := time.Now().Add(-time.Minute)
+
+	paid, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	unpaid, err := service.ForceUnparkVehicle(ctx, "DL3CAF0001", "payment failure")
+	assert.NoError(t, err)
+
+	adj, _ := service.RequestAdjustment(ctx, domain.FeeAdjustment{TicketId: paid.TicketId, Amount: inr(10), Reason: "overcharged"})
+	service.ApproveAdjustment(asSupervisor, adj.AdjustmentId)
+	service.ApplyAdjustment(ctx, adj.AdjustmentId)
+
+	report, err := service.GetRevenueReport(ctx, from, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.TicketCount)
+	assert.Equal(t, paid.Fee.Add(unpaid), report.GrossFees)
+	assert.Equal(t, inr(10), report.Adjustments)
+	assert.Equal(t, paid.Fee.Add(unpaid).Sub(inr(10)), report.NetRevenue)
+	assert.Equal(t, paid.Fee, report.ByPaymentMethod[domain.PaymentCash])
+	assert.Equal(t, unpaid, report.ByPaymentMethod["unpaid"])
+
+	_, err = service.GetRevenueReport(ctx, from, from)
+	assert.ErrorIs(t, err, ErrInvalidReportRange)
+}
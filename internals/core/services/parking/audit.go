@@ -0,0 +1,24 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/services/audit"
+)
+
+// audit records a change in AuditLog, if one is set.
+func (s *ParkingService) audit(ctx context.Context, action, target string, before, after any) {
+	audit.Record(ctx, s.AuditLog, action, target, before, after)
+}
+
+func ticketTarget(ticketId int64) string {
+	return fmt.Sprintf("ticket:%d", ticketId)
+}
+
+func adjustmentTarget(adjustmentId int64) string {
+	return fmt.Sprintf("adjustment:%d", adjustmentId)
+}
+
+func vehicleTarget(vehicleNumber string) string {
+	return "vehicle:" + vehicleNumber
+}
@@ -0,0 +1,59 @@
+package parking
+
+import (
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangesAreAudited(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	service := NewParkingService(slotRepo, inmemmory.NewTicketInMemmory())
+	service.PaymentGateways = cashGateways()
+	service.LedgerRepo = inmemmory.NewLedgerInMemmory()
+	auditLog := inmemmory.NewAuditInMemmory()
+	service.AuditLog = auditLog
+
+	admin := domain.WithRequestID(domain.WithUser(ctx, &domain.User{Username: "admin", Role: domain.RoleAdmin}), "req-1")
+	attendant := domain.WithRequestID(domain.WithUser(ctx, &domain.User{Username: "ravi", Role: domain.RoleAttendant}), "req-2")
+
+	assert.NoError(t, service.AddSlot(admin, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}))
+	ticket, err := service.ParkVehicle(attendant, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.NoError(t, err)
+	_, err = service.UnparkVehicle(attendant, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	_, err = service.ParkVehicle(attendant, domain.Vehicle{VehicleNumber: "UP16AB9999", VehicleType: "car"})
+	assert.NoError(t, err)
+	_, err = service.ForceUnparkVehicle(admin, "UP16AB9999", "barrier lifted manually")
+	assert.NoError(t, err)
+
+	entries := auditLog.Entries()
+	if !assert.Len(t, entries, 5) {
+		return
+	}
+	assert.Equal(t, domain.AuditSlotAdd, entries[0].Action)
+	assert.Equal(t, "slot:1", entries[0].Target)
+	assert.Equal(t, "admin", entries[0].Actor)
+	assert.Equal(t, "req-1", entries[0].RequestID)
+	assert.Nil(t, entries[0].Before)
+
+	park, unpark := entries[1], entries[2]
+	assert.Equal(t, domain.AuditTicketPark, park.Action)
+	assert.Equal(t, ticketTarget(ticket.TicketId), park.Target)
+	assert.Equal(t, "ravi", park.Actor)
+	assert.Equal(t, "req-2", park.RequestID)
+	assert.Equal(t, domain.AuditTicketUnpark, unpark.Action)
+	assert.NotContains(t, string(unpark.Before), "exittime")
+	assert.Contains(t, string(unpark.After), "exittime")
+
+	force := entries[4]
+	assert.Equal(t, domain.AuditTicketForceUnpark, force.Action)
+	assert.Equal(t, "barrier lifted manually", force.Detail)
+	assert.Equal(t, "req-1", force.RequestID)
+
+	filtered, err := auditLog.ListAuditEntries(ctx, domain.AuditFilter{Action: "ticket", Actor: "ravi"})
+	assert.NoError(t, err)
+	assert.Len(t, filtered, 3)
+}
@@ -18,6 +18,38 @@ var (
 	ErrExistingTicketCheck  = errors.New("error checking existing ticket")
 	ErrSlotFetchByType      = errors.New("failed to fetch slots by type ")
 	ErrVehicleAlreadyParked = errors.New("vehicle has been already parked")
+
+	ErrVehicleBlocklisted     = errors.New("vehicle is blocklisted")
+	ErrVehicleListCheck       = errors.New("error checking vehicle list")
+	ErrVehicleListUnavailable = errors.New("vehicle lists are not configured")
+	ErrInvalidListType        = errors.New("list type must be block or allow")
+
+	ErrUnpaidBalanceExceeded    = errors.New("vehicle has an unpaid balance over the allowed limit")
+	ErrBalanceCheck             = errors.New("error checking unpaid balance")
+	ErrLedgerUnavailable        = errors.New("unpaid balance ledger is not configured")
+	ErrInvalidSettlementAmount  = errors.New("settlement amount must be positive")
+	ErrSettlementExceedsBalance = errors.New("settlement amount exceeds outstanding balance")
+
+	ErrTicketCloseFailed        = errors.New("failed to close ticket")
+	ErrOutboxWriteFailed        = errors.New("failed to store events in the outbox")
+	ErrPaymentFailed            = errors.New("payment failed")
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+
+	ErrAdjustmentsUnavailable   = errors.New("fee adjustments are not configured")
+	ErrAdjustmentNotFound       = errors.New("fee adjustment not found")
+	ErrAdjustmentReasonRequired = errors.New("a reason is required for a fee adjustment")
+	ErrInvalidAdjustmentAmount  = errors.New("adjustment amount must be positive")
+	ErrAdjustmentExceedsFee     = errors.New("adjustment exceeds the fee left on the ticket")
+	ErrTicketNotClosed          = errors.New("only closed tickets can be adjusted")
+	ErrInvalidAdjustmentState   = errors.New("fee adjustment is not in a state that allows this")
+	ErrAdjustmentApprovalDenied = errors.New("only a supervisor can review fee adjustments")
+	ErrSelfReview               = errors.New("a fee adjustment can't be reviewed by the user who requested it")
+	ErrRefundFailed             = errors.New("refund failed")
+	ErrInvalidReportRange       = errors.New("report start must be before its end")
+	ErrReceiptNotReady          = errors.New("a receipt is only available after exit")
+	ErrTicketClosed             = errors.New("ticket is already closed")
+	ErrWrongCurrency            = errors.New("amount must be in the lot's currency")
+	ErrExchangeRateMissing      = errors.New("no exchange rate to convert the report")
 )
 
 func Wrap(content string, err error) error {
@@ -0,0 +1,37 @@
+package parking
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// store runs fn, which makes a change, in a transaction if there is a
+// Transactor, and adds the events reporting the change to the Outbox, if
+// there is one, in the same transaction.
+func (s *ParkingService) store(ctx context.Context, events []domain.Event, fn func(ctx context.Context) error) error {
+	work := func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		if s.Outbox != nil {
+			if err := s.Outbox.AddEvents(ctx, events...); err != nil {
+				return ErrOutboxWriteFailed
+			}
+		}
+		return nil
+	}
+	if s.Transactor == nil {
+		return work(ctx)
+	}
+	return s.Transactor.WithinTx(ctx, work)
+}
+
+// publish tells Events about a stored change, if Events is set.
+func (s *ParkingService) publish(ctx context.Context, events ...domain.Event) {
+	if s.Events == nil {
+		return
+	}
+	for _, event := range events {
+		s.Events.Publish(ctx, event)
+	}
+}
@@ -0,0 +1,155 @@
+package parking
+
+import (
+	"context"
+	"errors"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedEvents []domain.Event
+
+func (r *recordedEvents) Publish(ctx context.Context, event domain.Event) {
+	*r = append(*r, event)
+}
+
+func (r recordedEvents) types() []string {
+	types := make([]string, len(r))
+	for i, event := range r {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestChangesArePublished(t *testing.T) {
+	service := NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	service.PaymentGateways = cashGateways()
+	service.LedgerRepo = inmemmory.NewLedgerInMemmory()
+	events := &recordedEvents{}
+	service.Events = events
+
+	assert.NoError(t, service.AddSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}))
+	ticket, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.NoError(t, err)
+	_, err = service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	_, err = service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB9999", VehicleType: "car"})
+	assert.NoError(t, err)
+	_, err = service.ForceUnparkVehicle(ctx, "UP16AB9999", "barrier lifted manually")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		domain.EventSlotAdded,
+		domain.EventTicketOpened, domain.EventSlotOccupied,
+		domain.EventTicketClosed, domain.EventSlotFreed,
+		domain.EventTicketOpened, domain.EventSlotOccupied,
+		domain.EventTicketClosed, domain.EventSlotFreed,
+	}, events.types())
+
+	opened, closed := (*events)[1], (*events)[3]
+	assert.Equal(t, ticket.TicketId, opened.TicketId)
+	assert.Equal(t, "UP16AB1234", opened.VehicleNumber)
+	assert.Equal(t, "car", opened.SlotType)
+	assert.Nil(t, opened.Fee)
+	assert.NotNil(t, closed.Fee)
+	assert.NotEqual(t, opened.ID, closed.ID)
+}
+
+func TestFailedChangesAreNotPublished(t *testing.T) {
+	service := NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	events := &recordedEvents{}
+	service.Events = events
+
+	_, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.Error(t, err)
+	assert.Empty(t, *events)
+}
+
+// recordingTransactor runs work like a transaction would and records how
+// each one ended.
+type recordingTransactor struct {
+	results []error
+}
+
+func (r *recordingTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	r.results = append(r.results, err)
+	return err
+}
+
+type failingOutbox struct {
+	*inmemmory.OutboxInMemmory
+}
+
+func (failingOutbox) AddEvents(ctx context.Context, events ...domain.Event) error {
+	return errors.New("disk full")
+}
+
+func TestChangesAreStoredInTheOutbox(t *testing.T) {
+	service := NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	service.PaymentGateways = cashGateways()
+	outbox := inmemmory.NewOutboxInMemmory()
+	tx := &recordingTransactor{}
+	service.Outbox = outbox
+	service.Transactor = tx
+	events := &recordedEvents{}
+	service.Events = events
+
+	assert.NoError(t, service.AddSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}))
+	_, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.NoError(t, err)
+	_, err = service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []error{nil, nil, nil}, tx.results)
+	messages, err := outbox.ListUnpublished(ctx, 10, time.Now())
+	assert.NoError(t, err)
+	stored := make(recordedEvents, len(messages))
+	for i, message := range messages {
+		stored[i] = message.Event
+	}
+	assert.Equal(t, []string{
+		domain.EventSlotAdded,
+		domain.EventTicketOpened, domain.EventSlotOccupied,
+		domain.EventTicketClosed, domain.EventSlotFreed,
+	}, stored.types())
+	assert.Equal(t, *events, stored, "Events is told about the same events once they are stored")
+}
+
+func TestOutboxFailureFailsTheChange(t *testing.T) {
+	service := NewParkingService(inmemmory.NewSlotInMemmory(), inmemmory.NewTicketInMemmory())
+	assert.NoError(t, service.AddSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}))
+	tx := &recordingTransactor{}
+	service.Outbox = failingOutbox{inmemmory.NewOutboxInMemmory()}
+	service.Transactor = tx
+	events := &recordedEvents{}
+	service.Events = events
+
+	_, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.ErrorIs(t, err, ErrOutboxWriteFailed)
+	assert.Equal(t, []error{ErrOutboxWriteFailed}, tx.results, "the transaction is rolled back")
+	assert.Empty(t, *events)
+}
+
+func TestGetAvailability(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	service := NewParkingService(slotRepo, inmemmory.NewTicketInMemmory())
+	for _, slot := range []domain.Slot{
+		{SlotId: 1, SlotType: "car", IsFree: true},
+		{SlotId: 2, SlotType: "car", IsFree: false},
+		{SlotId: 3, SlotType: "bike", IsFree: true},
+	} {
+		assert.NoError(t, slotRepo.SaveSlot(ctx, slot))
+	}
+
+	availability, err := service.GetAvailability(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]domain.SlotAvailability{
+		"car":  {Total: 2, Free: 1},
+		"bike": {Total: 1, Free: 1},
+	}, availability)
+}
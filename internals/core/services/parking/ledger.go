@@ -0,0 +1,166 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/audit"
+	"time"
+)
+
+// checkUnpaidBalance returns the vehicle's outstanding balance and refuses
+// entry when it is above MaxUnpaidBalance.
+func (s *ParkingService) checkUnpaidBalance(ctx context.Context, vehicleNumber string) (domain.Money, error) {
+	if s.LedgerRepo == nil {
+		return domain.Money{}, nil
+	}
+	balance, err := s.LedgerRepo.GetBalance(ctx, vehicleNumber)
+	if err != nil {
+		return domain.Money{}, ErrBalanceCheck
+	}
+	if err := balance.CheckCurrency(s.MaxUnpaidBalance); err != nil {
+		return domain.Money{}, err
+	}
+	if s.MaxUnpaidBalance.IsPositive() && balance.Cmp(s.MaxUnpaidBalance) > 0 {
+		reason := fmt.Sprintf("unpaid balance %s over limit %s", balance, s.MaxUnpaidBalance)
+		if err := s.recordRejection(ctx, vehicleNumber, reason); err != nil {
+			return domain.Money{}, err
+		}
+		return domain.Money{}, ErrUnpaidBalanceExceeded
+	}
+	return balance, nil
+}
+
+// ForceUnparkVehicle lets a vehicle out without collecting the fee, e.g.
+// when the barrier was lifted manually. The fee is added to the vehicle's
+// unpaid balance.
+func (s *ParkingService) ForceUnparkVehicle(ctx context.Context, vehicleNumber, reason string) (domain.Money, error) {
+	if s.LedgerRepo == nil {
+		return domain.Money{}, ErrLedgerUnavailable
+	}
+	ticket, slot, err := s.findOpenTicket(ctx, vehicleNumber)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	before := *ticket
+	exitTime := time.Now()
+	fee, err := s.exitFee(ctx, ticket, slot.SlotType, exitTime)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	err = s.closeTicket(ctx, ticket, slot, exitTime, fee, nil, func(ctx context.Context) error {
+		return s.recordUnpaid(ctx, ticket, fee.Total, reason)
+	})
+	if err != nil {
+		return domain.Money{}, err
+	}
+	entry := domain.NewAuditEntry(ctx, domain.AuditTicketForceUnpark, ticketTarget(ticket.TicketId), before, ticket)
+	entry.Detail = reason
+	audit.RecordEntry(ctx, s.AuditLog, entry)
+	return fee.Total, nil
+}
+
+func (s *ParkingService) recordUnpaid(ctx context.Context, ticket *domain.Ticket, fee domain.Money, reason string) error {
+	if !fee.IsPositive() {
+		return nil
+	}
+	entry := domain.LedgerEntry{
+		EntryId:       GenerateTicketID(),
+		VehicleNumber: ticket.VehicleNumber,
+		TicketId:      ticket.TicketId,
+		Kind:          domain.LedgerUnpaid,
+		Amount:        fee,
+		Note:          reason,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.LedgerRepo.SaveLedgerEntry(ctx, entry); err != nil {
+		return Wrap("failed to record unpaid fee", err)
+	}
+	return nil
+}
+
+func (s *ParkingService) GetUnpaidBalance(ctx context.Context, vehicleNumber string) (domain.Money, error) {
+	if s.LedgerRepo == nil {
+		return domain.Money{}, ErrLedgerUnavailable
+	}
+	number, err := s.NormaliseVehicleNumber(vehicleNumber)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	balance, err := s.LedgerRepo.GetBalance(ctx, number)
+	if err != nil {
+		return domain.Money{}, ErrBalanceCheck
+	}
+	return balance, nil
+}
+
+func (s *ParkingService) GetLedgerEntries(ctx context.Context, vehicleNumber string) ([]domain.LedgerEntry, error) {
+	if s.LedgerRepo == nil {
+		return nil, ErrLedgerUnavailable
+	}
+	number, err := s.NormaliseVehicleNumber(vehicleNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.LedgerRepo.ListLedgerEntries(ctx, number)
+}
+
+// SettleBalance collects amount through the payment gateway for
+// payment.Method and records it against the vehicle's unpaid balance. It
+// returns what is still owed.
+func (s *ParkingService) SettleBalance(ctx context.Context, vehicleNumber string, amount domain.Money, payment domain.PaymentRequest) (domain.Money, error) {
+	balance, err := s.GetUnpaidBalance(ctx, vehicleNumber)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if !amount.IsPositive() {
+		return domain.Money{}, ErrInvalidSettlementAmount
+	}
+	if err := s.checkCurrency(amount); err != nil {
+		return domain.Money{}, err
+	}
+	if err := amount.CheckCurrency(balance); err != nil {
+		return domain.Money{}, err
+	}
+	if amount.Cmp(balance) > 0 {
+		return domain.Money{}, ErrSettlementExceedsBalance
+	}
+	number, _ := s.NormaliseVehicleNumber(vehicleNumber)
+	payment.Amount = amount
+	paid, err := s.collectPayment(payment)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	entry := domain.LedgerEntry{
+		EntryId:          GenerateTicketID(),
+		VehicleNumber:    number,
+		Kind:             domain.LedgerSettlement,
+		Amount:           amount.Neg(),
+		CreatedAt:        time.Now(),
+		PaymentMethod:    paid.Method,
+		PaymentReference: paid.Reference,
+	}
+	err = s.store(ctx, nil, func(ctx context.Context) error {
+		// Another settlement may have been recorded since the balance was
+		// read, so check it again with the entries locked.
+		if balance, err = s.LedgerRepo.LockBalance(ctx, number); err != nil {
+			return ErrBalanceCheck
+		}
+		if err := amount.CheckCurrency(balance); err != nil {
+			return err
+		}
+		if amount.Cmp(balance) > 0 {
+			return ErrSettlementExceedsBalance
+		}
+		if err := s.LedgerRepo.SaveLedgerEntry(ctx, entry); err != nil {
+			return Wrap("failed to record settlement", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.refundPayment(paid)
+		return domain.Money{}, err
+	}
+	s.audit(ctx, domain.AuditLedgerSettle, vehicleTarget(number), balance, balance.Sub(amount))
+	return balance.Sub(amount), nil
+}
@@ -0,0 +1,149 @@
+package parking
+
+import (
+	"context"
+	"errors"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLedgerService() (*ParkingService, *inmemmory.TicketInMemmory) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: false})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{
+		TicketId:      1,
+		VehicleNumber: "UP16AB1234",
+		SlotId:        1,
+		EntryTime:     time.Now().Add(-2 * time.Hour),
+	})
+	service := NewParkingService(slotRepo, ticketRepo)
+	service.LedgerRepo = inmemmory.NewLedgerInMemmory()
+	service.VehicleListRepo = inmemmory.NewVehicleListInMemmory()
+	service.PaymentGateways = cashGateways()
+	return service, ticketRepo
+}
+
+var cashPayment = domain.PaymentRequest{Method: domain.PaymentCash}
+
+func TestForceUnparkVehicle_RecordsUnpaidFee(t *testing.T) {
+	service, _ := newLedgerService()
+
+	fee, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "barrier lifted manually")
+	assert.NoError(t, err)
+	assert.InDelta(t, 12000, fee.Amount, 10)
+
+	balance, err := service.GetUnpaidBalance(ctx, "up16 ab 1234")
+	assert.NoError(t, err)
+	assert.Equal(t, fee, balance)
+
+	entries, err := service.GetLedgerEntries(ctx, "UP16AB1234")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, domain.LedgerUnpaid, entries[0].Kind)
+	assert.Equal(t, "barrier lifted manually", entries[0].Note)
+
+	ticket, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.NoError(t, err)
+	assert.Equal(t, fee, ticket.OutstandingBalance)
+}
+
+type failingLedger struct {
+	*inmemmory.LedgerInMemmory
+}
+
+func (failingLedger) SaveLedgerEntry(ctx context.Context, entry domain.LedgerEntry) error {
+	return errors.New("disk full")
+}
+
+func TestForceUnparkVehicle_LedgerFailureRollsBackExit(t *testing.T) {
+	service, _ := newLedgerService()
+	service.LedgerRepo = failingLedger{inmemmory.NewLedgerInMemmory()}
+	tx := &recordingTransactor{}
+	service.Transactor = tx
+
+	_, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "barrier lifted manually")
+	assert.ErrorContains(t, err, "failed to record unpaid fee")
+	assert.Equal(t, []error{err}, tx.results, "the exit is rolled back with the ledger entry")
+}
+
+// settledElsewhere reports a balance that another settlement has already
+// paid off once the entries are locked.
+type settledElsewhere struct {
+	*inmemmory.LedgerInMemmory
+}
+
+func (settledElsewhere) LockBalance(ctx context.Context, vehiclenumber string) (domain.Money, error) {
+	return inr(0), nil
+}
+
+func TestSettleBalance_RechecksLockedBalance(t *testing.T) {
+	service, _ := newLedgerService()
+	ledger := inmemmory.NewLedgerInMemmory()
+	service.LedgerRepo = settledElsewhere{ledger}
+	tx := &recordingTransactor{}
+	service.Transactor = tx
+	_, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "")
+	assert.NoError(t, err)
+
+	_, err = service.SettleBalance(ctx, "UP16AB1234", inr(20), cashPayment)
+	assert.ErrorIs(t, err, ErrSettlementExceedsBalance)
+	assert.ErrorIs(t, tx.results[len(tx.results)-1], ErrSettlementExceedsBalance)
+
+	entries, _ := ledger.ListLedgerEntries(ctx, "UP16AB1234")
+	assert.Len(t, entries, 1, "no settlement is recorded")
+}
+
+func TestParkVehicle_UnpaidBalanceOverLimit(t *testing.T) {
+	service, _ := newLedgerService()
+	service.MaxUnpaidBalance = inr(100)
+
+	_, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "payment failure")
+	assert.NoError(t, err)
+
+	_, err = service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.ErrorIs(t, err, ErrUnpaidBalanceExceeded)
+
+	rejections, _ := service.GetEntryRejections(ctx)
+	assert.Len(t, rejections, 1)
+
+	_, err = service.SettleBalance(ctx, "UP16AB1234", inr(50), cashPayment)
+	assert.NoError(t, err)
+
+	_, err = service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.NoError(t, err)
+}
+
+func TestSettleBalance(t *testing.T) {
+	service, _ := newLedgerService()
+	fee, _ := service.ForceUnparkVehicle(ctx, "UP16AB1234", "")
+
+	_, err := service.SettleBalance(ctx, "UP16AB1234", inr(0), cashPayment)
+	assert.ErrorIs(t, err, ErrInvalidSettlementAmount)
+
+	_, err = service.SettleBalance(ctx, "UP16AB1234", fee.Add(inr(1)), cashPayment)
+	assert.ErrorIs(t, err, ErrSettlementExceedsBalance)
+
+	_, err = service.SettleBalance(ctx, "UP16AB1234", inr(1), domain.PaymentRequest{Method: "cheque"})
+	assert.ErrorIs(t, err, ErrUnsupportedPaymentMethod)
+
+	remaining, err := service.SettleBalance(ctx, "UP16AB1234", inr(20), cashPayment)
+	assert.NoError(t, err)
+	assert.Equal(t, fee.Sub(inr(20)), remaining)
+
+	remaining, err = service.SettleBalance(ctx, "UP16AB1234", remaining, cashPayment)
+	assert.NoError(t, err)
+	assert.True(t, remaining.IsZero())
+
+	entries, _ := service.GetLedgerEntries(ctx, "UP16AB1234")
+	assert.Len(t, entries, 3)
+	assert.Equal(t, domain.PaymentCash, entries[1].PaymentMethod)
+	assert.NotEmpty(t, entries[1].PaymentReference)
+
+	_, err = NewParkingService(nil, nil).ForceUnparkVehicle(ctx, "UP16AB1234", "")
+	assert.ErrorIs(t, err, ErrLedgerUnavailable)
+}
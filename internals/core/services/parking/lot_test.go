@@ -0,0 +1,84 @@
+package parking
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/currency"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func usdLot() domain.Lot {
+	return domain.Lot{
+		Name:        "Downtown",
+		Currency:    "USD",
+		Locale:      "en-US",
+		HourlyRates: map[string]domain.Money{"car": domain.NewMoney(400, "USD")},
+	}
+}
+
+func TestLotCurrencyChange_OldBalanceDoesNotPanic(t *testing.T) {
+	service, _ := newLedgerService()
+	service.MaxUnpaidBalance = inr(100)
+	_, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "barrier lifted")
+	assert.NoError(t, err)
+
+	service.Lot = usdLot()
+	service.MaxUnpaidBalance = domain.NewMoney(5000, "USD")
+	_, err = service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+	_, err = service.SettleBalance(ctx, "UP16AB1234", domain.NewMoney(100, "USD"), cashPayment)
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+}
+
+func TestForeignLot_ChargesInLotCurrency(t *testing.T) {
+	service, _ := newLedgerService()
+	service.Lot = usdLot()
+
+	fee, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "barrier lifted")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Currency("USD"), fee.Currency)
+	assert.InDelta(t, 800, fee.Amount, 5)
+
+	_, err = service.SettleBalance(ctx, "UP16AB1234", inr(1), cashPayment)
+	assert.ErrorIs(t, err, ErrWrongCurrency)
+
+	remaining, err := service.SettleBalance(ctx, "UP16AB1234", fee, cashPayment)
+	assert.NoError(t, err)
+	assert.True(t, remaining.IsZero())
+
+	receipt, err := service.GetReceipt(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Downtown", receipt.LotName)
+	assert.Equal(t, currency.Format(fee, "en-US"), receipt.Formatted["total"])
+	assert.Contains(t, receipt.Formatted["total"], "$")
+}
+
+func TestGetRevenueReport_ConvertsToBaseCurrency(t *testing.T) {
+	service, _ := newLedgerService()
+	service.Lot = usdLot()
+	from := time.Now().Add(-time.Minute)
+
+	fee, err := service.ForceUnparkVehicle(ctx, "UP16AB1234", "barrier lifted")
+	assert.NoError(t, err)
+
+	report, err := service.GetRevenueReport(ctx, from, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Currency("USD"), report.Currency)
+	assert.Equal(t, fee, report.GrossFees)
+	assert.Nil(t, report.Base)
+
+	service.ExchangeRates = &currency.RateTable{Base: "INR", Rates: map[domain.Currency]float64{"USD": 80}}
+	report, err = service.GetRevenueReport(ctx, from, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(fee.Amount*80, "INR"), report.Base.GrossFees)
+	assert.Equal(t, report.Base.GrossFees, report.Base.ByPaymentMethod[unpaidMethod])
+
+	// A ticket recorded in another currency needs a rate to be totalled.
+	service.Lot = domain.DefaultLot()
+	service.Lot.Currency = "EUR"
+	_, err = service.GetRevenueReport(ctx, from, time.Now().Add(time.Minute))
+	assert.ErrorIs(t, err, ErrExchangeRateMissing)
+}
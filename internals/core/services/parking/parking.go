@@ -1,26 +1,97 @@
 package parking
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/currency"
+	"parkingSlotManagement/internals/core/services/plate"
+	"parkingSlotManagement/internals/core/services/tax"
 	"parkingSlotManagement/internals/ports"
+	"sort"
 	"time"
 )
 
 type ParkingService struct {
-	SlotRepo   ports.SlotRepository
-	TicketRepo ports.TicketRepository
+	// Lot sets the currency, locale and tariffs of the site.
+	Lot             domain.Lot
+	SlotRepo        ports.SlotRepository
+	TicketRepo      ports.TicketRepository
+	PlateValidator  plate.Validator
+	VehicleListRepo ports.VehicleListRepository
+	LedgerRepo      ports.LedgerRepository
+	// PaymentGateways maps a payment method such as "cash" or "card" to
+	// the gateway that collects it.
+	PaymentGateways map[string]ports.PaymentGateway
+	AdjustmentRepo  ports.AdjustmentRepository
+	// TaxCalculator adds tax to tariffs at exit. Without one fees are
+	// untaxed.
+	TaxCalculator *tax.Calculator
+	// FeeRounding settles tariffs that fall between two paise. Empty means
+	// half-up.
+	FeeRounding domain.Rounding
+	// MaxUnpaidBalance refuses entry to vehicles owing more than this
+	// amount. Zero disables the check.
+	MaxUnpaidBalance domain.Money
+	// ExchangeRates converts report totals to a base currency. Optional.
+	ExchangeRates *currency.RateTable
+	// AuditLog records every change made through the service. Optional.
+	AuditLog ports.AuditLog
+	// Events is told when slots are added, occupied or freed and when
+	// tickets open or close. Optional.
+	Events ports.EventPublisher
+	// Outbox stores those events in the same transaction as the change,
+	// so that a crash after the change can't lose them. The outbox relay
+	// publishes them from there; make it Events so that it wakes at once.
+	// Optional.
+	Outbox ports.OutboxRepository
+	// Transactor makes each slot and ticket change, with its outbox
+	// events, a single transaction. Optional.
+	Transactor ports.Transactor
 }
 
 func NewParkingService(s ports.SlotRepository, t ports.TicketRepository) *ParkingService {
 	return &ParkingService{SlotRepo: s,
-		TicketRepo: t,
+		Lot:            domain.DefaultLot(),
+		TicketRepo:     t,
+		PlateValidator: plate.NewIndianValidator(),
 	}
 }
 
-func (s *ParkingService) ParkVehicle(vehicle domain.Vehicle) (*domain.Ticket, error) {
+// NormaliseVehicleNumber returns the canonical form of a vehicle number, or
+// an error wrapping plate.ErrInvalidPlate if it isn't a valid registration.
+func (s *ParkingService) NormaliseVehicleNumber(raw string) (string, error) {
+	return plate.Parse(raw, s.PlateValidator)
+}
+
+// SlotTypes lists the slot types the lot has a tariff for, sorted.
+func (s *ParkingService) SlotTypes() []string {
+	types := make([]string, 0, len(s.Lot.HourlyRates))
+	for slotType := range s.Lot.HourlyRates {
+		types = append(types, slotType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (s *ParkingService) ParkVehicle(ctx context.Context, vehicle domain.Vehicle) (*domain.Ticket, error) {
+	number, err := s.NormaliseVehicleNumber(vehicle.VehicleNumber)
+	if err != nil {
+		return nil, err
+	}
+	vehicle.VehicleNumber = number
+
+	listEntry, err := s.checkVehicleList(ctx, vehicle.VehicleNumber)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := s.checkUnpaidBalance(ctx, vehicle.VehicleNumber)
+	if err != nil {
+		return nil, err
+	}
 
-	existingTicket, err := s.TicketRepo.FindTicketByVehicleNumber(vehicle.VehicleNumber)
+	existingTicket, err := s.TicketRepo.FindTicketByVehicleNumber(ctx, vehicle.VehicleNumber)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, ErrExistingTicketCheck
 	}
@@ -29,7 +100,7 @@ func (s *ParkingService) ParkVehicle(vehicle domain.Vehicle) (*domain.Ticket, er
 		return nil, ErrVehicleAlreadyParked
 	}
 
-	slots, err := s.SlotRepo.FindSlotByType(vehicle.VehicleType)
+	slots, err := s.SlotRepo.FindSlotByType(ctx, vehicle.VehicleType)
 	if err != nil {
 		return nil, ErrSlotListFailed
 	}
@@ -45,18 +116,34 @@ func (s *ParkingService) ParkVehicle(vehicle domain.Vehicle) (*domain.Ticket, er
 		return nil, ErrSlotFetchByType
 	}
 	firstAvailable.IsFree = false
-	if err := s.SlotRepo.UpdateSlot(firstAvailable); err != nil {
-		return nil, ErrSlotUpdateFailed
-	}
+	firstAvailable.UpdatedBy = domain.Actor(ctx)
 	ticket := &domain.Ticket{
 		TicketId:      GenerateTicketID(),
 		VehicleNumber: vehicle.VehicleNumber,
 		SlotId:        firstAvailable.SlotId,
 		EntryTime:     time.Now(),
+		FeeExempt:     listEntry != nil && listEntry.ListType == domain.AllowList,
+		ParkedBy:      domain.Actor(ctx),
+	}
+	ticket.OutstandingBalance = balance
+	events := []domain.Event{
+		domain.NewTicketEvent(domain.EventTicketOpened, *ticket, *firstAvailable),
+		domain.NewSlotEvent(domain.EventSlotOccupied, *firstAvailable),
 	}
-	if err := s.TicketRepo.SaveTicket(*ticket); err != nil {
-		return nil, ErrTicketSaveFailed
+	err = s.store(ctx, events, func(ctx context.Context) error {
+		if err := s.SlotRepo.UpdateSlot(ctx, firstAvailable); err != nil {
+			return ErrSlotUpdateFailed
+		}
+		if err := s.TicketRepo.SaveTicket(ctx, *ticket); err != nil {
+			return ErrTicketSaveFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.audit(ctx, domain.AuditTicketPark, ticketTarget(ticket.TicketId), nil, ticket)
+	s.publish(ctx, events...)
 	return ticket, nil
 
 }
@@ -66,63 +153,195 @@ func GenerateTicketID() int64 {
 
 }
 
-func (s *ParkingService) UnparkVehicle(VehicleNumber string) (float64, error) {
+// UnparkVehicle completes an exit: it collects the fee through the payment
+// gateway for payment.Method and only frees the slot once the payment has
+// been captured. Use QuoteExit first to show the fee to the driver.
+func (s *ParkingService) UnparkVehicle(ctx context.Context, VehicleNumber string, payment domain.PaymentRequest) (*domain.Ticket, error) {
+	ticket, slot, err := s.findOpenTicket(ctx, VehicleNumber)
+	if err != nil {
+		return nil, err
+	}
+	before := *ticket
 	ExitTime := time.Now()
-	ticket, err := s.TicketRepo.FindTicketByVehicleNumber(VehicleNumber)
+	fee, err := s.exitFee(ctx, ticket, slot.SlotType, ExitTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var paid *domain.Payment
+	if fee.Total.IsPositive() {
+		payment.TicketId = ticket.TicketId
+		payment.Amount = fee.Total
+		if paid, err = s.collectPayment(payment); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.closeTicket(ctx, ticket, slot, ExitTime, fee, paid, nil); err != nil {
+		if paid != nil {
+			s.refundPayment(paid)
+		}
+		return nil, err
+	}
+	s.audit(ctx, domain.AuditTicketUnpark, ticketTarget(ticket.TicketId), before, ticket)
+	return ticket, nil
+
+}
+
+// findOpenTicket returns the vehicle's open ticket and the slot it occupies.
+func (s *ParkingService) findOpenTicket(ctx context.Context, VehicleNumber string) (*domain.Ticket, *domain.Slot, error) {
+	VehicleNumber, err := s.NormaliseVehicleNumber(VehicleNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	ticket, err := s.TicketRepo.FindTicketByVehicleNumber(ctx, VehicleNumber)
 	if err != nil || ticket == nil {
-		return 0, ErrTicketNotFound
+		return nil, nil, ErrTicketNotFound
 	}
-	slot, err := s.SlotRepo.FindSlotByID(ticket.SlotId)
+	slot, err := s.SlotRepo.FindSlotByID(ctx, ticket.SlotId)
 
 	if err != nil || slot == nil {
-		return 0, ErrSlotNotFound
+		return nil, nil, ErrSlotNotFound
 	}
+	return ticket, slot, nil
+}
 
-	fee, err := s.CalculateFee(ticket.SlotId, ticket.EntryTime, ExitTime)
+// exitFee prices the stay and applies tax to the tariff.
+func (s *ParkingService) exitFee(ctx context.Context, ticket *domain.Ticket, slotType string, ExitTime time.Time) (domain.FeeBreakdown, error) {
+	if ticket.FeeExempt {
+		zero := domain.Money{Currency: s.Lot.Currency}
+		return domain.FeeBreakdown{Net: zero, Tax: zero, Total: zero}, nil
+	}
+	fee, err := s.CalculateFee(ctx, ticket.SlotId, ticket.EntryTime, ExitTime)
 	if err != nil {
-		return 0, ErrFeeCalculationFailed
+		return domain.FeeBreakdown{}, ErrFeeCalculationFailed
+	}
+	if s.TaxCalculator == nil {
+		return domain.FeeBreakdown{Net: fee, Tax: domain.Money{Currency: fee.Currency}, Total: fee}, nil
 	}
+	return s.TaxCalculator.Apply(slotType, fee), nil
+}
 
+// closeTicket frees the slot and records the exit on the ticket.
+// closeTicket frees the slot and closes the ticket. also, if given, runs
+// first in the same transaction so that whatever it records is kept or
+// lost together with the exit.
+func (s *ParkingService) closeTicket(ctx context.Context, ticket *domain.Ticket, slot *domain.Slot, ExitTime time.Time, fee domain.FeeBreakdown, paid *domain.Payment, also func(ctx context.Context) error) error {
 	slot.IsFree = true
-	if err := s.SlotRepo.UpdateSlot(slot); err != nil {
-		return 0, ErrSlotUpdateFailed
+	slot.UpdatedBy = domain.Actor(ctx)
+	ticket.ExitTime = &ExitTime
+	ticket.ClosedBy = domain.Actor(ctx)
+	ticket.Fee = fee.Total
+	ticket.NetFee = fee.Net
+	ticket.Tax = fee.Tax
+	ticket.TaxLines = fee.TaxLines
+	if paid != nil {
+		ticket.PaymentMethod = paid.Method
+		ticket.PaymentReference = paid.Reference
 	}
-
-	if err = s.TicketRepo.DeleteTicket(ticket.TicketId); err != nil {
-		return 0, ErrTicketDeleteFailed
+	events := []domain.Event{
+		domain.NewTicketEvent(domain.EventTicketClosed, *ticket, *slot),
+		domain.NewSlotEvent(domain.EventSlotFreed, *slot),
+	}
+	err := s.store(ctx, events, func(ctx context.Context) error {
+		if also != nil {
+			if err := also(ctx); err != nil {
+				return err
+			}
+		}
+		if err := s.SlotRepo.UpdateSlot(ctx, slot); err != nil {
+			return ErrSlotUpdateFailed
+		}
+		if err := s.TicketRepo.CloseTicket(ctx, *ticket); err != nil {
+			return ErrTicketCloseFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.publish(ctx, events...)
+	return nil
+}
+func (s *ParkingService) AddSlot(ctx context.Context, slot domain.Slot) error {
+	slot.UpdatedBy = domain.Actor(ctx)
+	event := domain.NewSlotEvent(domain.EventSlotAdded, slot)
+	err := s.store(ctx, []domain.Event{event}, func(ctx context.Context) error {
+		return s.SlotRepo.SaveSlot(ctx, slot)
+	})
+	if err != nil {
+		return err
 	}
+	s.audit(ctx, domain.AuditSlotAdd, fmt.Sprintf("slot:%d", slot.SlotId), nil, slot)
+	s.publish(ctx, event)
+	return nil
 
-	return fee, nil
+}
 
+// GetSlot returns one slot.
+func (s *ParkingService) GetSlot(ctx context.Context, slotId int) (*domain.Slot, error) {
+	slot, err := s.SlotRepo.FindSlotByID(ctx, slotId)
+	if err != nil {
+		return nil, Wrap("failed to find slot", err)
+	}
+	return slot, nil
 }
-func (s *ParkingService) AddSlot(slot domain.Slot) error {
-	err := s.SlotRepo.SaveSlot(slot)
-	return err
 
+// GetSlots returns every slot, free or not, by slot ID.
+func (s *ParkingService) GetSlots(ctx context.Context) ([]domain.Slot, error) {
+	slots, err := s.SlotRepo.ListSlots(ctx)
+	if err != nil {
+		return nil, ErrSlotListFailed
+	}
+	return slots, nil
 }
-func (s *ParkingService) GetAvailableSlots() ([]domain.Slot, error) {
-	slots, err := s.SlotRepo.ListAvailableSlots()
+
+// GetAvailability counts the slots of each type, and how many are free.
+func (s *ParkingService) GetAvailability(ctx context.Context) (map[string]domain.SlotAvailability, error) {
+	slots, err := s.GetSlots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	availability := map[string]domain.SlotAvailability{}
+	for _, slot := range slots {
+		counts := availability[slot.SlotType]
+		counts.Total++
+		if slot.IsFree {
+			counts.Free++
+		}
+		availability[slot.SlotType] = counts
+	}
+	return availability, nil
+}
+
+func (s *ParkingService) GetAvailableSlots(ctx context.Context) ([]domain.Slot, error) {
+	slots, err := s.SlotRepo.ListAvailableSlots(ctx)
 	if err != nil {
 		return nil, ErrSlotListFailed
 	}
 	return slots, nil
 }
 
-func (s *ParkingService) CalculateFee(SlotId int, EntryTime time.Time, ExistTime time.Time) (float64, error) {
-	slottype, err := s.SlotRepo.FindSlotTypebyID(SlotId)
+// CalculateFee charges the lot's hourly rate for the slot type pro rata
+// for the stay, rounded to the minor unit with FeeRounding.
+func (s *ParkingService) CalculateFee(ctx context.Context, SlotId int, EntryTime time.Time, ExistTime time.Time) (domain.Money, error) {
+	slottype, err := s.SlotRepo.FindSlotTypebyID(ctx, SlotId)
 	if err != nil {
-		return 0, err
+		return domain.Money{}, err
 	}
 	duration := ExistTime.Sub(EntryTime)
 
-	switch slottype {
-	case "car":
-		fee := duration.Hours() * 60
-		return fee, nil
-	case "bike":
-		fee := duration.Hours() * 30
-		return fee, nil
-	default:
-		return 0, ErrInvalidVehicleType
+	hourly, ok := s.Lot.HourlyRates[slottype]
+	if !ok {
+		return domain.Money{}, ErrInvalidVehicleType
+	}
+	return hourly.MulRat(int64(duration), int64(time.Hour), s.FeeRounding), nil
+}
+
+// checkCurrency rejects amounts that are not in the lot's currency.
+func (s *ParkingService) checkCurrency(amount domain.Money) error {
+	if amount.Currency != s.Lot.Currency {
+		return fmt.Errorf("%w: %s", ErrWrongCurrency, s.Lot.Currency)
 	}
+	return nil
 }
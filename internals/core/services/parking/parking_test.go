@@ -1,10 +1,14 @@
 package parking
 
 import (
+	"context"
 	"database/sql"
 
+	"parkingSlotManagement/internals/adapters/payments"
 	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
 	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/plate"
+	"parkingSlotManagement/internals/ports"
 
 	"testing"
 	"time"
@@ -12,6 +16,16 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var ctx = context.Background()
+
+func inr(rupees int64) domain.Money {
+	return domain.NewMoney(rupees*100, domain.CurrencyINR)
+}
+
+func cashGateways() map[string]ports.PaymentGateway {
+	return map[string]ports.PaymentGateway{domain.PaymentCash: payments.NewCashGateway()}
+}
+
 func TestParkVehicle(t *testing.T) {
 	slotrepo := inmemmory.NewSlotInMemmory()
 	ticketrepo := inmemmory.NewTicketInMemmory()
@@ -20,7 +34,7 @@ func TestParkVehicle(t *testing.T) {
 		SlotType: "car",
 		IsFree:   true,
 	}
-	err := slotrepo.SaveSlot(slot)
+	err := slotrepo.SaveSlot(ctx, slot)
 	assert.NoError(t, err)
 	service := NewParkingService(slotrepo, ticketrepo)
 	vehicle := domain.Vehicle{
@@ -28,9 +42,9 @@ func TestParkVehicle(t *testing.T) {
 		VehicleType:   "car",
 	}
 
-	ticket, err := service.ParkVehicle(vehicle)
+	ticket, err := service.ParkVehicle(ctx, vehicle)
 
-	err1 := ticketrepo.SaveTicket(*ticket)
+	err1 := ticketrepo.SaveTicket(ctx, *ticket)
 	assert.NoError(t, err1)
 
 	assert.NoError(t, err)
@@ -39,7 +53,7 @@ func TestParkVehicle(t *testing.T) {
 	assert.Equal(t, vehicle.VehicleNumber, ticket.VehicleNumber)
 	assert.Equal(t, slot.SlotId, ticket.SlotId)
 
-	ticket2, err2 := service.ParkVehicle(vehicle)
+	ticket2, err2 := service.ParkVehicle(ctx, vehicle)
 	assert.Error(t, err2)
 	assert.Nil(t, ticket2)
 	assert.Contains(t, err2.Error(), "already parked")
@@ -64,7 +78,7 @@ func TestUnparkVehicle(t *testing.T) {
 		IsFree:   true,
 	}
 
-	_ = slotRepo.SaveSlot(slot)
+	_ = slotRepo.SaveSlot(ctx, slot)
 
 	entryTime := time.Now().Add(-2 * time.Hour)
 
@@ -75,18 +89,20 @@ func TestUnparkVehicle(t *testing.T) {
 		EntryTime:     entryTime,
 	}
 
-	_ = ticketRepo.SaveTicket(ticket)
+	_ = ticketRepo.SaveTicket(ctx, ticket)
 
 	service := NewParkingService(slotRepo, ticketRepo)
-	fee, err := service.UnparkVehicle("UP74M8311")
+	service.PaymentGateways = cashGateways()
+	closed, err := service.UnparkVehicle(ctx, "UP74M8311", domain.PaymentRequest{Method: domain.PaymentCash})
 
 	assert.NoError(t, err)
-	assert.Greater(t, fee, float64(0))
+	assert.True(t, closed.Fee.IsPositive())
+	assert.NotEmpty(t, closed.PaymentReference)
 
-	updatedSlot, _ := slotRepo.FindSlotByID(1)
+	updatedSlot, _ := slotRepo.FindSlotByID(ctx, 1)
 	assert.True(t, updatedSlot.IsFree)
 
-	_, err = ticketRepo.FindTicketByVehicleNumber("UP74M8311")
+	_, err = ticketRepo.FindTicketByVehicleNumber(ctx, "UP74M8311")
 	assert.ErrorIs(t, err, sql.ErrNoRows)
 
 	slot1 := domain.Slot{
@@ -94,19 +110,19 @@ func TestUnparkVehicle(t *testing.T) {
 		SlotType: "bike",
 		IsFree:   true,
 	}
-	_ = slotRepo.SaveSlot(slot1)
+	_ = slotRepo.SaveSlot(ctx, slot1)
 	ticket1 := domain.Ticket{
 		TicketId:      123456987654321,
 		VehicleNumber: "UP74M8412",
 		SlotId:        2,
 		EntryTime:     entryTime,
 	}
-	_ = ticketRepo.SaveTicket(ticket1)
-	fee1, err := service.UnparkVehicle("UP74M8412")
+	_ = ticketRepo.SaveTicket(ctx, ticket1)
+	closed1, err := service.UnparkVehicle(ctx, "UP74M8412", domain.PaymentRequest{Method: domain.PaymentCash})
 	assert.NoError(t, err)
-	assert.Greater(t, fee1, float64(0))
+	assert.True(t, closed1.Fee.IsPositive())
 
-	updatedSlot1, _ := slotRepo.FindSlotByID(2)
+	updatedSlot1, _ := slotRepo.FindSlotByID(ctx, 2)
 	assert.True(t, updatedSlot1.IsFree)
 
 }
@@ -118,7 +134,7 @@ func TestAddSlot(t *testing.T) {
 		SlotType: "car",
 		IsFree:   true,
 	}
-	err := service.AddSlot(slot)
+	err := service.AddSlot(ctx, slot)
 	assert.NoError(t, err)
 
 }
@@ -132,9 +148,9 @@ func TestGetAvailableSlots(t *testing.T) {
 	}
 	for _, slot := range slots {
 
-		slotRepo.SaveSlot(slot)
+		slotRepo.SaveSlot(ctx, slot)
 	}
-	availableSlots, err := service.GetAvailableSlots()
+	availableSlots, err := service.GetAvailableSlots(ctx)
 	assert.NoError(t, err)
 	assert.Len(t, availableSlots, 2)
 	for _, slot := range availableSlots {
@@ -148,7 +164,7 @@ func TestOpposingCases(t *testing.T) {
 		name        string
 		ticket      *domain.Ticket
 		slot        *domain.Slot
-		expectedFee float64
+		expectedFee int64
 		expectError bool
 		errorText   string
 	}{
@@ -163,7 +179,7 @@ func TestOpposingCases(t *testing.T) {
 			name: "slot not found",
 			ticket: &domain.Ticket{
 				TicketId:      1,
-				VehicleNumber: "XYZ123",
+				VehicleNumber: "MH12XY1234",
 				SlotId:        101,
 				EntryTime:     time.Now().Add(-2 * time.Hour),
 			},
@@ -175,7 +191,7 @@ func TestOpposingCases(t *testing.T) {
 			name: "invalid slot type",
 			ticket: &domain.Ticket{
 				TicketId:      1,
-				VehicleNumber: "XYZ123",
+				VehicleNumber: "MH12XY1234",
 				SlotId:        101,
 				EntryTime:     time.Now().Add(-2 * time.Hour),
 			},
@@ -191,7 +207,7 @@ func TestOpposingCases(t *testing.T) {
 			name: "success case",
 			ticket: &domain.Ticket{
 				TicketId:      1,
-				VehicleNumber: "XYZ123",
+				VehicleNumber: "MH12XY1234",
 				SlotId:        101,
 				EntryTime:     time.Now().Add(-2 * time.Hour),
 			},
@@ -201,7 +217,7 @@ func TestOpposingCases(t *testing.T) {
 				SlotType: "car",
 			},
 
-			expectedFee: 120.0,
+			expectedFee: 12000,
 			expectError: false,
 		},
 	}
@@ -211,24 +227,99 @@ func TestOpposingCases(t *testing.T) {
 			ticketRepo := inmemmory.NewTicketInMemmory()
 			slotRepo := inmemmory.NewSlotInMemmory()
 			service := NewParkingService(slotRepo, ticketRepo)
+			service.PaymentGateways = cashGateways()
 
 			if tt.ticket != nil {
-				ticketRepo.SaveTicket(*tt.ticket)
+				ticketRepo.SaveTicket(ctx, *tt.ticket)
 			}
 
 			if tt.slot != nil {
-				slotRepo.SaveSlot(*tt.slot)
+				slotRepo.SaveSlot(ctx, *tt.slot)
 			}
 
-			fee, err := service.UnparkVehicle("XYZ123")
+			closed, err := service.UnparkVehicle(ctx, "MH12XY1234", domain.PaymentRequest{})
 
 			if tt.expectError {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errorText)
 			} else {
 				assert.NoError(t, err)
-				assert.InDelta(t, tt.expectedFee, fee, 0.1)
+				assert.InDelta(t, tt.expectedFee, closed.Fee.Amount, 10)
 			}
 		})
 	}
 }
+
+func TestParkVehicle_NormalisesVehicleNumber(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true})
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 2, SlotType: "car", IsFree: true})
+	service := NewParkingService(slotRepo, ticketRepo)
+
+	ticket, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "up16 ab 1234", VehicleType: "car"})
+	assert.NoError(t, err)
+	assert.Equal(t, "UP16AB1234", ticket.VehicleNumber)
+
+	_, err = service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP-16-AB-1234", VehicleType: "car"})
+	assert.ErrorIs(t, err, ErrVehicleAlreadyParked)
+
+	_, err = service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "FAIL123", VehicleType: "car"})
+	assert.ErrorIs(t, err, plate.ErrInvalidPlate)
+
+	_, err = service.UnparkVehicle(ctx, "", domain.PaymentRequest{})
+	assert.ErrorIs(t, err, plate.ErrEmptyPlate)
+}
+
+func TestCalculateFee_Rounding(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: false})
+	service := NewParkingService(slotRepo, inmemmory.NewTicketInMemmory())
+	entry := time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)
+
+	// One second of a ₹60/hour tariff is 1.67 paise.
+	fee, err := service.CalculateFee(ctx, 1, entry, entry.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(2, domain.CurrencyINR), fee)
+
+	service.FeeRounding = domain.RoundDown
+	fee, err = service.CalculateFee(ctx, 1, entry, entry.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(1, domain.CurrencyINR), fee)
+
+	fee, err = service.CalculateFee(ctx, 1, entry, entry.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, inr(90), fee)
+}
+
+func TestActorIsRecorded(t *testing.T) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	service := NewParkingService(slotRepo, ticketRepo)
+	service.PaymentGateways = cashGateways()
+
+	admin := domain.WithUser(ctx, &domain.User{Username: "admin", Role: domain.RoleAdmin})
+	attendant := domain.WithUser(ctx, &domain.User{Username: "ravi", Role: domain.RoleAttendant})
+	cashier := domain.WithUser(ctx, &domain.User{Username: "meena", Role: domain.RoleAttendant})
+
+	assert.NoError(t, service.AddSlot(admin, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true}))
+	slot, _ := slotRepo.FindSlotByID(ctx, 1)
+	assert.Equal(t, "admin", slot.UpdatedBy)
+
+	ticket, err := service.ParkVehicle(attendant, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ravi", ticket.ParkedBy)
+	slot, _ = slotRepo.FindSlotByID(ctx, 1)
+	assert.Equal(t, "ravi", slot.UpdatedBy)
+
+	closed, err := service.UnparkVehicle(cashier, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	assert.Equal(t, "ravi", closed.ParkedBy)
+	assert.Equal(t, "meena", closed.ClosedBy)
+	slot, _ = slotRepo.FindSlotByID(ctx, 1)
+	assert.Equal(t, "meena", slot.UpdatedBy)
+
+	assert.NoError(t, service.AddSlot(ctx, domain.Slot{SlotId: 2, SlotType: "bike", IsFree: true}))
+	slot, _ = slotRepo.FindSlotByID(ctx, 2)
+	assert.Equal(t, domain.SystemActor, slot.UpdatedBy)
+}
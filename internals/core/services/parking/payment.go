@@ -0,0 +1,75 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"time"
+)
+
+// QuoteExit returns the fee the vehicle would pay if it left now, without
+// freeing the slot or closing the ticket.
+func (s *ParkingService) QuoteExit(ctx context.Context, VehicleNumber string) (*domain.ExitQuote, error) {
+	ticket, slot, err := s.findOpenTicket(ctx, VehicleNumber)
+	if err != nil {
+		return nil, err
+	}
+	quotedAt := time.Now()
+	fee, err := s.exitFee(ctx, ticket, slot.SlotType, quotedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ExitQuote{
+		TicketId:      ticket.TicketId,
+		VehicleNumber: ticket.VehicleNumber,
+		SlotId:        ticket.SlotId,
+		EntryTime:     ticket.EntryTime,
+		QuotedAt:      quotedAt,
+		Fee:           fee.Total,
+		Breakdown:     fee,
+	}, nil
+}
+
+func (s *ParkingService) paymentGateway(method string) (ports.PaymentGateway, error) {
+	gateway, ok := s.PaymentGateways[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedPaymentMethod, method)
+	}
+	return gateway, nil
+}
+
+// collectPayment authorises and captures the payment. Cash is assumed when
+// no method is given.
+func (s *ParkingService) collectPayment(req domain.PaymentRequest) (*domain.Payment, error) {
+	if req.Method == "" {
+		req.Method = domain.PaymentCash
+	}
+	gateway, err := s.paymentGateway(req.Method)
+	if err != nil {
+		return nil, err
+	}
+	authorised, err := gateway.Authorise(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPaymentFailed, err)
+	}
+	captured, err := gateway.Capture(authorised.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPaymentFailed, err)
+	}
+	return captured, nil
+}
+
+// refundPayment gives the money back when the exit could not be completed
+// after the payment was captured.
+func (s *ParkingService) refundPayment(paid *domain.Payment) {
+	gateway, err := s.paymentGateway(paid.Method)
+	if err != nil {
+		log.Printf("cannot refund payment %s: %v", paid.Reference, err)
+		return
+	}
+	if _, err := gateway.Refund(paid.Reference, paid.Amount); err != nil {
+		log.Printf("cannot refund payment %s: %v", paid.Reference, err)
+	}
+}
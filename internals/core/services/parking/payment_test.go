@@ -0,0 +1,88 @@
+package parking
+
+import (
+	"parkingSlotManagement/internals/adapters/payments"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/ports"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newPaymentService() (*ParkingService, *inmemmory.SlotInMemmory, *payments.FakeCardGateway) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: false})
+	ticketRepo.SaveTicket(ctx, domain.Ticket{
+		TicketId:      1,
+		VehicleNumber: "UP16AB1234",
+		SlotId:        1,
+		EntryTime:     time.Now().Add(-2 * time.Hour),
+	})
+	card := payments.NewFakeCardGateway()
+	service := NewParkingService(slotRepo, ticketRepo)
+	service.PaymentGateways = map[string]ports.PaymentGateway{
+		domain.PaymentCash: payments.NewCashGateway(),
+		domain.PaymentCard: card,
+	}
+	return service, slotRepo, card
+}
+
+func TestQuoteExit(t *testing.T) {
+	service, slotRepo, _ := newPaymentService()
+
+	quote, err := service.QuoteExit(ctx, "up16ab1234")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), quote.TicketId)
+	assert.InDelta(t, 12000, quote.Fee.Amount, 10)
+
+	slot, _ := slotRepo.FindSlotByID(ctx, 1)
+	assert.False(t, slot.IsFree)
+
+	_, err = service.QuoteExit(ctx, "DL3CAF0001")
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+}
+
+func TestUnparkVehicle_CardPayment(t *testing.T) {
+	service, slotRepo, card := newPaymentService()
+
+	ticket, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCard, CardToken: "tok_visa"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentCard, ticket.PaymentMethod)
+	assert.NotNil(t, ticket.ExitTime)
+
+	payment, err := card.Status(ticket.PaymentReference)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PaymentCaptured, payment.Status)
+	assert.Equal(t, ticket.Fee, payment.Amount)
+
+	slot, _ := slotRepo.FindSlotByID(ctx, 1)
+	assert.True(t, slot.IsFree)
+}
+
+func TestUnparkVehicle_PaymentFailureKeepsVehicleParked(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment domain.PaymentRequest
+		err     error
+	}{
+		{"card declined", domain.PaymentRequest{Method: domain.PaymentCard, CardToken: payments.FakeCardDeclineToken}, ErrPaymentFailed},
+		{"capture fails", domain.PaymentRequest{Method: domain.PaymentCard, CardToken: payments.FakeCardCaptureFailToken}, ErrPaymentFailed},
+		{"unknown method", domain.PaymentRequest{Method: "cheque"}, ErrUnsupportedPaymentMethod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, slotRepo, _ := newPaymentService()
+
+			_, err := service.UnparkVehicle(ctx, "UP16AB1234", tt.payment)
+			assert.ErrorIs(t, err, tt.err)
+
+			slot, _ := slotRepo.FindSlotByID(ctx, 1)
+			assert.False(t, slot.IsFree)
+			_, err = service.QuoteExit(ctx, "UP16AB1234")
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package parking
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/currency"
+)
+
+// GetReceipt returns the receipt for a closed ticket, including its tax
+// lines.
+func (s *ParkingService) GetReceipt(ctx context.Context, ticketId int64) (*domain.Receipt, error) {
+	ticket, err := s.TicketRepo.FindTicketByID(ctx, ticketId)
+	if err != nil || ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.ExitTime == nil {
+		return nil, ErrReceiptNotReady
+	}
+	format := func(m domain.Money) string { return currency.Format(m, s.Lot.Locale) }
+	formatted := map[string]string{
+		"netfee": format(ticket.NetFee),
+		"tax":    format(ticket.Tax),
+		"total":  format(ticket.Fee),
+	}
+	for _, line := range ticket.TaxLines {
+		formatted[line.Name] = format(line.Amount)
+	}
+	return &domain.Receipt{
+		LotName:          s.Lot.Name,
+		TicketId:         ticket.TicketId,
+		VehicleNumber:    ticket.VehicleNumber,
+		SlotId:           ticket.SlotId,
+		EntryTime:        ticket.EntryTime,
+		ExitTime:         *ticket.ExitTime,
+		NetFee:           ticket.NetFee,
+		TaxLines:         ticket.TaxLines,
+		Tax:              ticket.Tax,
+		Total:            ticket.Fee,
+		PaymentMethod:    ticket.PaymentMethod,
+		PaymentReference: ticket.PaymentReference,
+		Formatted:        formatted,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package parking
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/tax"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnparkVehicle_AddsGST(t *testing.T) {
+	service, _, _ := newPaymentService()
+	calc, err := tax.NewCalculator(tax.Config{Default: tax.GST(18, false)})
+	assert.NoError(t, err)
+	service.TaxCalculator = calc
+	from := time.Now().Add(-time.Minute)
+
+	quote, err := service.QuoteExit(ctx, "UP16AB1234")
+	assert.NoError(t, err)
+	assert.InDelta(t, 14160, quote.Fee.Amount, 20)
+	assert.Equal(t, quote.Fee, quote.Breakdown.Total)
+
+	ticket, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	assert.Equal(t, ticket.Fee, ticket.NetFee.Add(ticket.Tax))
+	assert.Len(t, ticket.TaxLines, 2)
+
+	receipt, err := service.GetReceipt(ctx, ticket.TicketId)
+	assert.NoError(t, err)
+	assert.Equal(t, ticket.Fee, receipt.Total)
+	assert.Equal(t, ticket.TaxLines, receipt.TaxLines)
+	assert.Equal(t, ticket.PaymentReference, receipt.PaymentReference)
+
+	report, err := service.GetRevenueReport(ctx, from, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, ticket.Tax, report.TaxTotal)
+	assert.Equal(t, ticket.NetFee, report.NetFees)
+	assert.Equal(t, ticket.TaxLines[0].Amount, report.TaxSummary["CGST"])
+}
+
+func TestGetReceipt_OpenTicket(t *testing.T) {
+	service, _, _ := newPaymentService()
+
+	_, err := service.GetReceipt(ctx, 1)
+	assert.ErrorIs(t, err, ErrReceiptNotReady)
+
+	_, err = service.GetReceipt(ctx, 99)
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+}
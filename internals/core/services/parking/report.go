@@ -0,0 +1,153 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+)
+
+// unpaidMethod groups fees of tickets closed without a payment, such as
+// forced exits, in the per-method breakdown.
+const unpaidMethod = "unpaid"
+
+// GetRevenueReport totals the fees of tickets closed in [from, to) and
+// subtracts adjustments applied in the same period. Totals are in the lot's
+// currency; amounts recorded in another currency are converted with
+// ExchangeRates.
+func (s *ParkingService) GetRevenueReport(ctx context.Context, from, to time.Time) (*domain.RevenueReport, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidReportRange
+	}
+	tickets, err := s.TicketRepo.ListClosedTickets(ctx, from, to)
+	if err != nil {
+		return nil, Wrap("failed to fetch closed tickets", err)
+	}
+
+	zero := domain.Money{Currency: s.Lot.Currency}
+	report := &domain.RevenueReport{
+		From:            from,
+		To:              to,
+		Currency:        s.Lot.Currency,
+		GrossFees:       zero,
+		NetFees:         zero,
+		TaxTotal:        zero,
+		Adjustments:     zero,
+		ByPaymentMethod: make(map[string]domain.Money),
+		TaxSummary:      make(map[string]domain.Money),
+	}
+	add := func(total *domain.Money, amount domain.Money) error {
+		converted, err := s.convert(amount, s.Lot.Currency)
+		if err != nil {
+			return err
+		}
+		*total = total.Add(converted)
+		return nil
+	}
+	for _, ticket := range tickets {
+		report.TicketCount++
+		if err := add(&report.GrossFees, ticket.Fee); err != nil {
+			return nil, err
+		}
+		if err := add(&report.NetFees, ticket.NetFee); err != nil {
+			return nil, err
+		}
+		if err := add(&report.TaxTotal, ticket.Tax); err != nil {
+			return nil, err
+		}
+		for _, line := range ticket.TaxLines {
+			total := report.TaxSummary[line.Name]
+			if err := add(&total, line.Amount); err != nil {
+				return nil, err
+			}
+			report.TaxSummary[line.Name] = total
+		}
+		if ticket.Fee.IsZero() {
+			continue
+		}
+		method := ticket.PaymentMethod
+		if method == "" {
+			method = unpaidMethod
+		}
+		total := report.ByPaymentMethod[method]
+		if err := add(&total, ticket.Fee); err != nil {
+			return nil, err
+		}
+		report.ByPaymentMethod[method] = total
+	}
+
+	if s.AdjustmentRepo != nil {
+		applied, err := s.AdjustmentRepo.ListAdjustments(ctx, domain.AdjustmentApplied)
+		if err != nil {
+			return nil, Wrap("failed to fetch fee adjustments", err)
+		}
+		for _, adj := range applied {
+			if adj.AppliedAt != nil && !adj.AppliedAt.Before(from) && adj.AppliedAt.Before(to) {
+				if err := add(&report.Adjustments, adj.Amount); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	report.NetRevenue = report.GrossFees.Sub(report.Adjustments)
+	if s.ExchangeRates != nil && s.ExchangeRates.Base != s.Lot.Currency {
+		if report.Base, err = s.convertReport(report, s.ExchangeRates.Base); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// convert returns amount in currency to using ExchangeRates.
+func (s *ParkingService) convert(amount domain.Money, to domain.Currency) (domain.Money, error) {
+	if amount.Currency == to || amount.Currency == "" {
+		return domain.Money{Amount: amount.Amount, Currency: to}, nil
+	}
+	if s.ExchangeRates == nil {
+		return domain.Money{}, fmt.Errorf("%w: %s to %s", ErrExchangeRateMissing, amount.Currency, to)
+	}
+	converted, err := s.ExchangeRates.Convert(amount, to)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("%w: %s to %s", ErrExchangeRateMissing, amount.Currency, to)
+	}
+	return converted, nil
+}
+
+// convertReport restates every total of report in currency to. Each total
+// is converted on its own, so converted parts may differ from the converted
+// whole by a minor unit.
+func (s *ParkingService) convertReport(report *domain.RevenueReport, to domain.Currency) (*domain.RevenueReport, error) {
+	converted := &domain.RevenueReport{
+		From:            report.From,
+		To:              report.To,
+		TicketCount:     report.TicketCount,
+		Currency:        to,
+		ByPaymentMethod: make(map[string]domain.Money),
+		TaxSummary:      make(map[string]domain.Money),
+	}
+	var err error
+	fields := []struct{ from, to *domain.Money }{
+		{&report.GrossFees, &converted.GrossFees},
+		{&report.NetFees, &converted.NetFees},
+		{&report.TaxTotal, &converted.TaxTotal},
+		{&report.Adjustments, &converted.Adjustments},
+		{&report.NetRevenue, &converted.NetRevenue},
+	}
+	for _, field := range fields {
+		if *field.to, err = s.convert(*field.from, to); err != nil {
+			return nil, err
+		}
+	}
+	for _, totals := range []struct{ from, to map[string]domain.Money }{
+		{report.ByPaymentMethod, converted.ByPaymentMethod},
+		{report.TaxSummary, converted.TaxSummary},
+	} {
+		for key, amount := range totals.from {
+			if totals.to[key], err = s.convert(amount, to); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return converted, nil
+}
@@ -0,0 +1,64 @@
+package parking
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// GetTicket returns a ticket, open or closed.
+func (s *ParkingService) GetTicket(ctx context.Context, ticketId int64) (*domain.Ticket, error) {
+	ticket, err := s.TicketRepo.FindTicketByID(ctx, ticketId)
+	if err != nil || ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	return ticket, nil
+}
+
+// GetOpenTickets returns the tickets of vehicles parked now, oldest first.
+func (s *ParkingService) GetOpenTickets(ctx context.Context) ([]domain.Ticket, error) {
+	tickets, err := s.TicketRepo.ListOpenTickets(ctx)
+	if err != nil {
+		return nil, Wrap("failed to list open tickets", err)
+	}
+	return tickets, nil
+}
+
+// openTicketVehicle returns the vehicle on an open ticket, so exits by
+// ticket can reuse the exits by vehicle number.
+func (s *ParkingService) openTicketVehicle(ctx context.Context, ticketId int64) (string, error) {
+	ticket, err := s.GetTicket(ctx, ticketId)
+	if err != nil {
+		return "", err
+	}
+	if ticket.ExitTime != nil {
+		return "", ErrTicketClosed
+	}
+	return ticket.VehicleNumber, nil
+}
+
+// QuoteTicketExit is QuoteExit for the vehicle on an open ticket.
+func (s *ParkingService) QuoteTicketExit(ctx context.Context, ticketId int64) (*domain.ExitQuote, error) {
+	number, err := s.openTicketVehicle(ctx, ticketId)
+	if err != nil {
+		return nil, err
+	}
+	return s.QuoteExit(ctx, number)
+}
+
+// ExitTicket is UnparkVehicle for the vehicle on an open ticket.
+func (s *ParkingService) ExitTicket(ctx context.Context, ticketId int64, payment domain.PaymentRequest) (*domain.Ticket, error) {
+	number, err := s.openTicketVehicle(ctx, ticketId)
+	if err != nil {
+		return nil, err
+	}
+	return s.UnparkVehicle(ctx, number, payment)
+}
+
+// ForceExitTicket is ForceUnparkVehicle for the vehicle on an open ticket.
+func (s *ParkingService) ForceExitTicket(ctx context.Context, ticketId int64, reason string) (domain.Money, error) {
+	number, err := s.openTicketVehicle(ctx, ticketId)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return s.ForceUnparkVehicle(ctx, number, reason)
+}
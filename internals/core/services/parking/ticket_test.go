@@ -0,0 +1,33 @@
+package parking
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitByTicket(t *testing.T) {
+	service, _ := newLedgerService()
+	service.PaymentGateways = cashGateways()
+
+	_, err := service.GetTicket(ctx, 42)
+	assert.ErrorIs(t, err, ErrTicketNotFound)
+
+	quote, err := service.QuoteTicketExit(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "UP16AB1234", quote.VehicleNumber)
+
+	ticket, err := service.ExitTicket(ctx, 1, domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.NoError(t, err)
+	assert.NotNil(t, ticket.ExitTime)
+
+	_, err = service.ExitTicket(ctx, 1, domain.PaymentRequest{Method: domain.PaymentCash})
+	assert.ErrorIs(t, err, ErrTicketClosed)
+	_, err = service.ForceExitTicket(ctx, 1, "")
+	assert.ErrorIs(t, err, ErrTicketClosed)
+
+	closed, err := service.GetTicket(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, ticket.Fee, closed.Fee)
+}
@@ -0,0 +1,105 @@
+package parking
+
+import (
+	"context"
+	"database/sql"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+)
+
+// checkVehicleList returns the vehicle's list entry, if any, and refuses
+// blocklisted vehicles. Every refusal is recorded as an EntryRejection.
+func (s *ParkingService) checkVehicleList(ctx context.Context, vehicleNumber string) (*domain.VehicleListEntry, error) {
+	if s.VehicleListRepo == nil {
+		return nil, nil
+	}
+	entry, err := s.VehicleListRepo.FindEntry(ctx, vehicleNumber)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, ErrVehicleListCheck
+	}
+	if entry == nil || entry.ListType != domain.BlockList {
+		return entry, nil
+	}
+
+	if err := s.recordRejection(ctx, vehicleNumber, entry.Reason); err != nil {
+		return nil, err
+	}
+	return nil, ErrVehicleBlocklisted
+}
+
+func (s *ParkingService) recordRejection(ctx context.Context, vehicleNumber, reason string) error {
+	if s.VehicleListRepo == nil {
+		return nil
+	}
+	rejection := domain.EntryRejection{
+		VehicleNumber: vehicleNumber,
+		Reason:        reason,
+		RejectedAt:    time.Now(),
+	}
+	if err := s.VehicleListRepo.SaveRejection(ctx, rejection); err != nil {
+		return Wrap("failed to record entry rejection", err)
+	}
+	return nil
+}
+
+func (s *ParkingService) AddVehicleListEntry(ctx context.Context, entry domain.VehicleListEntry) (*domain.VehicleListEntry, error) {
+	if s.VehicleListRepo == nil {
+		return nil, ErrVehicleListUnavailable
+	}
+	if entry.ListType != domain.BlockList && entry.ListType != domain.AllowList {
+		return nil, ErrInvalidListType
+	}
+	number, err := s.NormaliseVehicleNumber(entry.VehicleNumber)
+	if err != nil {
+		return nil, err
+	}
+	entry.VehicleNumber = number
+	entry.CreatedAt = time.Now()
+	before, err := s.VehicleListRepo.FindEntry(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.VehicleListRepo.SaveEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, domain.AuditVehicleListAdd, vehicleTarget(number), before, entry)
+	return &entry, nil
+}
+
+func (s *ParkingService) RemoveVehicleListEntry(ctx context.Context, vehicleNumber string) error {
+	if s.VehicleListRepo == nil {
+		return ErrVehicleListUnavailable
+	}
+	number, err := s.NormaliseVehicleNumber(vehicleNumber)
+	if err != nil {
+		return err
+	}
+	before, err := s.VehicleListRepo.FindEntry(ctx, number)
+	if err != nil {
+		return err
+	}
+	if err := s.VehicleListRepo.DeleteEntry(ctx, number); err != nil {
+		return err
+	}
+	s.audit(ctx, domain.AuditVehicleListRemove, vehicleTarget(number), before, nil)
+	return nil
+}
+
+// GetVehicleListEntries lists entries of one list type, or of both when
+// listType is empty.
+func (s *ParkingService) GetVehicleListEntries(ctx context.Context, listType string) ([]domain.VehicleListEntry, error) {
+	if s.VehicleListRepo == nil {
+		return nil, ErrVehicleListUnavailable
+	}
+	if listType != "" && listType != domain.BlockList && listType != domain.AllowList {
+		return nil, ErrInvalidListType
+	}
+	return s.VehicleListRepo.ListEntries(ctx, listType)
+}
+
+func (s *ParkingService) GetEntryRejections(ctx context.Context) ([]domain.EntryRejection, error) {
+	if s.VehicleListRepo == nil {
+		return nil, ErrVehicleListUnavailable
+	}
+	return s.VehicleListRepo.ListRejections(ctx)
+}
@@ -0,0 +1,91 @@
+package parking
+
+import (
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVehicleListService() (*ParkingService, *inmemmory.TicketInMemmory, *inmemmory.VehicleListInMemmory) {
+	slotRepo := inmemmory.NewSlotInMemmory()
+	ticketRepo := inmemmory.NewTicketInMemmory()
+	listRepo := inmemmory.NewVehicleListInMemmory()
+	slotRepo.SaveSlot(ctx, domain.Slot{SlotId: 1, SlotType: "car", IsFree: true})
+	service := NewParkingService(slotRepo, ticketRepo)
+	service.VehicleListRepo = listRepo
+	return service, ticketRepo, listRepo
+}
+
+func TestParkVehicle_Blocklisted(t *testing.T) {
+	service, _, _ := newVehicleListService()
+
+	_, err := service.AddVehicleListEntry(ctx, domain.VehicleListEntry{
+		VehicleNumber: "up16 ab 1234",
+		ListType:      domain.BlockList,
+		Reason:        "unpaid fines",
+	})
+	assert.NoError(t, err)
+
+	ticket, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.ErrorIs(t, err, ErrVehicleBlocklisted)
+	assert.Nil(t, ticket)
+
+	rejections, err := service.GetEntryRejections(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, rejections, 1)
+	assert.Equal(t, "UP16AB1234", rejections[0].VehicleNumber)
+	assert.Equal(t, "unpaid fines", rejections[0].Reason)
+
+	slots, _ := service.GetAvailableSlots(ctx)
+	assert.Len(t, slots, 1)
+}
+
+func TestParkVehicle_AllowlistedExitsFree(t *testing.T) {
+	service, ticketRepo, _ := newVehicleListService()
+
+	_, err := service.AddVehicleListEntry(ctx, domain.VehicleListEntry{
+		VehicleNumber: "UP16AB1234",
+		ListType:      domain.AllowList,
+		Reason:        "staff",
+	})
+	assert.NoError(t, err)
+
+	ticket, err := service.ParkVehicle(ctx, domain.Vehicle{VehicleNumber: "UP16AB1234", VehicleType: "car"})
+	assert.NoError(t, err)
+	assert.True(t, ticket.FeeExempt)
+
+	ticket.EntryTime = time.Now().Add(-3 * time.Hour)
+	ticketRepo.SaveTicket(ctx, *ticket)
+
+	closed, err := service.UnparkVehicle(ctx, "UP16AB1234", domain.PaymentRequest{})
+	assert.NoError(t, err)
+	assert.True(t, closed.Fee.IsZero())
+	assert.Empty(t, closed.PaymentReference)
+}
+
+func TestVehicleListEntries(t *testing.T) {
+	service, _, _ := newVehicleListService()
+
+	_, err := service.AddVehicleListEntry(ctx, domain.VehicleListEntry{VehicleNumber: "UP16AB1234", ListType: "grey"})
+	assert.ErrorIs(t, err, ErrInvalidListType)
+
+	service.AddVehicleListEntry(ctx, domain.VehicleListEntry{VehicleNumber: "UP16AB1234", ListType: domain.BlockList})
+	service.AddVehicleListEntry(ctx, domain.VehicleListEntry{VehicleNumber: "DL3CAF0001", ListType: domain.AllowList})
+
+	all, err := service.GetVehicleListEntries(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	blocked, err := service.GetVehicleListEntries(ctx, domain.BlockList)
+	assert.NoError(t, err)
+	assert.Len(t, blocked, 1)
+
+	assert.NoError(t, service.RemoveVehicleListEntry(ctx, "UP16AB1234"))
+	assert.Error(t, service.RemoveVehicleListEntry(ctx, "UP16AB1234"))
+
+	_, err = NewParkingService(nil, nil).GetVehicleListEntries(ctx, "")
+	assert.ErrorIs(t, err, ErrVehicleListUnavailable)
+}
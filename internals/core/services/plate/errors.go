@@ -0,0 +1,8 @@
+package plate
+
+import "errors"
+
+var (
+	ErrEmptyPlate   = errors.New("vehicle number is required")
+	ErrInvalidPlate = errors.New("invalid vehicle number")
+)
@@ -0,0 +1,63 @@
+package plate
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	// State code, RTO district, optional series and a 1-4 digit number, e.g. UP16AB1234.
+	indianRTOPattern = regexp.MustCompile(`^([A-Z]{2})([0-9]{1,2})([A-Z]{0,3})([0-9]{1,4})$`)
+	// Bharat series: registration year, "BH", number and series, e.g. 22BH1234AA.
+	indianBHPattern = regexp.MustCompile(`^[0-9]{2}BH[0-9]{4}[A-Z]{1,2}$`)
+)
+
+var indianStateCodes = map[string]bool{
+	"AN": true, "AP": true, "AR": true, "AS": true, "BR": true, "CG": true,
+	"CH": true, "DD": true, "DL": true, "DN": true, "GA": true, "GJ": true,
+	"HP": true, "HR": true, "JH": true, "JK": true, "KA": true, "KL": true,
+	"LA": true, "LD": true, "MH": true, "ML": true, "MN": true, "MP": true,
+	"MZ": true, "NL": true, "OD": true, "OR": true, "PB": true, "PY": true,
+	"RJ": true, "SK": true, "TN": true, "TR": true, "TS": true, "UA": true,
+	"UK": true, "UP": true, "WB": true,
+}
+
+// IndianValidator validates plates issued by Indian RTOs, including the
+// Bharat (BH) series. States narrows the accepted state codes; when empty
+// every Indian state and union territory is allowed.
+type IndianValidator struct {
+	States []string
+}
+
+func NewIndianValidator(states ...string) *IndianValidator {
+	return &IndianValidator{States: states}
+}
+
+func (v *IndianValidator) Validate(plate string) error {
+	if indianBHPattern.MatchString(plate) {
+		return nil
+	}
+	m := indianRTOPattern.FindStringSubmatch(plate)
+	if m == nil {
+		return fmt.Errorf("%w %q: does not match Indian RTO format", ErrInvalidPlate, plate)
+	}
+	if !v.allowsState(m[1]) {
+		return fmt.Errorf("%w %q: unknown state code %s", ErrInvalidPlate, plate, m[1])
+	}
+	if m[2] == "0" || m[2] == "00" {
+		return fmt.Errorf("%w %q: invalid RTO code", ErrInvalidPlate, plate)
+	}
+	return nil
+}
+
+func (v *IndianValidator) allowsState(code string) bool {
+	if len(v.States) == 0 {
+		return indianStateCodes[code]
+	}
+	for _, s := range v.States {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
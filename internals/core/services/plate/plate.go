@@ -0,0 +1,71 @@
+package plate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Validator checks that an already normalised plate matches a registration format.
+type Validator interface {
+	Validate(plate string) error
+}
+
+// ValidatorFunc lets a plain function be used as a Validator.
+type ValidatorFunc func(plate string) error
+
+func (f ValidatorFunc) Validate(plate string) error {
+	return f(plate)
+}
+
+// Normalise upper-cases a plate and drops whitespace and separators, so
+// "up16 ab-1234" and "UP16AB1234" compare equal.
+func Normalise(raw string) string {
+	var b strings.Builder
+	for _, r := range raw {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// Parse normalises raw and validates it against v. A nil validator only
+// rejects empty plates.
+func Parse(raw string, v Validator) (string, error) {
+	plate := Normalise(raw)
+	if plate == "" {
+		return "", ErrEmptyPlate
+	}
+	if v == nil {
+		return plate, nil
+	}
+	if err := v.Validate(plate); err != nil {
+		return "", err
+	}
+	return plate, nil
+}
+
+type anyOf []Validator
+
+// AnyOf accepts a plate if at least one of the validators does, which is how
+// lots that see vehicles from several countries or states are configured.
+func AnyOf(validators ...Validator) Validator {
+	return anyOf(validators)
+}
+
+func (a anyOf) Validate(plate string) error {
+	var firstErr error
+	for _, v := range a {
+		err := v.Validate(plate)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		return ErrInvalidPlate
+	}
+	return firstErr
+}
@@ -0,0 +1,91 @@
+package plate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalise(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"UP16AB1234", "UP16AB1234"},
+		{"up16 ab 1234", "UP16AB1234"},
+		{" Up-16-Ab-1234 ", "UP16AB1234"},
+		{"dl.3c.af.0001", "DL3CAF0001"},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Normalise(tt.raw))
+		})
+	}
+}
+
+func TestIndianValidator(t *testing.T) {
+	v := NewIndianValidator()
+	tests := []struct {
+		plate string
+		valid bool
+	}{
+		{"UP16AB1234", true},
+		{"UP74M8311", true},
+		{"DL3CAF0001", true},
+		{"KA011234", true},
+		{"22BH1234AA", true},
+		{"XX16AB1234", false},
+		{"UP00AB1234", false},
+		{"UP16ABCD1234", false},
+		{"FAIL123", false},
+		{"1234", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.plate, func(t *testing.T) {
+			err := v.Validate(tt.plate)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidPlate)
+			}
+		})
+	}
+
+	upOnly := NewIndianValidator("UP")
+	assert.NoError(t, upOnly.Validate("UP16AB1234"))
+	assert.ErrorIs(t, upOnly.Validate("DL3CAF0001"), ErrInvalidPlate)
+}
+
+func TestParse(t *testing.T) {
+	plate, err := Parse("up16 ab 1234", NewIndianValidator())
+	assert.NoError(t, err)
+	assert.Equal(t, "UP16AB1234", plate)
+
+	_, err = Parse("  ", NewIndianValidator())
+	assert.ErrorIs(t, err, ErrEmptyPlate)
+
+	_, err = Parse("not a plate", NewIndianValidator())
+	assert.ErrorIs(t, err, ErrInvalidPlate)
+
+	plate, err = Parse("abc-123", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ABC123", plate)
+}
+
+func TestAnyOf(t *testing.T) {
+	errUK := errors.New("not a UK plate")
+	uk := ValidatorFunc(func(plate string) error {
+		if plate == "AB12CDE" {
+			return nil
+		}
+		return errUK
+	})
+	v := AnyOf(NewIndianValidator(), uk)
+
+	assert.NoError(t, v.Validate("UP16AB1234"))
+	assert.NoError(t, v.Validate("AB12CDE"))
+	assert.ErrorIs(t, v.Validate("ZZZ"), ErrInvalidPlate)
+	assert.ErrorIs(t, AnyOf().Validate("UP16AB1234"), ErrInvalidPlate)
+}
@@ -0,0 +1,49 @@
+package tax
+
+import (
+	"os"
+	"parkingSlotManagement/internals/core/domain"
+	"strconv"
+	"strings"
+)
+
+// ConfigFromEnv builds a GST configuration from:
+//
+//	GST_RATE       default rate in percent, e.g. 18
+//	GST_RATES      per slot type overrides, e.g. car=18,bike=12
+//	GST_INCLUSIVE  true when tariffs already include GST
+//	GST_ROUNDING   half-up (default), half-even, up or down
+func ConfigFromEnv() (Config, error) {
+	config := Config{Services: make(map[string]Rule), Rounding: domain.Rounding(os.Getenv("GST_ROUNDING"))}
+	inclusive, _ := strconv.ParseBool(os.Getenv("GST_INCLUSIVE"))
+
+	if value := os.Getenv("GST_RATE"); value != "" {
+		rate, err := parseRate(value)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Default = GST(rate, inclusive)
+	}
+	if value := os.Getenv("GST_RATES"); value != "" {
+		for _, spec := range strings.Split(value, ",") {
+			service, rateStr, ok := strings.Cut(strings.TrimSpace(spec), "=")
+			if !ok || service == "" {
+				return Config{}, ErrInvalidRateSpec
+			}
+			rate, err := parseRate(rateStr)
+			if err != nil {
+				return Config{}, err
+			}
+			config.Services[strings.TrimSpace(service)] = GST(rate, inclusive)
+		}
+	}
+	return config, nil
+}
+
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate < 0 || rate > 100 {
+		return 0, ErrInvalidRate
+	}
+	return rate, nil
+}
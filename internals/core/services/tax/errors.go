@@ -0,0 +1,9 @@
+package tax
+
+import "errors"
+
+var (
+	ErrInvalidRate     = errors.New("tax rate must be between 0 and 100 percent")
+	ErrInvalidRounding = errors.New("unknown rounding mode")
+	ErrInvalidRateSpec = errors.New("tax rates must look like car=18,bike=12")
+)
@@ -0,0 +1,105 @@
+package tax
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// Component is one tax levied on a service, e.g. CGST at 9%.
+type Component struct {
+	Name string
+	Rate float64
+}
+
+// Rule is the set of taxes for a service. Inclusive means tariffs already
+// contain the tax and it is backed out of the fee rather than added on top.
+type Rule struct {
+	Components []Component
+	Inclusive  bool
+}
+
+func (r Rule) rate() float64 {
+	var total float64
+	for _, c := range r.Components {
+		total += c.Rate
+	}
+	return total
+}
+
+// GST returns the intra-state GST rule for a rate given in percent, split
+// equally into CGST and SGST.
+func GST(percent float64, inclusive bool) Rule {
+	half := percent / 200
+	return Rule{
+		Components: []Component{{Name: "CGST", Rate: half}, {Name: "SGST", Rate: half}},
+		Inclusive:  inclusive,
+	}
+}
+
+type Config struct {
+	Default Rule
+	// Services overrides Default per service; for parking the service is
+	// the slot type.
+	Services map[string]Rule
+	Rounding domain.Rounding
+}
+
+type Calculator struct {
+	config Config
+}
+
+func NewCalculator(config Config) (*Calculator, error) {
+	if config.Rounding == "" {
+		config.Rounding = domain.RoundHalfUp
+	}
+	if !config.Rounding.Valid() {
+		return nil, ErrInvalidRounding
+	}
+	rules := []Rule{config.Default}
+	for _, rule := range config.Services {
+		rules = append(rules, rule)
+	}
+	for _, rule := range rules {
+		for _, c := range rule.Components {
+			if c.Rate < 0 || c.Rate > 1 {
+				return nil, ErrInvalidRate
+			}
+		}
+	}
+	return &Calculator{config: config}, nil
+}
+
+func (c *Calculator) rule(service string) Rule {
+	if rule, ok := c.config.Services[service]; ok {
+		return rule
+	}
+	return c.config.Default
+}
+
+// Apply taxes a tariff amount for service. Each tax line is rounded on its
+// own and the total is the sum of the rounded parts, so the receipt always
+// adds up.
+func (c *Calculator) Apply(service string, amount domain.Money) domain.FeeBreakdown {
+	rule := c.rule(service)
+	mode := c.config.Rounding
+
+	net := amount
+	if rule.Inclusive && rule.rate() > 0 {
+		net = amount.Div(1+rule.rate(), mode)
+	}
+
+	breakdown := domain.FeeBreakdown{Net: net, Tax: domain.Money{Currency: amount.Currency}, TaxInclusive: rule.Inclusive}
+	for _, component := range rule.Components {
+		line := domain.TaxLine{Name: component.Name, Rate: component.Rate, Amount: net.Mul(component.Rate, mode)}
+		breakdown.TaxLines = append(breakdown.TaxLines, line)
+		breakdown.Tax = breakdown.Tax.Add(line.Amount)
+	}
+
+	if rule.Inclusive {
+		// The customer pays the tariff; any rounding difference stays in net.
+		breakdown.Total = amount
+		breakdown.Net = amount.Sub(breakdown.Tax)
+	} else {
+		breakdown.Total = net.Add(breakdown.Tax)
+	}
+	return breakdown
+}
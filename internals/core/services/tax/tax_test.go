@@ -0,0 +1,105 @@
+package tax
+
+import (
+	"parkingSlotManagement/internals/core/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func inr(paise int64) domain.Money {
+	return domain.NewMoney(paise, domain.CurrencyINR)
+}
+
+func TestApply_Exclusive(t *testing.T) {
+	calc, err := NewCalculator(Config{Default: GST(18, false)})
+	assert.NoError(t, err)
+
+	breakdown := calc.Apply("car", inr(12000))
+	assert.Equal(t, inr(12000), breakdown.Net)
+	assert.Equal(t, inr(2160), breakdown.Tax)
+	assert.Equal(t, inr(14160), breakdown.Total)
+	assert.Len(t, breakdown.TaxLines, 2)
+	assert.Equal(t, "CGST", breakdown.TaxLines[0].Name)
+	assert.Equal(t, 0.09, breakdown.TaxLines[0].Rate)
+	assert.Equal(t, inr(1080), breakdown.TaxLines[0].Amount)
+}
+
+func TestApply_Inclusive(t *testing.T) {
+	calc, err := NewCalculator(Config{Default: GST(18, true)})
+	assert.NoError(t, err)
+
+	breakdown := calc.Apply("car", inr(11800))
+	assert.Equal(t, inr(10000), breakdown.Net)
+	assert.Equal(t, inr(1800), breakdown.Tax)
+	assert.Equal(t, inr(11800), breakdown.Total)
+	assert.True(t, breakdown.TaxInclusive)
+
+	// The lines and net always add back up to the tariff.
+	breakdown = calc.Apply("car", inr(10000))
+	assert.Equal(t, inr(10000), breakdown.Net.Add(breakdown.Tax))
+}
+
+func TestApply_PerServiceRate(t *testing.T) {
+	calc, err := NewCalculator(Config{
+		Default:  GST(18, false),
+		Services: map[string]Rule{"bike": GST(12, false)},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, inr(600), calc.Apply("bike", inr(5000)).Tax)
+	assert.Equal(t, inr(900), calc.Apply("car", inr(5000)).Tax)
+}
+
+func TestApply_NoTax(t *testing.T) {
+	calc, err := NewCalculator(Config{})
+	assert.NoError(t, err)
+
+	breakdown := calc.Apply("car", inr(6046))
+	assert.Equal(t, inr(6046), breakdown.Net)
+	assert.Equal(t, inr(6046), breakdown.Total)
+	assert.True(t, breakdown.Tax.IsZero())
+	assert.Empty(t, breakdown.TaxLines)
+}
+
+func TestApply_Rounding(t *testing.T) {
+	// 9% of 0.50 is 4.5 paise per line.
+	up, err := NewCalculator(Config{Default: GST(18, false)})
+	assert.NoError(t, err)
+	assert.Equal(t, inr(5), up.Apply("car", inr(50)).TaxLines[0].Amount)
+
+	even, err := NewCalculator(Config{Default: GST(18, false), Rounding: domain.RoundHalfEven})
+	assert.NoError(t, err)
+	assert.Equal(t, inr(4), even.Apply("car", inr(50)).TaxLines[0].Amount)
+}
+
+func TestNewCalculator_Invalid(t *testing.T) {
+	_, err := NewCalculator(Config{Rounding: "sideways"})
+	assert.ErrorIs(t, err, ErrInvalidRounding)
+
+	_, err = NewCalculator(Config{Default: Rule{Components: []Component{{Name: "GST", Rate: 1.5}}}})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("GST_RATE", "18")
+	t.Setenv("GST_RATES", "bike=12, car=28")
+	t.Setenv("GST_INCLUSIVE", "true")
+	t.Setenv("GST_ROUNDING", "half-even")
+
+	config, err := ConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, GST(18, true), config.Default)
+	assert.Equal(t, GST(12, true), config.Services["bike"])
+	assert.Equal(t, GST(28, true), config.Services["car"])
+	assert.Equal(t, domain.RoundHalfEven, config.Rounding)
+
+	t.Setenv("GST_RATES", "bike")
+	_, err = ConfigFromEnv()
+	assert.ErrorIs(t, err, ErrInvalidRateSpec)
+
+	t.Setenv("GST_RATES", "")
+	t.Setenv("GST_RATE", "abc")
+	_, err = ConfigFromEnv()
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
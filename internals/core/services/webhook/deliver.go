@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"parkingSlotManagement/internals/core/domain"
+	"strconv"
+	"time"
+)
+
+// batchSize caps how many due deliveries one pass of the worker sends.
+const batchSize = 50
+
+// maxErrorLength keeps LastError within what the delivery log stores.
+const maxErrorLength = 255
+
+// Run sends due deliveries until ctx is done: at once when an event is
+// queued, and every PollInterval for retries. Run a single worker per
+// database, or deliveries may be sent twice.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.DeliverDue(ctx); err != nil {
+			log.Printf("webhook delivery failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// DeliverDue makes one attempt at every delivery that is due, and returns
+// how many were accepted by their receiver.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		due, err := s.repo.ListDueDeliveries(ctx, time.Now(), batchSize)
+		if err != nil {
+			return delivered, Wrap("failed to list due webhook deliveries", err)
+		}
+		for _, delivery := range due {
+			if ctx.Err() != nil {
+				return delivered, nil
+			}
+			ok, err := s.attempt(ctx, delivery)
+			if err != nil {
+				return delivered, err
+			}
+			if ok {
+				delivered++
+			}
+		}
+		if len(due) < batchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// attempt sends delivery once and records the outcome.
+func (s *Service) attempt(ctx context.Context, delivery domain.WebhookDelivery) (bool, error) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	sub, err := s.repo.FindSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return false, Wrap("failed to find webhook subscription", err)
+	}
+	if sub == nil {
+		delivery.Status = domain.DeliveryDead
+		delivery.LastStatusCode = 0
+		delivery.LastError = "subscription was deleted"
+	} else if status, err := s.send(ctx, *sub, delivery); err == nil {
+		delivery.Status = domain.DeliveryDelivered
+		delivery.LastStatusCode = status
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else if ctx.Err() != nil {
+		// Shutting down: the attempt didn't count.
+		return false, nil
+	} else {
+		delivery.LastStatusCode = status
+		delivery.LastError = truncate(err.Error(), maxErrorLength)
+		if delivery.Attempts >= s.MaxAttempts {
+			delivery.Status = domain.DeliveryDead
+		} else {
+			delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+		}
+	}
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return false, Wrap("failed to record webhook delivery", err)
+	}
+	return delivery.Status == domain.DeliveryDelivered, nil
+}
+
+// send POSTs the payload, signed with the subscription's secret. Any 2xx
+// answer counts as accepted. The status is 0 if no answer came back.
+func (s *Service) send(ctx context.Context, sub domain.WebhookSubscription, delivery domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, delivery.EventID)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff is how long to wait after the given number of failed attempts.
+func (s *Service) backoff(attempts int) time.Duration {
+	wait := s.BaseBackoff
+	for i := 1; i < attempts && wait < s.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, s.MaxBackoff)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
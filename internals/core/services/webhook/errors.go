@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidURL           = errors.New("webhook url must be an absolute http or https url")
+	ErrForbiddenTarget      = errors.New("webhook url must not point at a loopback, link-local or private address")
+	ErrUnresolvableHost     = errors.New("webhook host does not resolve")
+	ErrEventTypesRequired   = errors.New("webhook needs at least one event type")
+	ErrUnknownEventType     = errors.New("unknown event type")
+	ErrSecretTooShort       = fmt.Errorf("webhook secret must be at least %d characters", MinSecretLength)
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrDeliveryNotDead      = errors.New("only dead deliveries can be redelivered")
+	ErrInvalidLimit         = fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+	ErrInvalidStatus        = errors.New("status must be pending, delivered or dead")
+)
+
+func Wrap(content string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", content, err)
+	}
+	return nil
+}
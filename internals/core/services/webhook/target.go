@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, 100.64.0.0/10, which
+// IsPrivate doesn't cover.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// forbiddenIP reports whether ip is one a webhook must not reach: the
+// server itself, the cloud metadata service on 169.254.169.254, or the
+// private network the server runs in.
+func forbiddenIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() || sharedAddressSpace.Contains(ip)
+}
+
+// checkTarget parses rawURL and refuses it unless it is an http or https
+// URL whose host resolves only to public addresses.
+func (s *Service) checkTarget(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if s.AllowPrivateTargets {
+		return nil
+	}
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if forbiddenIP(ip) {
+			return ErrForbiddenTarget
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return Wrap(host, ErrUnresolvableHost)
+	}
+	for _, ip := range addrs {
+		if forbiddenIP(ip) {
+			return ErrForbiddenTarget
+		}
+	}
+	return nil
+}
+
+// newClient returns the delivery client. Its dialer checks the address it
+// is about to connect to, so a host that resolved to a public address at
+// subscribe time and to a private one later, or a redirect to a private
+// address, is still refused. Proxies are not used, as the proxy would
+// connect on the client's behalf.
+func (s *Service) newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: DefaultTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if s.AllowPrivateTargets {
+				return nil
+			}
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if forbiddenIP(addrPort.Addr()) {
+				return Wrap(address, ErrForbiddenTarget)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: DefaultTimeout, Transport: transport}
+}
@@ -0,0 +1,269 @@
+// Package webhook POSTs the parking service's events to subscribed URLs.
+// Events are queued as deliveries when they are published and sent by a
+// background worker, which retries failures with backoff and gives up on
+// a delivery, marking it dead, after MaxAttempts.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"parkingSlotManagement/internals/core/domain"
+	"parkingSlotManagement/internals/core/services/audit"
+	"parkingSlotManagement/internals/ports"
+	"slices"
+	"time"
+)
+
+const (
+	// MinSecretLength applies to secrets chosen by the subscriber.
+	// Generated secrets are 64 hex characters.
+	MinSecretLength = 16
+
+	DefaultMaxAttempts  = 8
+	DefaultBaseBackoff  = 10 * time.Second
+	DefaultMaxBackoff   = time.Hour
+	DefaultPollInterval = 5 * time.Second
+	DefaultTimeout      = 10 * time.Second
+
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Service manages subscriptions and delivers to them. It is a
+// ports.OutboxPublisher: the outbox relay's Send queues a delivery for each
+// event, and Run sends them.
+type Service struct {
+	repo ports.WebhookRepository
+	wake chan struct{}
+
+	Client *http.Client
+	// MaxAttempts is how many times a delivery is tried before it is dead.
+	MaxAttempts int
+	// BaseBackoff is the wait after the first failure. It doubles after
+	// each further failure, up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// PollInterval is how often Run looks for deliveries that are due for
+	// a retry.
+	PollInterval time.Duration
+	// AuditLog records changes to subscriptions. Optional.
+	AuditLog ports.AuditLog
+	// AllowPrivateTargets lets subscriptions and deliveries reach loopback,
+	// link-local and private addresses. Leave it off unless every receiver
+	// is on the server's own network.
+	AllowPrivateTargets bool
+}
+
+func NewService(repo ports.WebhookRepository) *Service {
+	s := &Service{
+		repo:         repo,
+		wake:         make(chan struct{}, 1),
+		MaxAttempts:  DefaultMaxAttempts,
+		BaseBackoff:  DefaultBaseBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		PollInterval: DefaultPollInterval,
+	}
+	s.Client = s.newClient()
+	return s
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", Wrap("failed to generate webhook id", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Subscribe creates a subscription. If secret is empty one is generated.
+// The returned secret is the only time it is shown.
+func (s *Service) Subscribe(ctx context.Context, rawURL string, eventTypes []string, secret string) (*domain.WebhookSubscription, string, error) {
+	if err := s.checkTarget(ctx, rawURL); err != nil {
+		return nil, "", err
+	}
+	if len(eventTypes) == 0 {
+		return nil, "", ErrEventTypesRequired
+	}
+	for _, eventType := range eventTypes {
+		if !slices.Contains(domain.EventTypes, eventType) {
+			return nil, "", Wrap(eventType, ErrUnknownEventType)
+		}
+	}
+	var err error
+	if secret == "" {
+		if secret, err = randomHex(32); err != nil {
+			return nil, "", err
+		}
+	} else if len(secret) < MinSecretLength {
+		return nil, "", ErrSecretTooShort
+	}
+	id, err := randomHex(6)
+	if err != nil {
+		return nil, "", err
+	}
+	sub := domain.WebhookSubscription{
+		ID:         id,
+		URL:        rawURL,
+		EventTypes: slices.Compact(slices.Sorted(slices.Values(eventTypes))),
+		Secret:     secret,
+		CreatedBy:  domain.Actor(ctx),
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.SaveSubscription(ctx, sub); err != nil {
+		return nil, "", Wrap("failed to save webhook subscription", err)
+	}
+	audit.Record(ctx, s.AuditLog, domain.AuditWebhookCreate, webhookTarget(sub.ID), nil, sub)
+	return &sub, secret, nil
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, Wrap("failed to list webhook subscriptions", err)
+	}
+	return subs, nil
+}
+
+// Unsubscribe deletes a subscription. Deliveries already queued for it are
+// kept in the log but will not be sent.
+func (s *Service) Unsubscribe(ctx context.Context, id string) error {
+	sub, err := s.repo.FindSubscription(ctx, id)
+	if err != nil {
+		return Wrap("failed to find webhook subscription", err)
+	}
+	if sub == nil {
+		return ErrSubscriptionNotFound
+	}
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		return Wrap("failed to delete webhook subscription", err)
+	}
+	audit.Record(ctx, s.AuditLog, domain.AuditWebhookDelete, webhookTarget(id), sub, nil)
+	return nil
+}
+
+// Send queues a delivery of event to every subscription that wants it and
+// wakes the worker. A subscription that already has a delivery of the
+// event is skipped, so the outbox relay can send an event again without
+// receivers getting it twice.
+func (s *Service) Send(ctx context.Context, event domain.Event) error {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return Wrap("failed to list webhook subscriptions", err)
+	}
+	queued, err := s.repo.ListDeliveries(ctx, domain.WebhookDeliveryFilter{EventID: event.ID})
+	if err != nil {
+		return Wrap("failed to list webhook deliveries", err)
+	}
+	already := make(map[string]bool, len(queued))
+	for _, delivery := range queued {
+		already[delivery.SubscriptionID] = true
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Wrap("failed to encode event", err)
+	}
+	var errs []error
+	for _, sub := range subs {
+		if !sub.Wants(event.Type) || already[sub.ID] {
+			continue
+		}
+		if err := s.queue(ctx, sub, event, payload); err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", sub.ID, err))
+			continue
+		}
+		already[sub.ID] = true
+	}
+	if len(already) > len(queued) {
+		s.notify()
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) queue(ctx context.Context, sub domain.WebhookSubscription, event domain.Event, payload []byte) error {
+	id, err := randomHex(12)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return s.repo.SaveDelivery(ctx, domain.WebhookDelivery{
+		ID:             id,
+		SubscriptionID: sub.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         domain.DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	})
+}
+
+// ListDeliveries is the delivery log, newest first. Limit defaults to
+// DefaultLimit.
+func (s *Service) ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit < 0 || filter.Limit > MaxLimit {
+		return nil, ErrInvalidLimit
+	}
+	switch filter.Status {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryDead:
+	default:
+		return nil, ErrInvalidStatus
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, filter)
+	if err != nil {
+		return nil, Wrap("failed to list webhook deliveries", err)
+	}
+	return deliveries, nil
+}
+
+// DeadLetters lists the deliveries that ran out of attempts, newest first.
+func (s *Service) DeadLetters(ctx context.Context, limit int) ([]domain.WebhookDelivery, error) {
+	return s.ListDeliveries(ctx, domain.WebhookDeliveryFilter{Status: domain.DeliveryDead, Limit: limit})
+}
+
+// Redeliver puts a dead delivery back in the queue with a fresh set of
+// attempts, e.g. once the receiver has been fixed.
+func (s *Service) Redeliver(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	delivery, err := s.repo.FindDelivery(ctx, id)
+	if err != nil {
+		return nil, Wrap("failed to find webhook delivery", err)
+	}
+	if delivery == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	if delivery.Status != domain.DeliveryDead {
+		return nil, ErrDeliveryNotDead
+	}
+	before := *delivery
+	delivery.Status = domain.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	if err := s.repo.UpdateDelivery(ctx, *delivery); err != nil {
+		return nil, Wrap("failed to requeue webhook delivery", err)
+	}
+	audit.Record(ctx, s.AuditLog, domain.AuditWebhookRedeliver, deliveryTarget(id), before, delivery)
+	s.notify()
+	return delivery, nil
+}
+
+func (s *Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func webhookTarget(id string) string {
+	return "webhook:" + id
+}
+
+func deliveryTarget(id string) string {
+	return "webhookdelivery:" + id
+}
@@ -0,0 +1,316 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"parkingSlotManagement/internals/adapters/repositories/inmemmory"
+	"parkingSlotManagement/internals/core/domain"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+const secret = "0123456789abcdef0123"
+
+// receiver is a local webhook endpoint that checks signatures and answers
+// with the queued statuses, then 200.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		if !Verify(secret, req.Header.Get(TimestampHeader), req.Header.Get(SignatureHeader), body, time.Minute) {
+			t.Errorf("Delivery %s has a bad signature", req.Header.Get(DeliveryHeader))
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.received = append(r.received, req)
+		r.bodies = append(r.bodies, body)
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.received)
+}
+
+func newTestService() (*Service, *inmemmory.WebhookInMemmory) {
+	repo := inmemmory.NewWebhookInMemmory()
+	service := NewService(repo)
+	// The receivers are httptest servers on 127.0.0.1.
+	service.AllowPrivateTargets = true
+	service.BaseBackoff = 0
+	service.MaxAttempts = 3
+	return service, repo
+}
+
+func closedEvent() domain.Event {
+	exit := time.Now()
+	ticket := domain.Ticket{TicketId: 42, VehicleNumber: "UP16AB1234", SlotId: 1, ExitTime: &exit, Fee: domain.NewMoney(5000, domain.CurrencyINR)}
+	return domain.NewTicketEvent(domain.EventTicketClosed, ticket, domain.Slot{SlotId: 1, SlotType: "car"})
+}
+
+func TestSubscribeValidates(t *testing.T) {
+	service, _ := newTestService()
+	for name, tc := range map[string]struct {
+		url, secret string
+		eventTypes  []string
+		err         error
+	}{
+		"relative url":  {"/hooks", "", []string{domain.EventTicketOpened}, ErrInvalidURL},
+		"ftp url":       {"ftp://example.com/hooks", "", []string{domain.EventTicketOpened}, ErrInvalidURL},
+		"no events":     {"https://example.com/hooks", "", nil, ErrEventTypesRequired},
+		"unknown event": {"https://example.com/hooks", "", []string{"vehicle.towed"}, ErrUnknownEventType},
+		"short secret":  {"https://example.com/hooks", "short", []string{domain.EventTicketOpened}, ErrSecretTooShort},
+	} {
+		_, _, err := service.Subscribe(ctx, tc.url, tc.eventTypes, tc.secret)
+		assert.ErrorIs(t, err, tc.err, name)
+	}
+
+	sub, generated, err := service.Subscribe(ctx, "https://example.com/hooks", []string{domain.EventTicketClosed, domain.EventTicketOpened, domain.EventTicketClosed}, "")
+	assert.NoError(t, err)
+	assert.Len(t, generated, 64)
+	assert.Equal(t, generated, sub.Secret)
+	assert.Equal(t, []string{domain.EventTicketClosed, domain.EventTicketOpened}, sub.EventTypes)
+}
+
+func TestSubscribeRefusesPrivateTargets(t *testing.T) {
+	service := NewService(inmemmory.NewWebhookInMemmory())
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hooks",
+		"http://localhost/hooks",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hooks",
+		"http://192.168.1.10/hooks",
+		"http://100.64.0.1/hooks",
+		"http://[::1]/hooks",
+		"http://[::ffff:127.0.0.1]/hooks",
+		"http://0.0.0.0/hooks",
+	} {
+		_, _, err := service.Subscribe(ctx, target, []string{domain.EventTicketClosed}, secret)
+		assert.ErrorIs(t, err, ErrForbiddenTarget, target)
+	}
+	_, _, err := service.Subscribe(ctx, "https://93.184.215.14/hooks", []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRefusesPrivateAddresses(t *testing.T) {
+	// Subscribed while private targets were allowed, as if the host had
+	// resolved to a public address then.
+	service, _ := newTestService()
+	receiver := newReceiver(t)
+	_, _, err := service.Subscribe(ctx, receiver.URL, []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+	assert.NoError(t, service.Send(ctx, closedEvent()))
+
+	service.AllowPrivateTargets = false
+	_, err = service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, receiver.count())
+	pending, err := service.ListDeliveries(ctx, domain.WebhookDeliveryFilter{Status: domain.DeliveryPending})
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Contains(t, pending[0].LastError, ErrForbiddenTarget.Error())
+	}
+}
+
+func TestSendDeliversSignedPayloads(t *testing.T) {
+	service, repo := newTestService()
+	billing := newReceiver(t)
+	crm := newReceiver(t)
+	_, _, err := service.Subscribe(ctx, billing.URL, []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+	_, _, err = service.Subscribe(ctx, crm.URL, []string{domain.EventTicketOpened}, secret)
+	assert.NoError(t, err)
+
+	event := closedEvent()
+	assert.NoError(t, service.Send(ctx, event))
+	delivered, err := service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 0, crm.count())
+	if !assert.Equal(t, 1, billing.count()) {
+		return
+	}
+
+	req := billing.received[0]
+	assert.Equal(t, event.ID, req.Header.Get(IDHeader))
+	assert.Equal(t, domain.EventTicketClosed, req.Header.Get(EventHeader))
+	var sent domain.Event
+	assert.NoError(t, json.Unmarshal(billing.bodies[0], &sent))
+	assert.Equal(t, int64(42), sent.TicketId)
+	assert.Equal(t, "50.00", sent.Fee.Decimal())
+
+	deliveries, _ := repo.ListDeliveries(ctx, domain.WebhookDeliveryFilter{})
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, domain.DeliveryDelivered, deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusOK, deliveries[0].LastStatusCode)
+		assert.NotNil(t, deliveries[0].DeliveredAt)
+	}
+}
+
+func TestSendQueuesEachEventOnce(t *testing.T) {
+	service, repo := newTestService()
+	receiver := newReceiver(t)
+	_, _, err := service.Subscribe(ctx, receiver.URL, []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+
+	event := closedEvent()
+	assert.NoError(t, service.Send(ctx, event))
+	assert.NoError(t, service.Send(ctx, event), "the outbox relay may send an event again")
+	_, err = service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, service.Send(ctx, event))
+
+	deliveries, _ := repo.ListDeliveries(ctx, domain.WebhookDeliveryFilter{EventID: event.ID})
+	assert.Len(t, deliveries, 1)
+	_, err = service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, receiver.count())
+}
+
+func TestFailedDeliveriesAreRetriedThenDead(t *testing.T) {
+	service, _ := newTestService()
+	flaky := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+	down := newReceiver(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	_, _, err := service.Subscribe(ctx, flaky.URL, []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+	_, _, err = service.Subscribe(ctx, down.URL, []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.Send(ctx, closedEvent()))
+	delivered := 0
+	for range 4 {
+		n, err := service.DeliverDue(ctx)
+		assert.NoError(t, err)
+		delivered += n
+	}
+	assert.Equal(t, 1, delivered, "the flaky receiver accepts the third attempt")
+	assert.Equal(t, 3, flaky.count())
+	assert.Equal(t, 3, down.count())
+
+	dead, err := service.DeadLetters(ctx, 0)
+	assert.NoError(t, err)
+	if !assert.Len(t, dead, 1) {
+		return
+	}
+	assert.Equal(t, 3, dead[0].Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, dead[0].LastStatusCode)
+	assert.Equal(t, "receiver answered 503", dead[0].LastError)
+
+	// The receiver has recovered; replay the dead letter.
+	requeued, err := service.Redeliver(ctx, dead[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DeliveryPending, requeued.Status)
+	delivered, err = service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, down.received[0].Header.Get(IDHeader), down.received[3].Header.Get(IDHeader), "a replay keeps the event ID")
+
+	_, err = service.Redeliver(ctx, dead[0].ID)
+	assert.ErrorIs(t, err, ErrDeliveryNotDead)
+	_, err = service.Redeliver(ctx, "missing")
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+}
+
+func TestBackoff(t *testing.T) {
+	service := NewService(inmemmory.NewWebhookInMemmory())
+	service.AllowPrivateTargets = true
+	service.BaseBackoff = 10 * time.Second
+	service.MaxBackoff = time.Minute
+	for attempts, want := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second, 4: time.Minute, 20: time.Minute} {
+		assert.Equal(t, want, service.backoff(attempts), "after %d attempts", attempts)
+	}
+
+	// A failed delivery waits out its backoff before the next attempt.
+	down := newReceiver(t, http.StatusInternalServerError)
+	_, _, err := service.Subscribe(ctx, down.URL, []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+	assert.NoError(t, service.Send(ctx, closedEvent()))
+	_, err = service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	_, err = service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, down.count())
+
+	pending, err := service.ListDeliveries(ctx, domain.WebhookDeliveryFilter{Status: domain.DeliveryPending})
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.WithinDuration(t, pending[0].LastAttemptAt.Add(10*time.Second), pending[0].NextAttemptAt, time.Millisecond)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	service, _ := newTestService()
+	receiver := newReceiver(t)
+	sub, _, err := service.Subscribe(ctx, receiver.URL, []string{domain.EventTicketClosed}, secret)
+	assert.NoError(t, err)
+	assert.NoError(t, service.Send(ctx, closedEvent()))
+
+	assert.NoError(t, service.Unsubscribe(ctx, sub.ID))
+	assert.ErrorIs(t, service.Unsubscribe(ctx, sub.ID), ErrSubscriptionNotFound)
+
+	_, err = service.DeliverDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, receiver.count())
+	dead, _ := service.DeadLetters(ctx, 0)
+	if assert.Len(t, dead, 1) {
+		assert.Equal(t, "subscription was deleted", dead[0].LastError)
+	}
+}
+
+func TestRunDeliversAsEventsArrive(t *testing.T) {
+	service, _ := newTestService()
+	service.PollInterval = time.Hour
+	receiver := newReceiver(t)
+	_, _, err := service.Subscribe(ctx, receiver.URL, []string{domain.EventSlotFreed}, secret)
+	assert.NoError(t, err)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		service.Run(runCtx)
+		close(done)
+	}()
+	assert.NoError(t, service.Send(ctx, domain.NewSlotEvent(domain.EventSlotFreed, domain.Slot{SlotId: 1, SlotType: "car"})))
+
+	assert.Eventually(t, func() bool { return receiver.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"e1"}`)
+	now := time.Now().Unix()
+	fresh := strconv.FormatInt(now, 10)
+	assert.True(t, Verify(secret, fresh, Sign(secret, fresh, body), body, time.Minute))
+	assert.False(t, Verify("another secret!!", fresh, Sign(secret, fresh, body), body, time.Minute))
+	assert.False(t, Verify(secret, fresh, Sign(secret, fresh, body), []byte(`{"id":"e2"}`), time.Minute))
+	stale := strconv.FormatInt(now-3600, 10)
+	assert.False(t, Verify(secret, stale, Sign(secret, stale, body), body, time.Minute))
+	assert.False(t, Verify(secret, "yesterday", Sign(secret, "yesterday", body), body, time.Minute))
+}
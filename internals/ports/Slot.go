@@ -1,12 +1,21 @@
 package ports
 
-import "parkingSlotManagement/internals/core/domain"
+import (
+	"context"
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// ErrSlotExists is returned by SaveSlot when a slot with the same ID is
+// already stored.
+var ErrSlotExists = errors.New("slot already exists")
 
 type SlotRepository interface {
-	SaveSlot(slot domain.Slot) error
-	UpdateSlot(slot *domain.Slot) error
-	ListAvailableSlots() ([]domain.Slot, error)
-	FindSlotByType(slottype string) ([]domain.Slot, error)
-	FindSlotTypebyID(SlotId int) (string, error)
-	FindSlotByID(SlotId int) (*domain.Slot, error)
+	SaveSlot(ctx context.Context, slot domain.Slot) error
+	UpdateSlot(ctx context.Context, slot *domain.Slot) error
+	ListSlots(ctx context.Context) ([]domain.Slot, error)
+	ListAvailableSlots(ctx context.Context) ([]domain.Slot, error)
+	FindSlotByType(ctx context.Context, slottype string) ([]domain.Slot, error)
+	FindSlotTypebyID(ctx context.Context, SlotId int) (string, error)
+	FindSlotByID(ctx context.Context, SlotId int) (*domain.Slot, error)
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type AdjustmentRepository interface {
+	SaveAdjustment(ctx context.Context, adjustment domain.FeeAdjustment) error
+	// UpdateAdjustmentFrom writes the adjustment's review and application
+	// fields while the stored status is still status. It reports whether it
+	// did.
+	UpdateAdjustmentFrom(ctx context.Context, adjustment domain.FeeAdjustment, status string) (bool, error)
+	FindAdjustmentByID(ctx context.Context, adjustmentid int64) (*domain.FeeAdjustment, error)
+	ListAdjustments(ctx context.Context, status string) ([]domain.FeeAdjustment, error)
+	ListAdjustmentsByTicket(ctx context.Context, ticketid int64) ([]domain.FeeAdjustment, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type APIKeyRepository interface {
+	SaveAPIKey(ctx context.Context, key domain.APIKey) error
+	UpdateAPIKey(ctx context.Context, key domain.APIKey) error
+	FindAPIKeyByID(ctx context.Context, id string) (*domain.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+}
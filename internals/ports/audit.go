@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// AuditLog is append-only: entries can be recorded and read back, never
+// changed or removed.
+type AuditLog interface {
+	Record(ctx context.Context, entry domain.AuditEntry) error
+	ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+}
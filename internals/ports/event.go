@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// EventPublisher is told about each change once it has been stored. It
+// is called on the request's goroutine, so it must return quickly and
+// leave slow work such as network calls to the background.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event)
+}
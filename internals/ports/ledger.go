@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type LedgerRepository interface {
+	SaveLedgerEntry(ctx context.Context, entry domain.LedgerEntry) error
+	ListLedgerEntries(ctx context.Context, vehiclenumber string) ([]domain.LedgerEntry, error)
+	GetBalance(ctx context.Context, vehiclenumber string) (domain.Money, error)
+	// LockBalance is GetBalance that also locks the vehicle's entries until
+	// the transaction ends, so that two settlements cannot both spend the
+	// same balance.
+	LockBalance(ctx context.Context, vehiclenumber string) (domain.Money, error)
+}
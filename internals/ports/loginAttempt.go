@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// LoginAttemptStore keeps failed-login counts per username and per client
+// address, for throttling and lockout.
+type LoginAttemptStore interface {
+	FindLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error)
+	SaveLoginAttempts(ctx context.Context, attempts domain.LoginAttempts) error
+	DeleteLoginAttempts(ctx context.Context, key string) error
+	ListLoginAttempts(ctx context.Context) ([]domain.LoginAttempts, error)
+}
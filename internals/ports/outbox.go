@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+)
+
+// Transactor runs fn in a transaction, committed if fn returns nil and
+// rolled back otherwise. Repositories given the context fn receives take
+// part in the transaction; a call made inside one joins it.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type OutboxRepository interface {
+	// AddEvents stores events to be relayed, in order, as part of the
+	// caller's transaction.
+	AddEvents(ctx context.Context, events ...domain.Event) error
+	// ListUnpublished returns up to limit messages neither published nor
+	// claimed by a relay at now, oldest first. Inside a transaction the
+	// rows stay locked to it until it ends, and rows another transaction
+	// holds are skipped.
+	ListUnpublished(ctx context.Context, limit int, now time.Time) ([]domain.OutboxMessage, error)
+	// Claim marks messages as being sent until the given time, so that
+	// other relays skip them without a transaction held open while they
+	// are sent. A zero time releases the claim.
+	Claim(ctx context.Context, ids []int64, until time.Time) error
+	MarkPublished(ctx context.Context, ids []int64, at time.Time) error
+	// DeletePublished removes messages published before the given time
+	// and returns how many went.
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// OutboxPublisher receives events relayed from the outbox. An error leaves
+// the event in the outbox to be sent again, so a publisher can see an
+// event more than once and its consumers should drop repeats by event ID.
+type OutboxPublisher interface {
+	Send(ctx context.Context, event domain.Event) error
+}
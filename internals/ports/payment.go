@@ -0,0 +1,10 @@
+package ports
+
+import "parkingSlotManagement/internals/core/domain"
+
+type PaymentGateway interface {
+	Authorise(req domain.PaymentRequest) (*domain.Payment, error)
+	Capture(reference string) (*domain.Payment, error)
+	Refund(reference string, amount domain.Money) (*domain.Payment, error)
+	Status(reference string) (*domain.Payment, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// TokenRevocationStore remembers token IDs that must no longer be accepted.
+// An ID only needs remembering until expiresAt, after which the token is
+// rejected as expired anyway. Revoke reports false if tokenID was already
+// revoked, so only one of two requests racing to use a token can win.
+type TokenRevocationStore interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error)
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
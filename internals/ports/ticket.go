@@ -1,9 +1,17 @@
 package ports
 
-import "parkingSlotManagement/internals/core/domain"
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+)
 
 type TicketRepository interface {
-	SaveTicket(ticket domain.Ticket) error
-	FindTicketByVehicleNumber(vehiclenumber string) (*domain.Ticket, error)
-	DeleteTicket(ticketid int64) error
+	SaveTicket(ctx context.Context, ticket domain.Ticket) error
+	FindTicketByVehicleNumber(ctx context.Context, vehiclenumber string) (*domain.Ticket, error)
+	FindTicketByID(ctx context.Context, ticketid int64) (*domain.Ticket, error)
+	ListClosedTickets(ctx context.Context, from, to time.Time) ([]domain.Ticket, error)
+	ListOpenTickets(ctx context.Context) ([]domain.Ticket, error)
+	CloseTicket(ctx context.Context, ticket domain.Ticket) error
+	DeleteTicket(ctx context.Context, ticketid int64) error
 }
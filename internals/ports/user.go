@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+type UserRepository interface {
+	SaveUser(ctx context.Context, user domain.User) error
+	UpdateUser(ctx context.Context, user domain.User) error
+	FindUserByID(ctx context.Context, id string) (*domain.User, error)
+	FindUserByUsername(ctx context.Context, username string) (*domain.User, error)
+	ListUsers(ctx context.Context) ([]domain.User, error)
+	DeleteUser(ctx context.Context, id string) error
+}
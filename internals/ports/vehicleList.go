@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"parkingSlotManagement/internals/core/domain"
+)
+
+// ErrVehicleListEntryNotFound is returned by DeleteEntry when the vehicle
+// is on neither list.
+var ErrVehicleListEntryNotFound = errors.New("vehicle list entry not found")
+
+type VehicleListRepository interface {
+	SaveEntry(ctx context.Context, entry domain.VehicleListEntry) error
+	FindEntry(ctx context.Context, vehiclenumber string) (*domain.VehicleListEntry, error)
+	ListEntries(ctx context.Context, listtype string) ([]domain.VehicleListEntry, error)
+	DeleteEntry(ctx context.Context, vehiclenumber string) error
+	SaveRejection(ctx context.Context, rejection domain.EntryRejection) error
+	ListRejections(ctx context.Context) ([]domain.EntryRejection, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"parkingSlotManagement/internals/core/domain"
+	"time"
+)
+
+type WebhookRepository interface {
+	SaveSubscription(ctx context.Context, sub domain.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id string) error
+	// FindSubscription returns nil, nil if there is no such subscription.
+	FindSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+
+	SaveDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	// FindDelivery returns nil, nil if there is no such delivery.
+	FindDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+	// ListDueDeliveries returns up to limit pending deliveries whose next
+	// attempt is due by now, oldest first.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+}